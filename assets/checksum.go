@@ -0,0 +1,161 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// versionedAssets are the data assets served over HTTP, which Version and
+// ModTime describe along with the prompts. Keys and certificates are left
+// out: rotating them must not change API validators, and /health publishes
+// these checksums.
+var versionedAssets = []string{TopTenAsset, ShakespertAsset}
+
+// assetChecksums describes the assets resolved for the current data
+// directory
+type assetChecksums struct {
+	checksums map[string]string
+	version   string
+	modTime   time.Time
+}
+
+var (
+	checksumsMu sync.Mutex
+	checksums   *assetChecksums
+)
+
+// Checksums returns the hex-encoded SHA-256 checksum of each asset, keyed
+// by its path relative to the assets directory when embedded and by its
+// file path when loaded from the data directory
+func Checksums() map[string]string {
+	current := computeChecksums()
+
+	result := make(map[string]string, len(current.checksums))
+	for name, sum := range current.checksums {
+		result[name] = sum
+	}
+	return result
+}
+
+// Version returns a digest over the assets, as resolved from the data
+// directory or the embedded copies. It changes whenever any of them changes
+// and is stable until the data directory is set again, since assets are
+// only read at startup.
+func Version() string {
+	return computeChecksums().version
+}
+
+// ModTime returns the time the assets last changed: the newest
+// modification time of the files loaded from the data directory and, for
+// embedded assets, of the running binary
+func ModTime() time.Time {
+	return computeChecksums().modTime
+}
+
+// resetChecksums discards the checksums, to compute them again for a new
+// data directory
+func resetChecksums() {
+	checksumsMu.Lock()
+	defer checksumsMu.Unlock()
+	checksums = nil
+}
+
+func computeChecksums() *assetChecksums {
+	checksumsMu.Lock()
+	defer checksumsMu.Unlock()
+	if checksums != nil {
+		return checksums
+	}
+
+	current := &assetChecksums{checksums: map[string]string{}}
+	for _, name := range versionedAssets {
+		asset, err := Resolve(name)
+		if err != nil {
+			continue
+		}
+		data, err := asset.ReadFile()
+		if err != nil {
+			continue
+		}
+		current.add(asset.checksumKey(), asset.Path, data)
+	}
+
+	if prompts, asset, err := Prompts(); err == nil {
+		fs.WalkDir(prompts, PromptsAsset, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(prompts, path)
+			if err != nil {
+				return err
+			}
+			if asset.Embedded() {
+				current.add(path, "", data)
+			} else {
+				file := filepath.Join(filepath.Dir(asset.Path), path)
+				current.add(file, file, data)
+			}
+			return nil
+		})
+	}
+
+	names := make([]string, 0, len(current.checksums))
+	for name := range current.checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(current.checksums[name]))
+		h.Write([]byte{0})
+	}
+	current.version = hex.EncodeToString(h.Sum(nil))
+	if current.modTime.IsZero() {
+		current.modTime = time.Now()
+	}
+
+	checksums = current
+	return checksums
+}
+
+// add records the checksum of data under key, and the modification time of
+// the file it was read from, or of the binary when path is empty
+func (c *assetChecksums) add(key, path string, data []byte) {
+	c.checksums[key] = sum(data)
+
+	if path == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		path = exe
+	}
+	if info, err := os.Stat(path); err == nil && info.ModTime().After(c.modTime) {
+		c.modTime = info.ModTime()
+	}
+}
+
+// checksumKey names the asset in Checksums
+func (a Asset) checksumKey() string {
+	if !a.Embedded() {
+		return a.Path
+	}
+	if a.Encrypted {
+		return "data/" + a.Name + ageSuffix
+	}
+	return "data/" + a.Name
+}
+
+func sum(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
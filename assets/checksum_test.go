@@ -0,0 +1,73 @@
+package assets_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/assets"
+)
+
+func TestChecksums(t *testing.T) {
+	t.Cleanup(func() { assets.SetDataDir("") })
+
+	checksum := func(data string) string {
+		h := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(h[:])
+	}
+
+	assets.SetDataDir("")
+	embedded := assets.Version()
+	require.Contains(t, assets.Checksums(), "data/topten.json.age")
+
+	t.Run("should cover overrides from the data directory", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "topten.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"lists":[]}`), 0o644))
+		modified := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		require.NoError(t, os.Chtimes(path, modified, modified))
+		assets.SetDataDir(dir)
+
+		checksums := assets.Checksums()
+		assert.Equal(t, checksum(`{"lists":[]}`), checksums[path])
+		assert.NotContains(t, checksums, "data/topten.json.age")
+		assert.Contains(t, checksums, "data/shakespert.db", "assets that are not overridden stay embedded")
+
+		version := assets.Version()
+		assert.NotEqual(t, embedded, version)
+		assert.True(t, assets.ModTime().Equal(modified), assets.ModTime())
+
+		// Replaced overrides are picked up at the next start
+		require.NoError(t, os.WriteFile(path, []byte(`{"lists":[{}]}`), 0o644))
+		assets.SetDataDir(dir)
+		assert.NotEqual(t, version, assets.Version())
+		assert.Equal(t, checksum(`{"lists":[{}]}`), assets.Checksums()[path])
+	})
+
+	t.Run("should ignore the host key", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "hostkey.age"), []byte("rotated"), 0o600))
+		assets.SetDataDir(dir)
+
+		assert.Equal(t, embedded, assets.Version())
+		for name := range assets.Checksums() {
+			assert.NotContains(t, name, "hostkey")
+		}
+	})
+
+	t.Run("should cover encrypted overrides", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "shakespert.db.age")
+		require.NoError(t, os.WriteFile(path, []byte("age-encrypted"), 0o644))
+		assets.SetDataDir(dir)
+
+		assert.Equal(t, checksum("age-encrypted"), assets.Checksums()[path])
+		assert.NotEqual(t, embedded, assets.Version())
+	})
+}
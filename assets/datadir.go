@@ -0,0 +1,176 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Names of the data assets that can be overridden from the data directory
+const (
+	TopTenAsset         = "topten.json"
+	HostKeyAsset        = "hostkey"
+	KeysAsset           = "keys.json"
+	AuthorizedKeysAsset = "authorized_keys"
+	TLSCertAsset        = "tls.crt"
+	TLSKeyAsset         = "tls.key"
+	ShakespertAsset     = "shakespert.db"
+	PromptsAsset        = "prompts"
+)
+
+// SourceEmbedded is reported as the source of compiled-in assets
+const SourceEmbedded = "embedded"
+
+// ageSuffix marks an age-encrypted file in the data directory
+const ageSuffix = ".age"
+
+// embeddedAsset describes the compiled-in copy of an asset
+type embeddedAsset struct {
+	encrypted bool
+	data      func() []byte
+}
+
+var embeddedAssets = map[string]embeddedAsset{
+	TopTenAsset:         {encrypted: true, data: GetEmbeddedTopTenData},
+	HostKeyAsset:        {encrypted: true, data: GetEmbeddedSSHKey},
+	KeysAsset:           {encrypted: true, data: GetEmbeddedKeys},
+	AuthorizedKeysAsset: {encrypted: true, data: GetEmbeddedAuthorizedKeys},
+	TLSCertAsset:        {encrypted: true, data: GetEmbeddedTLSCert},
+	TLSKeyAsset:         {encrypted: true, data: GetEmbeddedTLSKey},
+	ShakespertAsset:     {data: GetEmbeddedShakespertDB},
+}
+
+var (
+	dataDirMu sync.RWMutex
+	dataDir   string
+)
+
+// SetDataDir sets the directory searched for asset overrides. An empty dir
+// restores the compiled-in assets.
+func SetDataDir(dir string) {
+	dataDirMu.Lock()
+	dataDir = dir
+	dataDirMu.Unlock()
+	resetChecksums()
+}
+
+// DataDir returns the directory searched for asset overrides
+func DataDir() string {
+	dataDirMu.RLock()
+	defer dataDirMu.RUnlock()
+	return dataDir
+}
+
+// Asset is a data file resolved either from the data directory or from the
+// compiled-in copy
+type Asset struct {
+	Name      string
+	Path      string // file on disk, empty when embedded
+	Encrypted bool   // contents are age-encrypted
+}
+
+// Embedded reports whether the asset comes from the compiled-in copy
+func (a Asset) Embedded() bool {
+	return a.Path == ""
+}
+
+// Source returns the file path the asset was loaded from, or "embedded"
+func (a Asset) Source() string {
+	if a.Embedded() {
+		return SourceEmbedded
+	}
+	return a.Path
+}
+
+// ReadFile returns the raw asset contents, still encrypted when Encrypted
+func (a Asset) ReadFile() ([]byte, error) {
+	if a.Embedded() {
+		return embeddedAssets[a.Name].data(), nil
+	}
+
+	data, err := os.ReadFile(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", a.Path, err)
+	}
+	return data, nil
+}
+
+// Resolve locates the named data asset. When a data directory is set, a
+// plain <name> file is preferred over an encrypted <name>.age file; if
+// neither exists the compiled-in copy is used.
+func Resolve(name string) (Asset, error) {
+	embedded, ok := embeddedAssets[name]
+	if !ok {
+		return Asset{}, fmt.Errorf("unknown asset %q", name)
+	}
+
+	if dir := DataDir(); dir != "" {
+		for _, candidate := range []Asset{
+			{Name: name, Path: filepath.Join(dir, name)},
+			{Name: name, Path: filepath.Join(dir, name+ageSuffix), Encrypted: true},
+		} {
+			found, err := isFile(candidate.Path)
+			if err != nil {
+				return Asset{}, err
+			}
+			if found {
+				return candidate, nil
+			}
+		}
+	}
+
+	return Asset{Name: name, Encrypted: embedded.encrypted}, nil
+}
+
+// Prompts returns the filesystem holding the prompts directory. A prompts
+// directory inside the data directory replaces the compiled-in prompts.
+func Prompts() (fs.FS, Asset, error) {
+	if dir := DataDir(); dir != "" {
+		path := filepath.Join(dir, PromptsAsset)
+		info, err := os.Stat(path)
+		switch {
+		case err == nil && info.IsDir():
+			return os.DirFS(dir), Asset{Name: PromptsAsset, Path: path}, nil
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return nil, Asset{}, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+	}
+
+	return promptFiles, Asset{Name: PromptsAsset}, nil
+}
+
+// Sources resolves every data asset, in a stable order, for reporting
+func Sources() ([]Asset, error) {
+	var sources []Asset
+	for _, name := range []string{TopTenAsset, HostKeyAsset, KeysAsset, AuthorizedKeysAsset, TLSCertAsset, TLSKeyAsset, ShakespertAsset} {
+		asset, err := Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, asset)
+	}
+
+	_, prompts, err := Prompts()
+	if err != nil {
+		return nil, err
+	}
+	return append(sources, prompts), nil
+}
+
+// isFile reports whether path exists and is a regular file
+func isFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return false, fmt.Errorf("%s is a directory", path)
+	}
+	return true, nil
+}
@@ -0,0 +1,120 @@
+package assets_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/assets"
+)
+
+func TestResolve(t *testing.T) {
+	useDataDir := func(t *testing.T, dir string) {
+		t.Helper()
+		assets.SetDataDir(dir)
+		t.Cleanup(func() { assets.SetDataDir("") })
+	}
+
+	t.Run("should use embedded assets without a data directory", func(t *testing.T) {
+		useDataDir(t, "")
+
+		asset, err := assets.Resolve(assets.TopTenAsset)
+		require.NoError(t, err)
+
+		assert.True(t, asset.Embedded())
+		assert.True(t, asset.Encrypted)
+		assert.Equal(t, assets.SourceEmbedded, asset.Source())
+	})
+
+	t.Run("should fall back to embedded assets missing from the data directory", func(t *testing.T) {
+		useDataDir(t, t.TempDir())
+
+		asset, err := assets.Resolve(assets.ShakespertAsset)
+		require.NoError(t, err)
+
+		assert.True(t, asset.Embedded())
+		assert.False(t, asset.Encrypted)
+	})
+
+	t.Run("should load a plain file from the data directory", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "topten.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"lists":[]}`), 0o644))
+		useDataDir(t, dir)
+
+		asset, err := assets.Resolve(assets.TopTenAsset)
+		require.NoError(t, err)
+
+		assert.Equal(t, path, asset.Source())
+		assert.False(t, asset.Encrypted)
+
+		data, err := asset.ReadFile()
+		require.NoError(t, err)
+		assert.Equal(t, `{"lists":[]}`, string(data))
+	})
+
+	t.Run("should load an encrypted file from the data directory", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "hostkey.age")
+		require.NoError(t, os.WriteFile(path, []byte("encrypted"), 0o600))
+		useDataDir(t, dir)
+
+		asset, err := assets.Resolve(assets.HostKeyAsset)
+		require.NoError(t, err)
+
+		assert.Equal(t, path, asset.Source())
+		assert.True(t, asset.Encrypted)
+	})
+
+	t.Run("should prefer the plain file over the encrypted one", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "topten.json"), []byte("{}"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "topten.json.age"), []byte("encrypted"), 0o644))
+		useDataDir(t, dir)
+
+		asset, err := assets.Resolve(assets.TopTenAsset)
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(dir, "topten.json"), asset.Path)
+		assert.False(t, asset.Encrypted)
+	})
+
+	t.Run("should reject unknown assets", func(t *testing.T) {
+		_, err := assets.Resolve("missing.json")
+		assert.Error(t, err)
+	})
+}
+
+func TestPrompts(t *testing.T) {
+	t.Run("should use a prompts directory from the data directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "prompts"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "prompts", "custom.toml"), []byte(`name = "custom"`), 0o644))
+		assets.SetDataDir(dir)
+		t.Cleanup(func() { assets.SetDataDir("") })
+
+		fsys, asset, err := assets.Prompts()
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(dir, "prompts"), asset.Source())
+		_, err = fsys.Open("prompts/custom.toml")
+		assert.NoError(t, err)
+	})
+}
+
+func TestSources(t *testing.T) {
+	t.Run("should report every asset source", func(t *testing.T) {
+		sources, err := assets.Sources()
+		require.NoError(t, err)
+
+		names := make([]string, len(sources))
+		for i, asset := range sources {
+			names[i] = asset.Name
+			assert.Equal(t, assets.SourceEmbedded, asset.Source())
+		}
+		assert.Equal(t, []string{"topten.json", "hostkey", "keys.json", "authorized_keys", "tls.crt", "tls.key", "shakespert.db", "prompts"}, names)
+	})
+}
@@ -2,16 +2,33 @@ package assets
 
 import (
 	"embed"
+	"io/fs"
 )
 
+// ShakespertDBPath is the path of the Shakespeare database within the
+// filesystem returned by GetEmbeddedShakespertFS
+const ShakespertDBPath = "data/shakespert.db"
+
 //go:embed data/topten.json.age
 var topTenData []byte
 
 //go:embed data/hostkey.age
 var sshHostKey []byte
 
+//go:embed data/keys.json.age
+var apiKeys []byte
+
+//go:embed data/authorized_keys.age
+var authorizedKeys []byte
+
+//go:embed data/tls.crt.age
+var tlsCert []byte
+
+//go:embed data/tls.key.age
+var tlsKey []byte
+
 //go:embed data/shakespert.db
-var shakespertDB []byte
+var shakespertFiles embed.FS
 
 //go:embed prompts
 var promptFiles embed.FS
@@ -26,9 +43,41 @@ func GetEmbeddedSSHKey() []byte {
 	return sshHostKey
 }
 
-// GetEmbeddedShakespertDB returns the embedded Shakespeare database
+// GetEmbeddedKeys returns the embedded encrypted API keys file. It is empty
+// when no keys have been created.
+func GetEmbeddedKeys() []byte {
+	return apiKeys
+}
+
+// GetEmbeddedAuthorizedKeys returns the embedded encrypted SSH
+// authorized_keys file. It is empty unless an allowlist has been embedded.
+func GetEmbeddedAuthorizedKeys() []byte {
+	return authorizedKeys
+}
+
+// GetEmbeddedTLSCert returns the embedded encrypted TLS certificate chain.
+// It is empty unless a certificate has been embedded.
+func GetEmbeddedTLSCert() []byte {
+	return tlsCert
+}
+
+// GetEmbeddedTLSKey returns the embedded encrypted TLS private key. It is
+// empty unless a certificate has been embedded.
+func GetEmbeddedTLSKey() []byte {
+	return tlsKey
+}
+
+// GetEmbeddedShakespertDB returns a copy of the embedded Shakespeare database
 func GetEmbeddedShakespertDB() []byte {
-	return shakespertDB
+	data, _ := shakespertFiles.ReadFile(ShakespertDBPath)
+	return data
+}
+
+// GetEmbeddedShakespertFS returns a filesystem containing the embedded
+// Shakespeare database at ShakespertDBPath. SQLite can read it in place
+// through a read-only VFS without copying it to disk.
+func GetEmbeddedShakespertFS() fs.FS {
+	return shakespertFiles
 }
 
 // GetEmbeddedPrompts returns the embedded prompts filesystem
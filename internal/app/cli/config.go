@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/config"
+)
+
+// defaults supplies the default values shown in flag help
+var defaults = config.Default()
+
+// appConfig is the configuration loaded by the root command's Before hook
+var appConfig = config.Default()
+
+// globalFlagKeys maps root command flags to the config keys they override
+var globalFlagKeys = map[string]string{
+	"log-format": "log.format",
+	"log-level":  "log.level",
+	"data-dir":   "data_dir",
+}
+
+var configCmd = &cli.Command{
+	Name:  "config",
+	Usage: "Inspect the effective configuration",
+	Description: `Configuration is layered: built-in defaults, then the TOML file given by
+--config (or PROSPERO_CONFIG), then PROSPERO_* environment variables, then
+command-line flags. Each key maps to an environment variable by upper-casing
+it and replacing dots with underscores, e.g. http.request_timeout is
+PROSPERO_HTTP_REQUEST_TIMEOUT.`,
+	Subcommands: []*cli.Command{
+		{
+			Name:        "print",
+			Usage:       "Print the effective configuration as TOML",
+			Description: `Print the configuration after applying the config file, environment variables and global flags.`,
+			Action: func(c *cli.Context) error {
+				return appConfig.Write(os.Stdout)
+			},
+		},
+		{
+			Name:        "validate",
+			Usage:       "Validate the effective configuration",
+			Description: `Check the configuration for invalid values and exit non-zero if any are found.`,
+			Action: func(c *cli.Context) error {
+				if err := appConfig.Validate(); err != nil {
+					return fmt.Errorf("invalid configuration:\n%w", err)
+				}
+				fmt.Println("✓ Configuration is valid")
+				return nil
+			},
+		},
+	},
+}
+
+// loadConfig builds the configuration from the config file, environment and
+// global flags
+func loadConfig(c *cli.Context) (*config.Config, error) {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	if err := applyFlags(c, cfg, globalFlagKeys); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyFlags copies explicitly set flags onto cfg, so flags override the
+// config file and environment while unset flags leave them alone
+func applyFlags(c *cli.Context, cfg *config.Config, flagKeys map[string]string) error {
+	for flag, key := range flagKeys {
+		if !c.IsSet(flag) {
+			continue
+		}
+
+		var value string
+		switch v := c.Value(flag).(type) {
+		case []string:
+			value = strings.Join(v, ",")
+		default:
+			value = fmt.Sprint(v)
+		}
+
+		if err := cfg.Set(key, value); err != nil {
+			return fmt.Errorf("--%s: %w", flag, err)
+		}
+	}
+	return nil
+}
@@ -3,10 +3,15 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
+	"prospero/internal/auth"
+	"prospero/internal/config"
 	"prospero/internal/features/dev"
+	"prospero/internal/hostkey"
 )
 
 var devCmd = &cli.Command{
@@ -17,7 +22,8 @@ var devCmd = &cli.Command{
 This command provides tools for:
 - Extracting embedded encrypted and compressed data for local development
 - Packing modified data back into embedded format
-- Safely rotating encryption keys`,
+- Safely rotating encryption keys
+- Generating and rotating the SSH host keys`,
 	Subcommands: []*cli.Command{
 		{
 			Name:      "extract",
@@ -53,7 +59,9 @@ Extracted files are placed in the current directory and are gitignored.`,
 			Description: `Pack modified data back into embedded format for inclusion in builds.
 
 Types:
-  shakespert - Compress shakespert.db or shakespert.sql into shakespert.sql.gz
+  shakespert      - Compress shakespert.db or shakespert.sql into shakespert.sql.gz
+  tls             - Encrypt tls.crt and tls.key into tls.crt.age and tls.key.age
+  authorized-keys - Encrypt authorized_keys into authorized_keys.age
 
 The pack command will automatically detect input files in the current directory
 and output compressed files to assets/data/ for embedding.`,
@@ -109,6 +117,185 @@ After successful rotation, you can remove PREVIOUS_AGE_ENCRYPTION_PASSWORD.`,
 			},
 			Action: runRotateKey,
 		},
+		{
+			Name:  "hash-password",
+			Usage: "Hash a password for an SSH password user",
+			Description: `Print the bcrypt hash of a password, to be set as
+ssh.auth.users.<name>.password_hash in the config file. The password is
+prompted for without echo, or read from stdin when it is not a terminal.`,
+			Action: runHashPassword,
+		},
+		{
+			Name:  "keys",
+			Usage: "Manage API keys",
+			Description: `Manage the API keys accepted by the HTTP API and /mcp.
+
+Keys are stored as SHA-256 hashes in assets/data/keys.json.age, encrypted with
+AGE_ENCRYPTION_PASSWORD. Rebuild the binary to embed changes, or serve the
+directory with --data-dir.
+
+Scopes: read:shakespert, read:topten, mcp, admin (grants every scope)`,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "dir",
+					Value: "assets/data",
+					Usage: "Directory holding keys.json.age",
+				},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:      "create",
+					Usage:     "Create an API key and print its token",
+					ArgsUsage: "<name>",
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:     "scope",
+							Aliases:  []string{"s"},
+							Usage:    "Scope granted to the key (repeatable)",
+							Required: true,
+						},
+						&cli.IntFlag{
+							Name:  "rate-limit",
+							Usage: "Requests per --rate-period for this key (default: the configured budgets)",
+						},
+						&cli.DurationFlag{
+							Name:  "rate-period",
+							Value: time.Minute,
+							Usage: "Period of --rate-limit",
+						},
+						&cli.IntFlag{
+							Name:  "rate-burst",
+							Usage: "Burst size of --rate-limit (default: --rate-limit)",
+						},
+					},
+					Action: runKeysCreate,
+				},
+				{
+					Name:   "list",
+					Usage:  "List API keys",
+					Action: runKeysList,
+				},
+				{
+					Name:      "revoke",
+					Usage:     "Revoke an API key",
+					ArgsUsage: "<id>",
+					Action:    runKeysRevoke,
+				},
+			},
+		},
+		{
+			Name:  "hostkey",
+			Usage: "Manage the SSH host keys",
+			Description: `Manage the keys the SSH server identifies itself with.
+
+Keys are stored as concatenated OpenSSH private keys, one per algorithm, in
+assets/data/hostkey.age, encrypted with AGE_ENCRYPTION_PASSWORD. The server
+presents all of them and clients pick the algorithm they prefer. Rebuild the
+binary to embed changes, or serve the directory with --data-dir.
+
+Algorithms: ` + strings.Join(hostkey.Algorithms, ", "),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "dir",
+					Value: "assets/data",
+					Usage: "Directory holding hostkey.age",
+				},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  "generate",
+					Usage: "Generate host keys",
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:    "type",
+							Aliases: []string{"t"},
+							Usage:   "Key algorithm to generate (repeatable, default: all)",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Overwrite existing host keys",
+						},
+					},
+					Action: runHostKeyGenerate,
+				},
+				{
+					Name:  "rotate",
+					Usage: "Replace the host keys, keeping a backup of the previous ones",
+					Description: `Generate new host keys of the algorithms currently in use, or of --type,
+and keep the previous keys in hostkey.age.bak. Clients that trusted the
+previous keys will refuse the new ones until their known_hosts is updated.`,
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:    "type",
+							Aliases: []string{"t"},
+							Usage:   "Key algorithm to generate (repeatable, default: those in use)",
+						},
+					},
+					Action: runHostKeyRotate,
+				},
+				{
+					Name:  "fingerprint",
+					Usage: "Print the host key fingerprints and known_hosts lines",
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:  "host",
+							Value: cli.NewStringSlice("localhost"),
+							Usage: "Host name or address clients connect to (repeatable)",
+						},
+						&cli.StringFlag{
+							Name:    "port",
+							Value:   "2222",
+							Usage:   "SSH port clients connect to",
+							EnvVars: []string{config.EnvName("ssh.port")},
+						},
+					},
+					Action: runHostKeyFingerprint,
+				},
+			},
+		},
+		{
+			Name:  "sessions",
+			Usage: "Inspect the SSH session audit log",
+			Description: `Inspect the sessions recorded by a server started with ssh.audit.dir set.
+
+Every session is listed with its remote address, key fingerprint, command,
+exit status, duration and bytes sent. Interactive sessions recorded with
+ssh.audit.record can be replayed in the terminal; the .cast files also play
+in asciinema.`,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "dir",
+					Usage:    "Audit directory of the server (ssh.audit.dir)",
+					EnvVars:  []string{config.EnvName("ssh.audit.dir")},
+					Required: true,
+				},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List recorded sessions, oldest first",
+					Action: runSessionsList,
+				},
+				{
+					Name:      "replay",
+					Usage:     "Replay the recording of an interactive session",
+					ArgsUsage: "<id>",
+					Flags: []cli.Flag{
+						&cli.Float64Flag{
+							Name:  "speed",
+							Value: 1,
+							Usage: "Playback speed multiplier",
+						},
+						&cli.DurationFlag{
+							Name:  "idle-limit",
+							Value: 2 * time.Second,
+							Usage: "Longest pause between two outputs (0 keeps every pause)",
+						},
+					},
+					Action: runSessionsReplay,
+				},
+			},
+		},
 	},
 }
 
@@ -172,8 +359,14 @@ func runPack(c *cli.Context) error {
 	case "shakespert":
 		return dev.PackShakespert(opts)
 
+	case "tls":
+		return dev.PackTLS(opts)
+
+	case "authorized-keys":
+		return dev.PackAuthorizedKeys(opts)
+
 	default:
-		return fmt.Errorf("unknown pack type: %s\nValid types: shakespert", packType)
+		return fmt.Errorf("unknown pack type: %s\nValid types: shakespert, tls, authorized-keys", packType)
 	}
 }
 
@@ -194,3 +387,97 @@ func runRotateKey(c *cli.Context) error {
 
 	return dev.RotateKeys(c.Context, opts)
 }
+
+func keysOptions(c *cli.Context) dev.KeysOptions {
+	opts := dev.DefaultKeysOptions()
+	if dir := c.String("dir"); dir != "" {
+		opts.Dir = dir
+	}
+	return opts
+}
+
+func runKeysCreate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one key name argument is required")
+	}
+
+	var limit *auth.RateLimit
+	if requests := c.Int("rate-limit"); requests > 0 {
+		limit = &auth.RateLimit{
+			Requests: requests,
+			Period:   c.Duration("rate-period").String(),
+			Burst:    c.Int("rate-burst"),
+		}
+	}
+
+	return dev.CreateKey(c.Context, keysOptions(c), c.Args().Get(0), c.StringSlice("scope"), limit)
+}
+
+func runHashPassword(c *cli.Context) error {
+	return dev.HashSSHPassword()
+}
+
+func runKeysList(c *cli.Context) error {
+	return dev.ListKeys(c.Context, keysOptions(c))
+}
+
+func runKeysRevoke(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one key ID argument is required")
+	}
+	return dev.RevokeKey(c.Context, keysOptions(c), c.Args().Get(0))
+}
+
+func hostKeyOptions(c *cli.Context) dev.HostKeyOptions {
+	opts := dev.DefaultHostKeyOptions()
+	if dir := c.String("dir"); dir != "" {
+		opts.Dir = dir
+	}
+	if c.IsSet("type") {
+		opts.Algorithms = c.StringSlice("type")
+	}
+	if c.IsSet("host") {
+		opts.Hosts = c.StringSlice("host")
+	}
+	if port := c.String("port"); port != "" {
+		opts.Port = port
+	}
+	opts.Force = c.Bool("force")
+	return opts
+}
+
+func runHostKeyGenerate(c *cli.Context) error {
+	return dev.GenerateHostKeys(hostKeyOptions(c))
+}
+
+func runHostKeyRotate(c *cli.Context) error {
+	opts := hostKeyOptions(c)
+	if !c.IsSet("type") {
+		// Keep the algorithms in use
+		opts.Algorithms = nil
+	}
+	return dev.RotateHostKeys(c.Context, opts)
+}
+
+func runHostKeyFingerprint(c *cli.Context) error {
+	return dev.PrintHostKeys(c.Context, hostKeyOptions(c))
+}
+
+func sessionsOptions(c *cli.Context) dev.SessionsOptions {
+	return dev.SessionsOptions{
+		Dir:       c.String("dir"),
+		Speed:     c.Float64("speed"),
+		IdleLimit: c.Duration("idle-limit"),
+	}
+}
+
+func runSessionsList(c *cli.Context) error {
+	return dev.ListSessions(sessionsOptions(c))
+}
+
+func runSessionsReplay(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("exactly one session ID argument is required")
+	}
+	return dev.ReplaySession(c.Context, sessionsOptions(c), c.Args().Get(0))
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"prospero/assets"
+	"prospero/internal/version"
+)
+
+var infoCmd = &cli.Command{
+	Name:        "info",
+	Usage:       "Show build and data asset information",
+	Description: `Show the build version and where each data asset is loaded from: the --data-dir directory or the copy embedded in the binary.`,
+	Action: func(c *cli.Context) error {
+		return showInfo()
+	},
+}
+
+func showInfo() error {
+	sources, err := assets.Sources()
+	if err != nil {
+		return fmt.Errorf("failed to resolve assets: %w", err)
+	}
+
+	build := version.Get()
+	fmt.Printf("Version:  %s\n", build.Version)
+	if build.Revision != "" {
+		fmt.Printf("Revision: %s\n", build.Revision)
+	}
+	fmt.Printf("Go:       %s\n", build.GoVersion)
+
+	dataDir := assets.DataDir()
+	if dataDir == "" {
+		dataDir = "(not set)"
+	}
+	fmt.Printf("Data dir: %s\n", dataDir)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ASSET\tENCRYPTED\tSOURCE")
+	fmt.Fprintln(w, "-----\t---------\t------")
+	for _, asset := range sources {
+		encrypted := "no"
+		if asset.Encrypted {
+			encrypted = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", asset.Name, encrypted, asset.Source())
+	}
+	return w.Flush()
+}
@@ -1,59 +1,41 @@
 package cli
 
 import (
-	"context"
-	"fmt"
-	"os"
+	"log/slog"
 
 	"github.com/urfave/cli/v2"
 
-	"prospero/assets"
-	"prospero/internal/mcp"
+	"prospero/internal/app/modules"
+	"prospero/internal/app/server"
 )
 
 var mcpCmd = &cli.Command{
 	Name:        "mcp",
 	Usage:       "Start the MCP (Model Context Protocol) server",
-	Description: `Start the MCP server with stdio transport. The server exposes prompts defined in assets/prompts/*.toml files.`,
+	Description: `Start the MCP server with stdio transport. The server exposes prompts defined in assets/prompts/*.toml files and the tools provided by each feature.`,
 	Action: func(c *cli.Context) error {
 		ctx := c.Context
 
-		// Create MCP server
-		server := mcp.NewServer("prospero", "1.0.0")
-
-		// Load prompts from TOML files
-		promptFS := assets.GetEmbeddedPrompts()
-		definitions, err := mcp.LoadPromptsFromTOML(promptFS)
-		if err != nil {
-			return fmt.Errorf("failed to load prompts: %w", err)
+		// Open the features so their tools can answer calls
+		registry := modules.Default()
+		if err := registry.Open(ctx); err != nil {
+			return err
 		}
+		defer registry.Close()
 
-		// Register prompts with handlers
-		for _, def := range definitions {
-			prompt := def.ToPrompt()
-
-			// Use content-based handler for prompts with content, placeholder otherwise
-			var handler mcp.PromptHandler
-			if def.Content != "" {
-				handler = def.CreateHandler()
-			} else {
-				// Placeholder handler for prompts without content
-				handler = func(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
-					return nil, fmt.Errorf("handler not implemented for prompt: %s", prompt.Name)
-				}
-			}
-
-			server.RegisterPrompt(prompt, handler)
+		// Create the MCP server with the prompts from TOML files and the
+		// feature tools
+		mcpServer, err := server.NewMCPServer(appConfig.MCP, registry)
+		if err != nil {
+			return err
 		}
 
-		// Log loaded prompts to stderr
-		fmt.Fprintf(os.Stderr, "Loaded %d prompts:\n", len(definitions))
-		for _, def := range definitions {
-			fmt.Fprintf(os.Stderr, "  - %s: %s\n", def.Name, def.Description)
-		}
+		// Log loaded prompts and tools (the logger writes to stderr, stdout
+		// is the transport)
+		slog.Info("loaded mcp prompts and tools", "prompts", mcpServer.PromptCount(), "tools", mcpServer.ToolCount())
 
 		// Start the server
-		fmt.Fprintln(os.Stderr, "MCP server starting on stdio...")
-		return server.Run(ctx)
+		slog.Info("mcp server starting on stdio")
+		return mcpServer.Run(ctx)
 	},
 }
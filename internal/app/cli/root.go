@@ -5,6 +5,12 @@ import (
 	"os"
 
 	"github.com/urfave/cli/v2"
+
+	"prospero/assets"
+	"prospero/internal/app/modules"
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/logging"
 )
 
 var app = &cli.App{
@@ -16,19 +22,63 @@ It provides fun utilities like David Letterman Top 10 lists and serves them
 via both HTTP and SSH interfaces when running in server mode.
 
 Perfect for deployment on edge platforms like bunny.net Magic Containers.`,
-	Commands: []*cli.Command{
-		topTenCmd,
-		shakespertCmd,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "Path to a TOML configuration file",
+			EnvVars: []string{"PROSPERO_CONFIG"},
+		},
+		&cli.StringFlag{
+			Name:    "log-format",
+			Value:   defaults.Log.Format,
+			Usage:   "Log output format (text or json)",
+			EnvVars: []string{config.EnvName("log.format")},
+		},
+		&cli.StringFlag{
+			Name:    "log-level",
+			Value:   defaults.Log.Level,
+			Usage:   "Minimum log level (debug, info, warn or error)",
+			EnvVars: []string{config.EnvName("log.level")},
+		},
+		&cli.StringFlag{
+			Name:    "data-dir",
+			Usage:   "Load data assets from this directory when present (plain or .age), falling back to the embedded copies",
+			EnvVars: []string{config.EnvName("data_dir")},
+		},
+	},
+	Before: func(c *cli.Context) error {
+		cfg, err := loadConfig(c)
+		if err != nil {
+			return err
+		}
+		appConfig = cfg
+
+		assets.SetDataDir(cfg.DataDir)
+		return logging.Setup(os.Stderr, logOptions(cfg))
+	},
+	// Feature commands come first, followed by the built-in ones
+	Commands: append(modules.Default().Commands(),
 		serveCmd,
 		devCmd,
 		mcpCmd,
-	},
+		infoCmd,
+		configCmd,
+	),
 }
 
-// Execute runs the CLI application
+// Execute runs the CLI application. Failed commands exit with the status
+// of their error class, as they do over SSH.
 func Execute() {
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(features.AsCommandError(err).Status)
+	}
+}
+
+// logOptions returns the logger settings from cfg
+func logOptions(cfg *config.Config) logging.Options {
+	return logging.Options{
+		Format: cfg.Log.Format,
+		Level:  cfg.Log.Level,
 	}
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,8 +11,38 @@ import (
 	"golang.org/x/term"
 
 	"prospero/internal/app/server"
+	"prospero/internal/config"
+	"prospero/internal/logging"
 )
 
+// serveFlagKeys maps serve flags to the config keys they override
+var serveFlagKeys = map[string]string{
+	"host":      "server.host",
+	"http-port": "http.port",
+	"ssh-port":  "ssh.port",
+	"force-ssh": "ssh.force",
+
+	"ssh-anonymous":           "ssh.auth.anonymous",
+	"ssh-authorized-keys":     "ssh.auth.authorized_keys",
+	"ssh-keys-dir":            "ssh.auth.keys_dir",
+	"ssh-audit-dir":           "ssh.audit.dir",
+	"ssh-record":              "ssh.audit.record",
+	"ssh-idle-timeout":        "ssh.limits.idle_timeout",
+	"ssh-max-duration":        "ssh.limits.max_duration",
+	"ssh-max-sessions":        "ssh.limits.max_sessions",
+	"ssh-max-sessions-per-ip": "ssh.limits.max_sessions_per_ip",
+	"ssh-max-bytes-out":       "ssh.limits.max_bytes_out",
+	"metrics-addr":            "metrics.addr",
+	"drain-delay":             "server.drain_delay",
+
+	"tls-cert":          "http.tls.cert_file",
+	"tls-key":           "http.tls.key_file",
+	"tls-embedded":      "http.tls.embedded",
+	"tls-self-signed":   "http.tls.self_signed",
+	"tls-redirect-addr": "http.tls.redirect_addr",
+	"hsts-max-age":      "http.tls.hsts.max_age",
+}
+
 var serveCmd = &cli.Command{
 	Name:  "serve",
 	Usage: "Start the Prospero server (HTTP + SSH)",
@@ -21,52 +52,157 @@ SSH server is automatically disabled when running on bunny.net Magic Containers.
 Use --force-ssh to override this behavior for testing.`,
 	Flags: []cli.Flag{
 		&cli.StringFlag{
-			Name:  "host",
-			Value: "localhost",
-			Usage: "Host to bind both servers to",
+			Name:    "host",
+			Value:   defaults.Server.Host,
+			Usage:   "Host to bind both servers to",
+			EnvVars: []string{config.EnvName("server.host")},
+		},
+		&cli.StringFlag{
+			Name:    "http-port",
+			Value:   defaults.HTTP.Port,
+			Usage:   "Port for the HTTP server",
+			EnvVars: []string{config.EnvName("http.port")},
+		},
+		&cli.StringFlag{
+			Name:    "ssh-port",
+			Value:   defaults.SSH.Port,
+			Usage:   "Port for the SSH server",
+			EnvVars: []string{config.EnvName("ssh.port")},
+		},
+		&cli.BoolFlag{
+			Name:    "force-ssh",
+			Value:   defaults.SSH.Force,
+			Usage:   "Force SSH server to start even on bunny.net Magic Containers",
+			EnvVars: []string{config.EnvName("ssh.force")},
+		},
+		&cli.BoolFlag{
+			Name:    "ssh-anonymous",
+			Value:   defaults.SSH.Auth.Anonymous,
+			Usage:   "Let SSH clients without a listed key or password in (use --ssh-anonymous=false to require one)",
+			EnvVars: []string{config.EnvName("ssh.auth.anonymous")},
+		},
+		&cli.BoolFlag{
+			Name:    "ssh-authorized-keys",
+			Usage:   "Allow the SSH keys of the encrypted authorized_keys asset",
+			EnvVars: []string{config.EnvName("ssh.auth.authorized_keys")},
+		},
+		&cli.StringFlag{
+			Name:    "ssh-keys-dir",
+			Usage:   "Allow the SSH keys of <user>.keys files in this directory",
+			EnvVars: []string{config.EnvName("ssh.auth.keys_dir")},
+		},
+		&cli.StringFlag{
+			Name:    "ssh-audit-dir",
+			Usage:   "Append an audit event for every SSH session to sessions.jsonl in this directory",
+			EnvVars: []string{config.EnvName("ssh.audit.dir")},
+		},
+		&cli.BoolFlag{
+			Name:    "ssh-record",
+			Usage:   "Record interactive SSH sessions as asciicast files in --ssh-audit-dir",
+			EnvVars: []string{config.EnvName("ssh.audit.record")},
+		},
+		&cli.DurationFlag{
+			Name:    "ssh-idle-timeout",
+			Value:   defaults.SSH.Limits.IdleTimeout,
+			Usage:   "Close SSH sessions without input or output for this long (0 disables)",
+			EnvVars: []string{config.EnvName("ssh.limits.idle_timeout")},
+		},
+		&cli.DurationFlag{
+			Name:    "ssh-max-duration",
+			Value:   defaults.SSH.Limits.MaxDuration,
+			Usage:   "Close SSH sessions open for this long (0 disables)",
+			EnvVars: []string{config.EnvName("ssh.limits.max_duration")},
+		},
+		&cli.IntFlag{
+			Name:    "ssh-max-sessions",
+			Value:   defaults.SSH.Limits.MaxSessions,
+			Usage:   "Refuse SSH sessions beyond this many open at once (0 disables)",
+			EnvVars: []string{config.EnvName("ssh.limits.max_sessions")},
+		},
+		&cli.IntFlag{
+			Name:    "ssh-max-sessions-per-ip",
+			Value:   defaults.SSH.Limits.MaxSessionsPerIP,
+			Usage:   "Refuse SSH sessions beyond this many open at once from one address (0 disables)",
+			EnvVars: []string{config.EnvName("ssh.limits.max_sessions_per_ip")},
+		},
+		&cli.Int64Flag{
+			Name:    "ssh-max-bytes-out",
+			Value:   defaults.SSH.Limits.MaxBytesOut,
+			Usage:   "Close SSH sessions once they have sent this many bytes (0 disables)",
+			EnvVars: []string{config.EnvName("ssh.limits.max_bytes_out")},
+		},
+		&cli.StringFlag{
+			Name:    "metrics-addr",
+			Usage:   "Serve /metrics on a separate admin listener (e.g. localhost:9090) instead of the public HTTP server",
+			EnvVars: []string{config.EnvName("metrics.addr")},
+		},
+		&cli.DurationFlag{
+			Name:    "drain-delay",
+			Value:   defaults.Server.DrainDelay,
+			Usage:   "How long /readyz reports draining before the HTTP server shuts down",
+			EnvVars: []string{config.EnvName("server.drain_delay")},
 		},
 		&cli.StringFlag{
-			Name:  "http-port",
-			Value: "8080",
-			Usage: "Port for the HTTP server",
+			Name:    "tls-cert",
+			Usage:   "Serve HTTPS with this PEM certificate chain (decrypted if it ends in .age)",
+			EnvVars: []string{config.EnvName("http.tls.cert_file")},
 		},
 		&cli.StringFlag{
-			Name:  "ssh-port",
-			Value: "2222",
-			Usage: "Port for the SSH server",
+			Name:    "tls-key",
+			Usage:   "PEM private key for --tls-cert (decrypted if it ends in .age)",
+			EnvVars: []string{config.EnvName("http.tls.key_file")},
+		},
+		&cli.BoolFlag{
+			Name:    "tls-embedded",
+			Usage:   "Serve HTTPS with the embedded tls.crt.age and tls.key.age",
+			EnvVars: []string{config.EnvName("http.tls.embedded")},
 		},
 		&cli.BoolFlag{
-			Name:  "force-ssh",
-			Value: false,
-			Usage: "Force SSH server to start even on bunny.net Magic Containers",
+			Name:    "tls-self-signed",
+			Usage:   "Serve HTTPS with an in-memory self-signed certificate (development only)",
+			EnvVars: []string{config.EnvName("http.tls.self_signed")},
+		},
+		&cli.StringFlag{
+			Name:    "tls-redirect-addr",
+			Usage:   "Redirect plain HTTP on this address (e.g. :8081) to HTTPS",
+			EnvVars: []string{config.EnvName("http.tls.redirect_addr")},
+		},
+		&cli.DurationFlag{
+			Name:    "hsts-max-age",
+			Usage:   "Send Strict-Transport-Security with this max-age over HTTPS (e.g. 8760h)",
+			EnvVars: []string{config.EnvName("http.tls.hsts.max_age")},
 		},
 	},
 	Action: func(c *cli.Context) error {
-		host := c.String("host")
-		httpPort := c.String("http-port")
-		sshPort := c.String("ssh-port")
-		forceSSH := c.Bool("force-ssh")
-
-		config := server.ServerConfig{
-			Host:     host,
-			HTTPPort: httpPort,
-			SSHPort:  sshPort,
-			ForceSSH: forceSSH,
+		cfg := *appConfig
+		if err := applyFlags(c, &cfg, serveFlagKeys); err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
 		}
 
 		// Create a context that cancels on SIGINT or SIGTERM
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
+		// Put the terminal in raw mode so a single 'q' keypress stops the
+		// server. Raw mode disables newline translation, so log output needs
+		// explicit carriage returns while it is active.
+		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err == nil {
+			defer term.Restore(int(os.Stdin.Fd()), oldState)
+			if err := logging.Setup(logging.CRLFWriter(os.Stderr), logOptions(&cfg)); err != nil {
+				return err
+			}
+		}
+
 		// Start keyboard input handler in a goroutine
 		go func() {
-			// Save original terminal state
-			oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-			if err != nil {
-				// If we can't set raw mode, just return silently
+			if oldState == nil {
+				// Not a terminal, nothing to read keypresses from
 				return
 			}
-			defer term.Restore(int(os.Stdin.Fd()), oldState)
 
 			// Read single bytes from stdin
 			buf := make([]byte, 1)
@@ -84,6 +220,6 @@ Use --force-ssh to override this behavior for testing.`,
 			}
 		}()
 
-		return server.StartServers(ctx, config)
+		return server.StartServers(ctx, &cfg)
 	},
 }
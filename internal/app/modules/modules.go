@@ -0,0 +1,19 @@
+// Package modules assembles the features Prospero is built from. Adding a
+// feature means implementing features.Feature in a package under
+// internal/features and listing it here.
+package modules
+
+import (
+	"prospero/internal/features"
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+)
+
+// Default returns a registry of every feature in display order. Each call
+// returns fresh, unopened features.
+func Default() *features.Registry {
+	return features.NewRegistry(
+		topten.NewFeature(),
+		shakespert.NewFeature(),
+	)
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/config"
+	"prospero/internal/metrics"
+)
+
+// StartAdminServer starts a separate HTTP listener for operational endpoints
+// such as /metrics, so they can be bound to a private interface
+func StartAdminServer(ctx context.Context, cfg *config.Config) error {
+	addr := cfg.Metrics.Addr
+
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: r,
+	}
+
+	slog.Info("admin server starting", "addr", addr)
+	bannerf("📈 Admin server starting on http://%s\r\n", addr)
+	bannerf("   GET  /metrics                      - Prometheus metrics\r\n")
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down admin server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("admin server forced to shutdown", "error", err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
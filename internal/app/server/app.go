@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/assets"
+	"prospero/internal/app/modules"
+	"prospero/internal/auth"
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/hostkey"
+	"prospero/internal/mcp"
+	"prospero/internal/ratelimit"
+	"prospero/internal/sshaudit"
+	"prospero/internal/sshauth"
+)
+
+// App holds the features shared by the HTTP and SSH servers. It is built once
+// by StartServers so the encrypted assets are decrypted and the shakespert
+// database is opened a single time.
+type App struct {
+	Features *features.Registry
+	MCP      *mcp.Server
+
+	// Keys authenticates API keys for the HTTP API and /mcp
+	Keys *auth.Keyring
+
+	// Limits holds the rate limit buckets of both servers
+	Limits ratelimit.Store
+
+	// TLS configures HTTPS, or is nil when the HTTP server is plain HTTP
+	TLS *tls.Config
+
+	// HostKeys are the SSH host keys, one per algorithm, or nil when SSH is
+	// disabled
+	HostKeys []cryptossh.Signer
+
+	// SSHAuth authenticates SSH clients, or is nil when SSH is disabled
+	SSHAuth *sshauth.Authenticator
+
+	// Audit keeps the SSH session log and recordings, or is nil when no
+	// audit directory is configured
+	Audit *sshaudit.Log
+
+	// Started is when the app was built, for the uptime reported by stats
+	Started time.Time
+
+	// sshSessions counts the SSH sessions currently open
+	sshSessions atomic.Int64
+}
+
+// NewApp opens every feature. The SSH host keys are only decrypted when
+// withSSH is set.
+func NewApp(ctx context.Context, cfg *config.Config, withSSH bool) (*App, error) {
+	var hostKeys []cryptossh.Signer
+	var sshAuth *sshauth.Authenticator
+	var audit *sshaudit.Log
+	if withSSH {
+		var err error
+		hostKeys, err = hostkey.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH host keys: %w", err)
+		}
+		sshAuth, err = newSSHAuthenticator(cfg.SSH.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH authentication: %w", err)
+		}
+		if cfg.SSH.Audit.Dir != "" {
+			audit, err = sshaudit.Open(cfg.SSH.Audit.Dir, cfg.SSH.Audit.Record)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The audit log is closed when a later step fails
+	opened := false
+	defer func() {
+		if !opened && audit != nil {
+			audit.Close()
+		}
+	}()
+
+	keys, err := auth.LoadKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := loadTLSConfig(cfg.HTTP.TLS, cfg.Server.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := modules.Default()
+	if err := registry.Open(ctx); err != nil {
+		return nil, err
+	}
+
+	mcpServer, err := NewMCPServer(cfg.MCP, registry)
+	if err != nil {
+		registry.Close()
+		return nil, err
+	}
+
+	opened = true
+	return &App{
+		Features: registry,
+		MCP:      mcpServer,
+		Keys:     keys,
+		Limits:   ratelimit.NewMemoryStore(),
+		TLS:      tlsConfig,
+		HostKeys: hostKeys,
+		SSHAuth:  sshAuth,
+		Audit:    audit,
+		Started:  time.Now(),
+	}, nil
+}
+
+// Close releases resources held by the features and the audit log
+func (a *App) Close() error {
+	err := a.Features.Close()
+	if a.Audit != nil {
+		err = errors.Join(err, a.Audit.Close())
+	}
+	return err
+}
+
+// NewMCPServer creates the MCP server with the embedded prompts and the
+// tools and prompts of every feature in registry registered. The features
+// must already be open.
+func NewMCPServer(cfg config.MCPConfig, registry *features.Registry) (*mcp.Server, error) {
+	mcpServer := mcp.NewServer(cfg.Name, cfg.Version)
+	promptFS, _, err := assets.Prompts()
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := mcp.LoadPromptsFromTOML(promptFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MCP prompts: %w", err)
+	}
+
+	// Register prompts with handlers
+	for _, def := range definitions {
+		prompt := def.ToPrompt()
+		var handler mcp.PromptHandler
+		if def.Content != "" {
+			handler = def.CreateHandler()
+		} else {
+			handler = func(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
+				return nil, fmt.Errorf("handler not implemented for prompt: %s", prompt.Name)
+			}
+		}
+		mcpServer.RegisterPrompt(prompt, handler)
+	}
+
+	registry.RegisterMCP(mcpServer)
+
+	return mcpServer, nil
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// stdoutIsTTY reports whether stdout is an interactive terminal. The pretty
+// startup banners are only printed in that case; otherwise the structured
+// log carries the same information.
+var stdoutIsTTY = term.IsTerminal(int(os.Stdout.Fd()))
+
+// bannerf prints decorative startup output when attached to a terminal.
+// Lines should end in \r\n because serve puts the terminal in raw mode.
+func bannerf(format string, args ...any) {
+	if stdoutIsTTY {
+		fmt.Printf(format, args...)
+	}
+}
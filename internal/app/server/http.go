@@ -3,163 +3,156 @@ package server
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
-	"prospero/assets"
-	"prospero/internal/features/shakespert"
-	"prospero/internal/features/topten"
-	"prospero/internal/mcp"
-	"prospero/internal/web/handlers"
+	"prospero/internal/config"
+	"prospero/internal/health"
+	"prospero/internal/metrics"
+	webmiddleware "prospero/internal/web/middleware"
 )
 
-// rawTerminalLogger is a custom logging middleware that uses \r\n for raw terminal mode
-func rawTerminalLogger(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
-		t1 := time.Now()
-
-		defer func() {
-			scheme := "http"
-			if r.TLS != nil {
-				scheme = "https"
-			}
-			log.Printf("\"%s %s://%s%s %s\" from %s - %d %dB in %s\r",
-				r.Method,
-				scheme,
-				r.Host,
-				r.RequestURI,
-				r.Proto,
-				r.RemoteAddr,
-				ww.Status(),
-				ww.BytesWritten(),
-				time.Since(t1),
-			)
-		}()
-
-		next.ServeHTTP(ww, r)
-	})
-}
-
-// StartHTTPServer starts the HTTP server with the given host and port
-func StartHTTPServer(ctx context.Context, host, port string) error {
-	// Initialize the topten service
-	toptenService, err := topten.NewService(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize topten service: %w", err)
-	}
+// StartHTTPServer starts the HTTP server on the configured host and port
+// using the shared services in app. It registers its dependency checks with
+// probes and completes the "http" startup task once the listener is bound.
+func StartHTTPServer(ctx context.Context, cfg *config.Config, app *App, probes *health.Registry) error {
+	host, port := cfg.Server.Host, cfg.HTTP.Port
+	startedAt := time.Now()
 
-	// Initialize the shakespert service
-	shakespertService, err := shakespert.NewService(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize shakespert service: %w", err)
-	}
-	defer shakespertService.Close()
-
-	// Initialize the MCP server
-	mcpServer := mcp.NewServer("prospero", "1.0.0")
-	promptFS := assets.GetEmbeddedPrompts()
-	definitions, err := mcp.LoadPromptsFromTOML(promptFS)
-	if err != nil {
-		return fmt.Errorf("failed to load MCP prompts: %w", err)
+	// Register dependency checks for /readyz
+	for _, f := range app.Features.All() {
+		probes.Register(f.Name(), f.Check)
 	}
-
-	// Register prompts with handlers
-	for _, def := range definitions {
-		prompt := def.ToPrompt()
-		var handler mcp.PromptHandler
-		if def.Content != "" {
-			handler = def.CreateHandler()
-		} else {
-			handler = func(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
-				return nil, fmt.Errorf("handler not implemented for prompt: %s", prompt.Name)
-			}
+	probes.Register("mcp_prompts", func(ctx context.Context) error {
+		if app.MCP.PromptCount() == 0 {
+			return fmt.Errorf("no MCP prompts loaded")
 		}
-		mcpServer.RegisterPrompt(prompt, handler)
-	}
+		return nil
+	})
 
 	// Create router
 	r := chi.NewRouter()
 
 	// Add middleware
-	r.Use(rawTerminalLogger)
-	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Timeout(60 * time.Second))
-
-	// Add CORS middleware for API usage
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-
-			if r.Method == "OPTIONS" {
-				return
-			}
+	r.Use(webmiddleware.RequestLogger)
+	r.Use(middleware.Recoverer)
+	r.Use(webmiddleware.Metrics)
+	r.Use(middleware.Timeout(cfg.HTTP.RequestTimeout))
+
+	// Answer CORS preflights with the methods each route actually serves
+	r.Use(webmiddleware.CORS(webmiddleware.CORSPolicy{
+		AllowedOrigins:   cfg.HTTP.CORS.AllowedOrigins,
+		AllowCredentials: cfg.HTTP.CORS.AllowCredentials,
+		AllowedHeaders:   cfg.HTTP.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.HTTP.CORS.ExposedHeaders,
+		MaxAge:           cfg.HTTP.CORS.MaxAge,
+	}))
+
+	if app.TLS != nil && cfg.HTTP.TLS.HSTS.MaxAge > 0 {
+		r.Use(webmiddleware.HSTS(webmiddleware.HSTSPolicy{
+			MaxAge:            cfg.HTTP.TLS.HSTS.MaxAge,
+			IncludeSubdomains: cfg.HTTP.TLS.HSTS.IncludeSubdomains,
+			Preload:           cfg.HTTP.TLS.HSTS.Preload,
+		}))
+	}
 
-			next.ServeHTTP(w, r)
-		})
-	})
+	// Negotiate zstd/brotli/gzip compression for larger responses
+	r.Use(webmiddleware.Compress(webmiddleware.DefaultCompressMinSize))
 
 	// Routes
-	r.Get("/health", handlers.Health())
-	r.Get("/api/info", handlers.Info())
-	r.Get("/api/topten", handlers.TopTen(toptenService))
-
-	// Shakespert routes
-	r.Get("/api/shakespert/works", handlers.ShakespertWorks(shakespertService))
-	r.Get("/api/shakespert/works/*", handlers.ShakespertWork(shakespertService))
-	r.Get("/api/shakespert/genres", handlers.ShakespertGenres(shakespertService))
+	registerRoutes(r, cfg, app, probes, startedAt)
 
-	// MCP routes
-	r.HandleFunc("/mcp", mcpServer.HTTPHandler())
+	// Serve metrics publicly only when no separate admin listener is configured
+	if cfg.Metrics.Addr == "" {
+		r.Method(http.MethodGet, "/metrics", metrics.Handler())
+	}
 
 	// Create server
 	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", host, port),
-		Handler: r,
+		Addr:      fmt.Sprintf("%s:%s", host, port),
+		Handler:   r,
+		TLSConfig: app.TLS,
 	}
 
-	fmt.Printf("🌐 HTTP Server starting on http://%s:%s\r\n", host, port)
-	fmt.Printf("📡 Endpoints:\r\n")
-	fmt.Printf("   GET  /health                    - Health check\r\n")
-	fmt.Printf("   GET  /api/info                  - Server information\r\n")
-	fmt.Printf("   GET  /api/topten                - Random Top 10 list\r\n")
-	fmt.Printf("   GET  /api/shakespert/works      - List Shakespeare works\r\n")
-	fmt.Printf("   GET  /api/shakespert/works/{id} - Get specific work details\r\n")
-	fmt.Printf("   GET  /api/shakespert/genres     - List available genres\r\n")
-	fmt.Printf("   💡 curl auto-detects and returns ASCII format\r\n")
-	fmt.Printf("   (Add ?format=json|text|ascii to override)\r\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
-	fmt.Printf("🤖 MCP Server:\r\n")
-	fmt.Printf("   POST /mcp                       - MCP JSON-RPC endpoint\r\n")
-	fmt.Printf("   GET  /mcp                       - MCP SSE stream endpoint\r\n")
-	fmt.Printf("   Loaded %d prompts from TOML files\r\n", len(definitions))
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+	scheme := "http"
+	if app.TLS != nil {
+		scheme = "https"
+	}
+
+	slog.Info("http server starting", "addr", server.Addr, "tls", app.TLS != nil, "prompts", app.MCP.PromptCount(), "tools", app.MCP.ToolCount(),
+		"api_keys", app.Keys.Len(), "public_scopes", cfg.Auth.PublicScopes)
+	bannerf("🌐 HTTP Server starting on %s://%s:%s\r\n", scheme, host, port)
+	bannerf("📡 Endpoints:\r\n")
+	for _, endpoint := range apiEndpoints(app.Features) {
+		bannerf("   %-4s %-30s - %s\r\n", endpoint.Method, endpoint.Path, endpoint.Description)
+	}
+	bannerf("   (Unversioned /api/... paths are deprecated aliases)\r\n")
+	if cfg.Metrics.Addr == "" {
+		bannerf("   GET  /metrics                      - Prometheus metrics\r\n")
+	}
+	bannerf("   🔑 %d API keys, public scopes: %s\r\n", app.Keys.Len(), publicScopes(cfg.Auth.PublicScopes))
+	bannerf("   💡 curl auto-detects and returns ASCII format\r\n")
+	bannerf("   (Add ?format=json|text|ascii to override)\r\n")
+	bannerf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+	bannerf("🤖 MCP Server:\r\n")
+	bannerf("   POST /mcp                          - MCP JSON-RPC endpoint\r\n")
+	bannerf("   GET  /mcp                          - MCP SSE stream endpoint\r\n")
+	bannerf("   Loaded %d prompts from TOML files\r\n", app.MCP.PromptCount())
+	bannerf("   Registered %d tools from features\r\n", app.MCP.ToolCount())
+	bannerf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	probes.Complete("http")
 
 	// Start server in a goroutine so we can handle context cancellation
 	go func() {
 		<-ctx.Done()
-		fmt.Printf("🌐 Shutting down HTTP server...\r\n")
 
-		// Give server 5 seconds to shut down gracefully
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Fail readiness first so load balancers stop sending new traffic
+		probes.Drain()
+		if cfg.Server.DrainDelay > 0 {
+			slog.Info("draining http server", "delay", cfg.Server.DrainDelay)
+			time.Sleep(cfg.Server.DrainDelay)
+		}
+
+		slog.Info("shutting down http server")
+
+		// Give in-flight requests time to finish
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 		defer cancel()
 
 		if err := server.Shutdown(shutdownCtx); err != nil {
-			fmt.Printf("HTTP server forced to shutdown: %v\n", err)
+			slog.Error("http server forced to shutdown", "error", err)
 		}
 	}()
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	// ServeTLS takes the certificate from server.TLSConfig and enables HTTP/2
+	serve := server.Serve
+	if app.TLS != nil {
+		serve = func(l net.Listener) error { return server.ServeTLS(l, "", "") }
+	}
+	if err := serve(listener); err != nil && err != http.ErrServerClosed {
 		return err
 	}
 
 	return nil
 }
+
+// publicScopes formats the scopes granted without an API key for the banner
+func publicScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "none (API key required)"
+	}
+	return strings.Join(scopes, ", ")
+}
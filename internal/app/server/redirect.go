@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"prospero/internal/config"
+	webmiddleware "prospero/internal/web/middleware"
+)
+
+// StartRedirectServer starts a plain HTTP listener that redirects every
+// request to the HTTPS server
+func StartRedirectServer(ctx context.Context, cfg *config.Config) error {
+	addr := cfg.HTTP.TLS.RedirectAddr
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: webmiddleware.RedirectHTTPS(cfg.HTTP.Port),
+	}
+
+	slog.Info("redirect server starting", "addr", addr, "https_port", cfg.HTTP.Port)
+	bannerf("↪️  Redirecting http://%s to HTTPS port %s\r\n", addr, cfg.HTTP.Port)
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down redirect server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("redirect server forced to shutdown", "error", err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
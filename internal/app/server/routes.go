@@ -0,0 +1,155 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/assets"
+	"prospero/internal/auth"
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/health"
+	"prospero/internal/ratelimit"
+	"prospero/internal/web/handlers"
+	"prospero/internal/web/middleware"
+)
+
+// apiVersionPrefix is the mount point of the current API version
+const apiVersionPrefix = "/api/v1"
+
+// registerRoutes mounts all HTTP routes on r
+func registerRoutes(r chi.Router, cfg *config.Config, app *App, probes *health.Registry, startedAt time.Time) {
+	registry := app.Features
+
+	r.NotFound(handlers.NotFound())
+	r.MethodNotAllowed(handlers.MethodNotAllowed())
+
+	// Probes must never be cached
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Cache(middleware.CachePolicy{CacheControl: middleware.CacheNoStore}))
+		r.Get("/health", handlers.Health(startedAt))
+		r.Get("/livez", handlers.Livez())
+		r.Get("/readyz", handlers.Readyz(probes))
+	})
+
+	endpoints := apiEndpoints(registry)
+
+	// Callers are identified before rate limiting so API keys get their own
+	// buckets
+	authn := middleware.Authenticate(app.Keys, cfg.Auth.PublicScopes)
+	limit := rateLimit(cfg.RateLimit, app.Limits)
+
+	// Versioned API
+	r.Route(apiVersionPrefix, func(r chi.Router) {
+		r.Use(authn, limit)
+		mountAPI(r, registry, endpoints)
+	})
+
+	// Legacy unversioned paths kept as deprecated aliases of /api/v1
+	r.Route("/api", func(r chi.Router) {
+		r.Use(middleware.Deprecated("/api", apiVersionPrefix), authn, limit)
+		mountAPI(r, registry, endpoints)
+	})
+
+	// MCP routes, reachable from browsers only on allowed origins. Only the
+	// methods of the Streamable HTTP transport are routed, so preflights
+	// advertise exactly those.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireOrigin(cfg.MCP.AllowedOrigins), authn, limit, middleware.RequireScope(auth.ScopeMCP))
+		mcpHandler := app.MCP.HTTPHandler()
+		r.Get("/mcp", mcpHandler)
+		r.Post("/mcp", mcpHandler)
+	})
+}
+
+// rateLimit returns the rate limiting middleware for cfg. Probes and metrics
+// are never limited, so it is applied to the API and MCP routes only.
+func rateLimit(cfg config.RateLimitConfig, store ratelimit.Store) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for pattern, budget := range cfg.Routes {
+		routes[pattern] = budget.Limit()
+	}
+	return middleware.RateLimit(middleware.RateLimitPolicy{
+		Store:   store,
+		Default: cfg.Default.Limit(),
+		Routes:  routes,
+		Key:     rateLimitKey,
+		Client:  keyRateLimit,
+	})
+}
+
+// rateLimitKey buckets requests made with an API key by key, and others by
+// client address
+func rateLimitKey(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok && !principal.Anonymous() {
+		return "key:" + principal.Key.ID
+	}
+	return ratelimit.ClientAddr(r.RemoteAddr)
+}
+
+// keyRateLimit returns the budget of the request's API key, if it has one
+func keyRateLimit(r *http.Request) (ratelimit.Limit, bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok || principal.Anonymous() || principal.Key.RateLimit == nil {
+		return ratelimit.Limit{}, false
+	}
+	// Key limits are validated when the keyring is loaded
+	limit, err := principal.Key.RateLimit.Limit()
+	return limit, err == nil
+}
+
+// mountAPI registers the API endpoints relative to the current route
+func mountAPI(r chi.Router, registry *features.Registry, endpoints []features.Endpoint) {
+	opts := features.RouteOptions{
+		DataVersion: assets.Version(),
+		DataModTime: assets.ModTime(),
+	}
+
+	// The info page auto-detects curl, so the representation varies by User-Agent
+	info := middleware.Cache(middleware.CachePolicy{
+		CacheControl: middleware.CacheShort,
+		Version:      opts.DataVersion,
+		LastModified: opts.DataModTime,
+		Vary:         []string{"User-Agent"},
+	})
+
+	r.With(info).Get("/info", handlers.Info(endpoints))
+
+	registry.RegisterRoutes(r, opts)
+}
+
+// apiEndpoints lists the core endpoints followed by those of every feature,
+// for the info page and the startup banner
+func apiEndpoints(registry *features.Registry) []features.Endpoint {
+	endpoints := []features.Endpoint{
+		{
+			Method:      http.MethodGet,
+			Path:        "/health",
+			Description: "Health check endpoint",
+			Parameters:  "?verbose=true",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/livez",
+			Description: "Liveness probe",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/readyz",
+			Description: "Readiness probe with per-component status",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        apiVersionPrefix + "/info",
+			Description: "Server information and available endpoints",
+			Parameters:  "?format=json|text|ascii",
+		},
+	}
+	return append(endpoints, registry.Endpoints(apiVersionPrefix)...)
+}
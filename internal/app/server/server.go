@@ -3,59 +3,50 @@ package server
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"sync"
-	"time"
 
-	"prospero/internal/features/topten"
+	"prospero/internal/config"
+	"prospero/internal/health"
 )
 
-// ServerConfig holds the configuration for both HTTP and SSH servers
-type ServerConfig struct {
-	Host     string
-	HTTPPort string
-	SSHPort  string
-	ForceSSH bool // Force SSH server to start even on bunny.net
-}
-
-// isRunningInBunnyMagicContainer detects if the application is running
-// inside a bunny.net Magic Container by checking for bunny.net-specific
-// environment variables
-func isRunningInBunnyMagicContainer() bool {
-	return os.Getenv("BUNNYNET_MC_APPID") != ""
-}
-
 // StartServers starts both HTTP and SSH servers concurrently
-func StartServers(ctx context.Context, config ServerConfig) error {
-	fmt.Printf("\r\n🎩 Prospero Server Starting\r\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
-	fmt.Printf("Press 'q' to quit or Ctrl+C to stop\r\n\r\n")
+func StartServers(ctx context.Context, cfg *config.Config) error {
+	bannerf("\r\n🎩 Prospero Server Starting\r\n")
+	bannerf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+	bannerf("Press 'q' to quit or Ctrl+C to stop\r\n\r\n")
 
 	// Determine if SSH should be enabled
-	enableSSH := true
-	if isRunningInBunnyMagicContainer() && !config.ForceSSH {
-		enableSSH = false
-		fmt.Printf("ℹ️  SSH server disabled (running on bunny.net Magic Container)\r\n")
+	enableSSH := cfg.SSHEnabled()
+	if !enableSSH {
+		slog.Info("ssh server disabled on bunny.net Magic Container", "hint", "use --force-ssh to override")
 	}
 
-	// Validate AGE encryption password before starting servers (only if SSH is enabled)
+	// Decrypt the assets and open the database once for every listener
+	app, err := NewApp(ctx, cfg, enableSSH)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+	slog.Info("age encryption password verified")
+
+	// Readiness stays "starting" until every enabled listener is bound
+	probes := health.NewRegistry()
+	probes.Pending("http")
 	if enableSSH {
-		if err := topten.ValidatePassword(ctx); err != nil {
-			return fmt.Errorf("AGE password validation failed: %w", err)
-		}
-		fmt.Printf("✅ AGE encryption password verified\r\n")
+		probes.Pending("ssh")
 	}
 
 	var wg sync.WaitGroup
-	errChan := make(chan error, 2)
-	shutdownChan := make(chan struct{}, 2)
+	errChan := make(chan error, 4)
+	shutdownChan := make(chan struct{}, 4)
 
 	// Start HTTP server in a goroutine
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		defer func() { shutdownChan <- struct{}{} }()
-		if err := StartHTTPServer(ctx, config.Host, config.HTTPPort); err != nil {
+		if err := StartHTTPServer(ctx, cfg, app, probes); err != nil {
 			if err != context.Canceled {
 				errChan <- fmt.Errorf("HTTP server error: %w", err)
 			}
@@ -68,7 +59,7 @@ func StartServers(ctx context.Context, config ServerConfig) error {
 		go func() {
 			defer wg.Done()
 			defer func() { shutdownChan <- struct{}{} }()
-			if err := StartSSHServer(ctx, config.Host, config.SSHPort); err != nil {
+			if err := StartSSHServer(ctx, cfg, app, probes); err != nil {
 				if err != context.Canceled {
 					errChan <- fmt.Errorf("SSH server error: %w", err)
 				}
@@ -76,14 +67,42 @@ func StartServers(ctx context.Context, config ServerConfig) error {
 		}()
 	}
 
+	// Start the admin metrics listener in a goroutine (only if configured)
+	if cfg.Metrics.Addr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { shutdownChan <- struct{}{} }()
+			if err := StartAdminServer(ctx, cfg); err != nil {
+				if err != context.Canceled {
+					errChan <- fmt.Errorf("admin server error: %w", err)
+				}
+			}
+		}()
+	}
+
+	// Start the HTTP to HTTPS redirect listener in a goroutine (only if configured)
+	if app.TLS != nil && cfg.HTTP.TLS.RedirectAddr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { shutdownChan <- struct{}{} }()
+			if err := StartRedirectServer(ctx, cfg); err != nil {
+				if err != context.Canceled {
+					errChan <- fmt.Errorf("redirect server error: %w", err)
+				}
+			}
+		}()
+	}
+
 	// Wait for either server to fail or context to be cancelled
 	select {
 	case <-ctx.Done():
 		// Context cancelled (Ctrl+C or SIGTERM)
-		fmt.Printf("\r\n🛑 Shutting down Prospero servers...\r\n")
+		slog.Info("shutting down prospero servers")
 
 		// Give servers time to shut down gracefully
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
 
 		done := make(chan struct{})
@@ -94,9 +113,9 @@ func StartServers(ctx context.Context, config ServerConfig) error {
 
 		select {
 		case <-done:
-			fmt.Printf("✅ All servers shut down gracefully\r\n")
+			slog.Info("all servers shut down gracefully")
 		case <-shutdownCtx.Done():
-			fmt.Printf("⚠️  Shutdown timeout reached\r\n")
+			slog.Warn("shutdown timeout reached")
 		}
 
 		return nil
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+
+	"prospero/internal/features"
+	"prospero/internal/metrics"
+)
+
+// shellName is the name of the command opening the shell
+const shellName = "shell"
+
+// shellCommand is the built-in command opening the interactive shell on s
+func (c *sshCommands) shellCommand(s ssh.Session) *cli.Command {
+	return &cli.Command{
+		Name:        shellName,
+		Aliases:     []string{"repl"},
+		Usage:       "Interactive shell with history and tab completion (ssh -t)",
+		Description: `Read command lines until exit, with a history for the session and tab completion.`,
+		Action: func(ctx *cli.Context) error {
+			return runShell(ctx.Context, s, c)
+		},
+	}
+}
+
+// shellBuiltins are the commands only the shell understands
+var shellBuiltins = []string{"help", "history", "clear", "exit"}
+
+// shellPrompt is shown before every line of input
+const shellPrompt = "prospero> "
+
+// keyCtrlC is the byte a terminal sends for ctrl+c
+const keyCtrlC = 3
+
+// runShell reads command lines from a session with a PTY and runs them
+// until the user exits or disconnects. History is kept for the session.
+func runShell(ctx context.Context, s ssh.Session, commands *sshCommands) error {
+	pty, windowChanges, hasPty := s.Pty()
+	if !hasPty {
+		return features.UsageError("The shell needs a terminal, connect with: ssh -t <host> shell")
+	}
+
+	role := sessionIdentity(s).Role
+	terminal := term.NewTerminal(s, shellPrompt)
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		switch key {
+		case '\t':
+			newLine, newPos, options := completeLine(line, pos, func(words []string) []string {
+				return commands.completions(ctx, role, words)
+			})
+			if len(options) > 0 {
+				fmt.Fprintf(terminal, "%s\n", strings.Join(options, "  "))
+			}
+			return newLine, newPos, true
+		case keyCtrlC:
+			// Abandon the line, as shells do
+			return "", 0, true
+		}
+		return "", 0, false
+	}
+	resizeTerminal(terminal, pty.Window)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case window, ok := <-windowChanges:
+				if !ok {
+					return
+				}
+				resizeTerminal(terminal, window)
+			}
+		}
+	}()
+
+	session := &shellSession{Session: s, terminal: terminal}
+	fmt.Fprintf(terminal, "\n🎩 Prospero shell. Type help for commands, tab to complete and exit to leave.\n\n")
+
+	for {
+		line, err := terminal.ReadLine()
+		if err != nil {
+			// io.EOF on ctrl+d or when the client disconnects
+			return nil
+		}
+
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "exit", "quit", "logout":
+			return nil
+		case "help":
+			showShellHelp(terminal, commands, role)
+		case "history":
+			showShellHistory(terminal)
+		case "clear":
+			fmt.Fprint(terminal, "\x1b[2J\x1b[H")
+		default:
+			runShellCommand(ctx, session, commands, args)
+		}
+	}
+}
+
+// resizeTerminal sizes terminal to window. Clients that request a terminal
+// without a size, such as ssh -tt without one, send 0x0, which would wrap
+// every character onto its own line, so the terminal keeps its 80x24
+// default until a real size arrives.
+func resizeTerminal(terminal *term.Terminal, window ssh.Window) {
+	if window.Width <= 0 || window.Height <= 0 {
+		return
+	}
+	_ = terminal.SetSize(window.Width, window.Height)
+}
+
+// runShellCommand runs one command line of the shell, counting it against
+// the session's command budget like an exec command. Failures are reported
+// without ending the session.
+func runShellCommand(ctx context.Context, s *shellSession, commands *sshCommands, args []string) {
+	label := commands.label(args)
+	metrics.IncSSHCommand(label)
+	slog.InfoContext(ctx, "ssh shell command", "command", strings.Join(args, " "))
+
+	if label == shellName {
+		fmt.Fprintf(s, "Already in the shell\n")
+		return
+	}
+	if !allowSSHCommand(ctx, s, commands.limiter) {
+		return
+	}
+	if err := commands.run(ctx, s, args); err != nil {
+		exitWithError(ctx, s, err)
+	}
+}
+
+// shellSession is the session as seen by commands run from the shell: their
+// output goes through the line editor, they cannot read the shell's input,
+// and their exit status does not end the session
+type shellSession struct {
+	ssh.Session
+	terminal *term.Terminal
+}
+
+func (s *shellSession) Write(p []byte) (int, error) {
+	return s.terminal.Write(p)
+}
+
+func (s *shellSession) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (s *shellSession) Stderr() io.ReadWriter {
+	return s
+}
+
+func (s *shellSession) Exit(code int) error {
+	return nil
+}
+
+// completeLine completes the word before pos in line. candidates returns
+// the possible values of that word given the words before it. A single
+// match is completed with a trailing space; several are completed to their
+// common prefix, and returned to be listed when that adds nothing.
+func completeLine(line string, pos int, candidates func(words []string) []string) (string, int, []string) {
+	head, tail := line[:pos], line[pos:]
+
+	words := strings.Fields(head)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(head, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var matches []string
+	for _, candidate := range candidates(words) {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(partial)) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	var completed string
+	switch len(matches) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		completed = matches[0] + " "
+	default:
+		completed = commonPrefix(matches)
+		if len(completed) <= len(partial) {
+			return line, pos, matches
+		}
+	}
+
+	head = head[:len(head)-len(partial)] + completed
+	return head + tail, len(head), nil
+}
+
+// commonPrefix returns the longest prefix shared by every value
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func showShellHelp(w io.Writer, commands *sshCommands, role string) {
+	fmt.Fprintf(w, "\nCommands:\n")
+	for _, usage := range usages(commands.newApp(nil, role)) {
+		if usage.Command == shellName {
+			continue
+		}
+		fmt.Fprintf(w, "  %-30s - %s\n", usage.Usage, usage.Description)
+	}
+	fmt.Fprintf(w, "  %-30s - %s\n", "history", "Show the commands entered this session")
+	fmt.Fprintf(w, "  %-30s - %s\n", "clear", "Clear the screen")
+	fmt.Fprintf(w, "  %-30s - %s\n", "exit", "Leave the shell (or ctrl+d)")
+	fmt.Fprintf(w, "\nAdd --help to a command for its options. Tab completes commands, options,\n")
+	fmt.Fprintf(w, "work IDs and character IDs; ↑/↓ recall history.\n\n")
+}
+
+// showShellHistory lists the session's history, oldest first
+func showShellHistory(terminal *term.Terminal) {
+	n := terminal.History.Len()
+	for i := n - 1; i >= 0; i-- {
+		fmt.Fprintf(terminal, "%4d  %s\n", n-i, terminal.History.At(i))
+	}
+}
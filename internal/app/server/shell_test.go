@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/config"
+)
+
+func TestRunShell(t *testing.T) {
+	t.Run("should keep the default size for terminals without one", func(t *testing.T) {
+		authn, err := newSSHAuthenticator(config.Default().SSH.Auth)
+		require.NoError(t, err)
+		addr := startTestSSHServer(t, func(s ssh.Session) {
+			assert.NoError(t, runShell(context.Background(), s, nil))
+		}, withSSHAuth(authn))
+
+		client, err := cryptossh.Dial("tcp", addr, &cryptossh.ClientConfig{
+			User:            "guest",
+			Auth:            []cryptossh.AuthMethod{cryptossh.PublicKeys(newClientKey(t))},
+			HostKeyCallback: cryptossh.InsecureIgnoreHostKey(),
+		})
+		require.NoError(t, err)
+		defer client.Close()
+		session, err := client.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		require.NoError(t, session.RequestPty("xterm", 0, 0, cryptossh.TerminalModes{}))
+		stdin, err := session.StdinPipe()
+		require.NoError(t, err)
+		stdout, err := session.StdoutPipe()
+		require.NoError(t, err)
+		require.NoError(t, session.Shell())
+
+		_, err = io.WriteString(stdin, "exit\r")
+		require.NoError(t, err)
+		output, err := io.ReadAll(stdout)
+		require.NoError(t, err)
+
+		// At a width of 0, every character would be wrapped onto a line
+		assert.Contains(t, string(output), shellPrompt+"exit")
+	})
+}
@@ -2,127 +2,215 @@ package server
 
 import (
 	"context"
-	"crypto/ed25519"
-	"crypto/x509"
-	"encoding/base64"
-	"encoding/pem"
 	"fmt"
+	"log/slog"
 	"net"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/muesli/termenv"
+	"github.com/urfave/cli/v2"
 	cryptossh "golang.org/x/crypto/ssh"
 
-	"prospero/internal/features/shakespert"
-	"prospero/internal/features/topten"
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/health"
+	"prospero/internal/hostkey"
+	"prospero/internal/logging"
+	"prospero/internal/metrics"
+	"prospero/internal/ratelimit"
+	"prospero/internal/sshauth"
 )
 
-// StartSSHServer starts the SSH server with the given host and port
-func StartSSHServer(ctx context.Context, host, port string) error {
-	// Initialize the topten service
-	toptenService, err := topten.NewService(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize topten service: %w", err)
-	}
-
-	// Initialize the shakespert service
-	shakespertService, err := shakespert.NewService(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to initialize shakespert service: %w", err)
-	}
-	defer shakespertService.Close()
-
-	// Decrypt the SSH host key
-	hostKey, err := topten.DecryptSSHHostKey(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to decrypt SSH host key: %w", err)
-	}
-
-	// Extract and display the public key and fingerprint
-	publicKey, fingerprint, err := extractPublicKeyFromPrivate(hostKey)
-	if err != nil {
-		fmt.Printf("Warning: failed to extract public key: %v\r\n", err)
-		publicKey = "[unable to extract public key]"
-		fingerprint = "[unable to extract fingerprint]"
-	}
+// StartSSHServer starts the SSH server on the configured host and port using
+// the shared services in app. It registers an "ssh" listener check with
+// probes and completes the "ssh" startup task once the listener is bound.
+func StartSSHServer(ctx context.Context, cfg *config.Config, app *App, probes *health.Registry) error {
+	host, port := cfg.Server.Host, cfg.SSH.Port
 
 	// Create the SSH server
+	limiter := sshCommandLimiter(cfg.RateLimit, app.Limits)
+	commands := newSSHCommands(app, limiter)
+	sessions := newSessionLimits(cfg.SSH.Limits)
 	server, err := wish.NewServer(
 		wish.WithAddress(fmt.Sprintf("%s:%s", host, port)),
-		wish.WithHostKeyPEM(hostKey),
+		withHostKeys(app.HostKeys),
+		withConnectionLimit(cfg.RateLimit, app.Limits),
+		withConnectionTimeouts(cfg.SSH.Limits),
+		withSSHAuth(app.SSHAuth),
+		withSFTP(),
 		wish.WithMiddleware(
-			prosperoMiddleware(toptenService, shakespertService),
+			prosperoMiddleware(app, commands, limiter, sessions),
 		),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create SSH server: %w", err)
 	}
 
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+
+	var listening atomic.Bool
+	listening.Store(true)
+	defer listening.Store(false)
+
+	probes.Register("ssh", func(ctx context.Context) error {
+		if !listening.Load() {
+			return fmt.Errorf("ssh listener is not accepting connections")
+		}
+		return nil
+	})
+	probes.Complete("ssh")
+
 	// Display startup information
-	fmt.Printf("\r\n🎩 Prospero SSH Server Starting\r\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
-	fmt.Printf("📡 Server Address: %s:%s\r\n", host, port)
-	fmt.Printf("🔑 Host Key: %s\r\n", publicKey)
-	fmt.Printf("🔐 Fingerprint: %s\r\n", fingerprint)
-	fmt.Printf("\r\n💻 Connect from:\r\n")
-
-	addresses := getNetworkAddresses(port)
-	for _, addr := range addresses {
-		fmt.Printf("   %s\r\n", addr)
+	var fingerprints []string
+	for _, signer := range app.HostKeys {
+		fingerprints = append(fingerprints, cryptossh.FingerprintSHA256(signer.PublicKey()))
+	}
+	slog.Info("ssh server starting", "addr", fmt.Sprintf("%s:%s", host, port), "fingerprints", fingerprints,
+		"anonymous", cfg.SSH.Auth.Anonymous, "listed_keys", app.SSHAuth.Len(), "password_users", len(cfg.SSH.Auth.Users),
+		"audit_dir", cfg.SSH.Audit.Dir, "record", cfg.SSH.Audit.Record)
+	bannerf("\r\n🎩 Prospero SSH Server Starting\r\n")
+	bannerf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+	bannerf("📡 Server Address: %s:%s\r\n", host, port)
+	for _, signer := range app.HostKeys {
+		bannerf("🔑 Host Key: %s\r\n", hostkey.AuthorizedKey(signer.PublicKey()))
+		bannerf("🔐 Fingerprint: %s\r\n", cryptossh.FingerprintSHA256(signer.PublicKey()))
+	}
+	bannerf("🔒 Client Auth: %s\r\n", sshAuthSummary(cfg.SSH.Auth, app.SSHAuth))
+	if app.Audit != nil {
+		recording := ""
+		if cfg.SSH.Audit.Record {
+			recording = ", recording interactive sessions"
+		}
+		bannerf("📝 Session Audit: %s%s\r\n", app.Audit.Dir(), recording)
 	}
+	bannerf("\r\n💻 Connect from:\r\n")
 
-	fmt.Printf("\r\n🎯 Interactive Prospero SSH Server!\r\n")
-	fmt.Printf("📚 Available commands: topten, shakespert, info\r\n")
-	fmt.Printf("💡 Try: ssh localhost -p %s info --color\r\n", port)
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
-	fmt.Printf("Server ready. Press Ctrl+C to stop.\r\n\r\n")
+	if stdoutIsTTY {
+		for _, addr := range getNetworkAddresses(port) {
+			bannerf("   %s\r\n", addr)
+		}
+	}
+
+	bannerf("\r\n🎯 Interactive Prospero SSH Server!\r\n")
+	bannerf("📚 Available commands: %s\r\n", strings.Join(commands.names(), ", "))
+	bannerf("💡 Try: ssh localhost -p %s info --color\r\n", port)
+	bannerf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\r\n")
+	bannerf("Server ready. Press Ctrl+C to stop.\r\n\r\n")
 
 	// Start server in a goroutine so we can handle context cancellation
 	go func() {
 		<-ctx.Done()
-		fmt.Printf("🔌 Shutting down SSH server...\r\n")
+		slog.Info("shutting down ssh server")
 
-		// Give server 5 seconds to shut down gracefully
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Give open sessions time to finish
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.SSH.ShutdownTimeout)
 		defer cancel()
 
 		if err := server.Shutdown(shutdownCtx); err != nil {
-			fmt.Printf("SSH server forced to shutdown: %v\n", err)
+			slog.Error("ssh server forced to shutdown", "error", err)
 		}
 	}()
 
-	if err := server.ListenAndServe(); err != nil && err != ssh.ErrServerClosed {
+	if err := server.Serve(listener); err != nil && err != ssh.ErrServerClosed {
 		return err
 	}
 
 	return nil
 }
 
-func prosperoMiddleware(toptenService *topten.Service, shakespertService *shakespert.Service) wish.Middleware {
+func prosperoMiddleware(app *App, commands *sshCommands, limiter *ratelimit.Limiter, sessions *sessionLimits) wish.Middleware {
 	return func(sh ssh.Handler) ssh.Handler {
 		return func(s ssh.Session) {
-			// Get command from SSH session command
+			sessionEnded := metrics.SSHSessionStarted()
+			defer sessionEnded()
+			app.sshSessions.Add(1)
+			defer app.sshSessions.Add(-1)
+
+			// Attach the session ID so every log line for this session carries it
+			identity := sessionIdentity(s)
+			attrs := []slog.Attr{
+				slog.String("ssh_session_id", s.Context().SessionID()),
+				slog.String("remote_addr", s.RemoteAddr().String()),
+				slog.String("user", s.User()),
+				slog.String("auth_method", identity.Method),
+				slog.String("role", identity.Role),
+			}
+			if identity.Method == sshauth.MethodPublicKey {
+				attrs = append(attrs, slog.String("key_owner", identity.User))
+			}
+			if identity.Fingerprint != "" {
+				attrs = append(attrs, slog.String("key_fingerprint", identity.Fingerprint))
+			}
+			if s.Subsystem() != "" {
+				attrs = append(attrs, slog.String("subsystem", s.Subsystem()))
+			}
+			ctx := logging.WithAttrs(s.Context(), attrs...)
+
+			// Get command from SSH session command. Without one, a session
+			// with a terminal gets the interactive application.
 			cmd := s.Command()
-			if len(cmd) == 0 {
-				// Default behavior - show help
-				showSSHHelp(s)
-			} else {
-				command := strings.ToLower(cmd[0])
-				switch command {
-				case "topten":
-					handleTopTenSSH(s, toptenService)
-				case "shakespert", "shakespeare", "works":
-					handleShakespertSSH(s, shakespertService, cmd[1:])
-				case "info":
-					handleInfoSSH(s)
-				default:
-					fmt.Fprintf(s, "Unknown command: %s\n\n", command)
-					showSSHHelp(s)
+			_, _, hasPty := s.Pty()
+			interactive := len(cmd) == 0 && hasPty
+
+			label := commands.label(cmd)
+			switch {
+			case isSFTP(s):
+				label = "sftp"
+			case isSCP(s):
+				label = "scp"
+			case interactive:
+				label = "tui"
+			}
+			metrics.IncSSHCommand(label)
+
+			// Every session ends with an audit event: logged, and appended to
+			// the session log when an audit directory is configured. Sessions
+			// over a limit are closed with a message once their handler
+			// returns, which it should soon after limitCtx is done.
+			audited := newAuditedSession(ctx, s, app.Audit)
+			limited, limitCtx := sessions.wrap(ctx, audited)
+			s = limited
+			slog.InfoContext(ctx, "ssh session started", "command", strings.Join(cmd, " "))
+			defer func() {
+				event := audited.finish(ctx, hasPty)
+				event.Limit = limited.Limit()
+				slog.InfoContext(ctx, "ssh session ended",
+					"command", event.Command, "exit_status", event.ExitStatus, "duration", event.Duration(),
+					"bytes_out", event.BytesOut, "recording", event.Recording, "limit", event.Limit)
+				if app.Audit == nil {
+					return
+				}
+				if err := app.Audit.Write(event); err != nil {
+					slog.ErrorContext(ctx, "failed to write ssh audit event", "error", err)
 				}
+			}()
+
+			defer limited.end()
+
+			if !sessions.admit(limited) {
+				return
+			}
+			defer sessions.release(limited)
+
+			if !allowSSHCommand(ctx, s, limiter) {
+				return
+			}
+
+			if isSFTP(s) {
+				serveSFTP(limitCtx, s, app)
+			} else if isSCP(s) {
+				serveSCP(limitCtx, s, app)
+			} else if interactive {
+				runTUI(limitCtx, s, app.Features)
+			} else if err := commands.run(limitCtx, s, cmd); err != nil && limited.Limit() == "" {
+				exitWithError(ctx, s, err)
 			}
 
 			// End the session
@@ -131,72 +219,73 @@ func prosperoMiddleware(toptenService *topten.Service, shakespertService *shakes
 	}
 }
 
-func showSSHHelp(s ssh.Session) {
-	fmt.Fprintf(s, "\n🎩 Welcome to Prospero SSH Server!\n")
-	fmt.Fprintf(s, "═════════════════════════════════════\n\n")
-	fmt.Fprintf(s, "Available commands:\n")
-	fmt.Fprintf(s, "  topten [--color|--ascii]  - Get a random David Letterman Top 10 list\n")
-	fmt.Fprintf(s, "  shakespert works          - List all Shakespeare works\n")
-	fmt.Fprintf(s, "  shakespert work ID        - Show details for a specific work\n")
-	fmt.Fprintf(s, "  shakespert genres         - List all genres\n")
-	fmt.Fprintf(s, "  info [--color|--ascii]    - Show detailed server information\n")
-	fmt.Fprintf(s, "\nFlags:\n")
-	fmt.Fprintf(s, "  --color  - Use fancy colored output\n")
-	fmt.Fprintf(s, "  --ascii  - Use plain text output (default)\n")
-	fmt.Fprintf(s, "\nExamples:\n")
-	fmt.Fprintf(s, "  ssh user@host -p 2222 topten --color\n")
-	fmt.Fprintf(s, "  ssh user@host -p 2222 shakespert works\n")
-	fmt.Fprintf(s, "  ssh user@host -p 2222 shakespert work hamlet\n")
-	fmt.Fprintf(s, "  ssh user@host -p 2222 info --color\n")
-	fmt.Fprintf(s, "\n")
+// exitWithError writes the message of a failed command to stderr and ends
+// the session with the exit status of its class. Internal errors are also
+// logged with their cause.
+func exitWithError(ctx context.Context, s ssh.Session, err error) {
+	commandErr := features.AsCommandError(err)
+	if commandErr.Status == features.ExitInternal {
+		slog.ErrorContext(ctx, "ssh command failed", "error", err)
+	}
+	fmt.Fprintf(s.Stderr(), "%s\n", commandErr.Message)
+	_ = s.Exit(commandErr.Status)
 }
 
-func handleTopTenSSH(s ssh.Session, service *topten.Service) {
-	// Parse flags from command arguments
-	useColor := false
-	cmd := s.Command()
-	for i := 1; i < len(cmd); i++ {
-		if cmd[i] == "--color" {
-			useColor = true
-		} else if cmd[i] == "--ascii" {
-			useColor = false
-		}
-	}
+// infoNotes are the tips of the info output
+var infoNotes = []string{
+	"Use --color for fancy colored output, --ascii for plain text (default)",
+	"Use --format json|text|markdown|csv, or --json, for machine-readable output",
+}
 
-	// Set color profile based on flag (default to ASCII)
-	if useColor {
-		lipgloss.SetColorProfile(termenv.TrueColor)
-	} else {
-		lipgloss.SetColorProfile(termenv.Ascii)
+// infoCommand is the built-in command describing the server and the
+// commands of the session
+func (c *sshCommands) infoCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "info",
+		Usage:       "Show detailed server information",
+		Description: `Show the commands this session may run, with examples.`,
+		Flags:       append(features.ColorFlags(), features.FormatFlags()...),
+		Action: func(ctx *cli.Context) error {
+			return showServerInfo(ctx, c.examples())
+		},
 	}
+}
 
-	list, err := service.GetRandomList()
+func showServerInfo(c *cli.Context, examples []string) error {
+	format, err := features.OutputFormat(c)
 	if err != nil {
-		fmt.Fprintf(s, "Error getting random list: %v\n", err)
-		return
-	}
-
-	// Print the list using the appropriate formatting
-	if useColor {
-		topten.PrintList(s, list)
-	} else {
-		topten.PrintListASCII(s, list)
+		return err
 	}
-}
 
-func handleInfoSSH(s ssh.Session) {
-	// Parse flags from command arguments
-	useColor := false
-	cmd := s.Command()
-	for i := 1; i < len(cmd); i++ {
-		if cmd[i] == "--color" {
-			useColor = true
-		} else if cmd[i] == "--ascii" {
-			useColor = false
+	w := c.App.Writer
+	infos := usages(c.App)
+
+	switch format {
+	case features.FormatJSON:
+		// Follows GET /api/v1/info, listing commands rather than endpoints
+		return features.WriteJSON(w, map[string]interface{}{
+			"service":     "prospero",
+			"description": "An interactive SSH server for exploring classic literature and entertainment",
+			"commands":    infos,
+			"examples":    examples,
+			"notes":       infoNotes,
+		})
+	case features.FormatMarkdown, features.FormatCSV:
+		rows := make([][]string, len(infos))
+		for i, info := range infos {
+			rows[i] = []string{info.Command, info.Usage, info.Description}
 		}
+		header := []string{"command", "usage", "description"}
+		if format == features.FormatCSV {
+			return features.WriteCSV(w, header, rows)
+		}
+		fmt.Fprintf(w, "# Prospero SSH Server\n\n")
+		features.WriteMarkdownTable(w, header, rows)
+		return nil
 	}
 
 	// Set color profile based on flag (default to ASCII)
+	useColor := features.UseColor(c)
 	if useColor {
 		lipgloss.SetColorProfile(termenv.TrueColor)
 	} else {
@@ -260,21 +349,11 @@ func handleInfoSSH(s ssh.Session) {
 
 	content.WriteString(sectionStyle.Render("Available Commands:"))
 	content.WriteString("\n\n")
-	content.WriteString(commandStyle.Render("  topten [--color|--ascii]"))
-	content.WriteString("\n")
-	content.WriteString("    Display a random David Letterman Top 10 list\n\n")
-	content.WriteString(commandStyle.Render("  shakespert works"))
-	content.WriteString("\n")
-	content.WriteString("    List all Shakespeare works\n\n")
-	content.WriteString(commandStyle.Render("  shakespert work <id>"))
-	content.WriteString("\n")
-	content.WriteString("    Show details for a specific work\n\n")
-	content.WriteString(commandStyle.Render("  shakespert genres"))
-	content.WriteString("\n")
-	content.WriteString("    List all genres\n\n")
-	content.WriteString(commandStyle.Render("  info [--color|--ascii]"))
-	content.WriteString("\n")
-	content.WriteString("    Show this information page\n\n")
+	for _, info := range infos {
+		content.WriteString(commandStyle.Render("  " + info.Usage))
+		content.WriteString("\n")
+		content.WriteString("    " + info.Description + "\n\n")
+	}
 
 	content.WriteString(sectionStyle.Render("💡 Tips:"))
 	content.WriteString("\n\n")
@@ -284,168 +363,32 @@ func handleInfoSSH(s ssh.Session) {
 	content.WriteString("  • Use ")
 	content.WriteString(commandStyle.Render("--ascii"))
 	content.WriteString(" flag for plain text output (default)\n")
+	content.WriteString("  • Use ")
+	content.WriteString(commandStyle.Render("--format json|markdown|csv"))
+	content.WriteString(" for machine-readable output\n")
 	content.WriteString("  • Most modern terminals support colored output\n\n")
 
 	content.WriteString(sectionStyle.Render("Examples:"))
 	content.WriteString("\n\n")
-	content.WriteString(exampleStyle.Render("  ssh localhost -p 2222 topten --color"))
-	content.WriteString("\n")
-	content.WriteString(exampleStyle.Render("  ssh localhost -p 2222 shakespert works"))
-	content.WriteString("\n")
-	content.WriteString(exampleStyle.Render("  ssh localhost -p 2222 shakespert work hamlet"))
-	content.WriteString("\n")
-	content.WriteString(exampleStyle.Render("  ssh localhost -p 2222 info --color"))
-	content.WriteString("\n")
+	for _, example := range examples {
+		content.WriteString(exampleStyle.Render("  ssh localhost -p 2222 " + example))
+		content.WriteString("\n")
+	}
 
 	// Apply container and print
 	finalOutput := containerStyle.Render(content.String())
-	fmt.Fprintf(s, "\n%s\n\n", finalOutput)
-}
-
-func handleShakespertSSH(s ssh.Session, service *shakespert.Service, args []string) {
-	ctx := s.Context()
-
-	if len(args) == 0 {
-		fmt.Fprintf(s, "shakespert command requires a subcommand. Use 'works', 'work <id>', or 'genres'\n")
-		return
-	}
-
-	subcommand := strings.ToLower(args[0])
-	switch subcommand {
-	case "works":
-		works, err := service.ListWorks(ctx)
-		if err != nil {
-			fmt.Fprintf(s, "Error listing works: %v\n", err)
-			return
-		}
-
-		fmt.Fprintf(s, "\n📚 Shakespeare's Complete Works (%d works)\n", len(works))
-		fmt.Fprintf(s, "%s\n\n", strings.Repeat("═", 50))
-
-		currentGenre := ""
-		for _, work := range works {
-			if work.GenreName != currentGenre {
-				if currentGenre != "" {
-					fmt.Fprintf(s, "\n")
-				}
-				fmt.Fprintf(s, "%s:\n", work.GenreName)
-				fmt.Fprintf(s, "%s\n", strings.Repeat("─", len(work.GenreName)+1))
-				currentGenre = work.GenreName
-			}
-
-			yearStr := ""
-			if work.Date > 0 {
-				yearStr = fmt.Sprintf(" (%d)", work.Date)
-			}
-
-			fmt.Fprintf(s, "  %s - %s%s\n", work.WorkID, work.Title, yearStr)
-		}
-		fmt.Fprintf(s, "\n")
-
-	case "work":
-		if len(args) < 2 {
-			fmt.Fprintf(s, "work command requires a work ID. Example: shakespert work hamlet\n")
-			return
-		}
-
-		workID := args[1]
-		work, err := service.GetWork(ctx, workID)
-		if err != nil {
-			fmt.Fprintf(s, "Error getting work: %v\n", err)
-			return
-		}
-
-		fmt.Fprintf(s, "\n📖 %s\n", work.Title)
-		fmt.Fprintf(s, "%s\n", strings.Repeat("═", len(work.Title)+4))
-
-		if work.LongTitle != work.Title && work.LongTitle != "" {
-			fmt.Fprintf(s, "Full Title: %s\n", work.LongTitle)
-		}
-
-		fmt.Fprintf(s, "Work ID: %s\n", work.WorkID)
-		fmt.Fprintf(s, "Genre: %s (%s)\n", work.GenreName, work.GenreType)
-
-		if work.Date > 0 {
-			fmt.Fprintf(s, "Year: %d\n", work.Date)
-		}
-
-		fmt.Fprintf(s, "Words: %d\n", work.TotalWords)
-		fmt.Fprintf(s, "Paragraphs: %d\n", work.TotalParagraphs)
-
-		if work.Source != "" {
-			fmt.Fprintf(s, "Source: %s\n", work.Source)
-		}
-
-		fmt.Fprintf(s, "\n")
-
-	case "genres":
-		genres, err := service.ListGenres(ctx)
-		if err != nil {
-			fmt.Fprintf(s, "Error listing genres: %v\n", err)
-			return
-		}
-
-		fmt.Fprintf(s, "\n📚 Shakespeare Genres\n")
-		fmt.Fprintf(s, "%s\n\n", strings.Repeat("═", 18))
-
-		for _, genre := range genres {
-			genreName := genre.Genrename.String
-			if !genre.Genrename.Valid {
-				genreName = ""
-			}
-			fmt.Fprintf(s, "%s - %s\n", genre.Genretype, genreName)
-		}
-		fmt.Fprintf(s, "\n")
-
-	default:
-		fmt.Fprintf(s, "Unknown shakespert subcommand: %s\n", subcommand)
-		fmt.Fprintf(s, "Available subcommands: works, work <id>, genres\n")
-	}
+	fmt.Fprintf(w, "\n%s\n\n", finalOutput)
+	return nil
 }
 
-// extractPublicKeyFromPrivate extracts the SSH public key and fingerprint from an OpenSSH private key
-func extractPublicKeyFromPrivate(privateKeyPEM []byte) (string, string, error) {
-	block, _ := pem.Decode(privateKeyPEM)
-	if block == nil {
-		return "", "", fmt.Errorf("failed to decode PEM block")
-	}
-
-	// Parse the private key
-	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
-	if err != nil {
-		// Try OpenSSH format
-		privateKey, err = cryptossh.ParseRawPrivateKey(privateKeyPEM)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to parse private key: %w", err)
+// withHostKeys presents every host key, letting clients choose the algorithm
+func withHostKeys(signers []cryptossh.Signer) ssh.Option {
+	return func(srv *ssh.Server) error {
+		for _, signer := range signers {
+			srv.AddHostKey(signer)
 		}
+		return nil
 	}
-
-	// Extract the public key based on type
-	var publicKey interface{}
-	switch priv := privateKey.(type) {
-	case *ed25519.PrivateKey:
-		publicKey = priv.Public()
-	case ed25519.PrivateKey:
-		publicKey = priv.Public()
-	default:
-		return "", "", fmt.Errorf("unsupported key type: %T", privateKey)
-	}
-
-	// Convert to SSH public key format
-	sshPublicKey, err := cryptossh.NewPublicKey(publicKey)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to create SSH public key: %w", err)
-	}
-
-	// Format as SSH public key string
-	publicKeyStr := fmt.Sprintf("%s %s prospero-ssh-server",
-		sshPublicKey.Type(),
-		base64.StdEncoding.EncodeToString(sshPublicKey.Marshal()))
-
-	// Compute fingerprint
-	fingerprint := cryptossh.FingerprintSHA256(sshPublicKey)
-
-	return publicKeyStr, fingerprint, nil
 }
 
 // getLocalIP gets the local IP address that would be used for outbound connections
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/features"
+)
+
+// adminCommands are the built-in commands only admins may run
+func adminCommands(app *App) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "stats",
+			Usage: "Show server uptime, sessions and runtime statistics (admin)",
+			Flags: features.FormatFlags(),
+			Action: func(c *cli.Context) error {
+				return showStats(c, app)
+			},
+		},
+		{
+			Name:  "reload",
+			Usage: "Reload the SSH key allowlists and API keys (admin)",
+			Flags: features.FormatFlags(),
+			Action: func(c *cli.Context) error {
+				return reload(c, app)
+			},
+		},
+	}
+}
+
+// serverStats is the output of the stats command
+type serverStats struct {
+	Uptime      string   `json:"uptime"`
+	SSHSessions int64    `json:"ssh_sessions"`
+	SSHKeys     int      `json:"ssh_keys"`
+	APIKeys     int      `json:"api_keys"`
+	Features    []string `json:"features"`
+	GoVersion   string   `json:"go_version"`
+	Goroutines  int      `json:"goroutines"`
+	HeapInUse   uint64   `json:"heap_in_use_bytes"`
+}
+
+func showStats(c *cli.Context, app *App) error {
+	format, err := features.OutputFormat(c)
+	if err != nil {
+		return err
+	}
+	w := c.App.Writer
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var names []string
+	for _, f := range app.Features.All() {
+		names = append(names, f.Name())
+	}
+
+	stats := serverStats{
+		Uptime:      time.Since(app.Started).Truncate(time.Second).String(),
+		SSHSessions: app.sshSessions.Load(),
+		SSHKeys:     app.SSHAuth.Len(),
+		APIKeys:     app.Keys.Len(),
+		Features:    names,
+		GoVersion:   runtime.Version(),
+		Goroutines:  runtime.NumGoroutine(),
+		HeapInUse:   mem.HeapInuse,
+	}
+
+	rows := [][]string{
+		{"Uptime", stats.Uptime},
+		{"SSH sessions", fmt.Sprint(stats.SSHSessions)},
+		{"SSH keys listed", fmt.Sprint(stats.SSHKeys)},
+		{"API keys active", fmt.Sprint(stats.APIKeys)},
+		{"Features", strings.Join(stats.Features, ", ")},
+		{"Go version", stats.GoVersion},
+		{"Goroutines", fmt.Sprint(stats.Goroutines)},
+		{"Heap in use", fmt.Sprintf("%.1f MiB", float64(stats.HeapInUse)/(1<<20))},
+	}
+
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, stats)
+	case features.FormatMarkdown:
+		features.WriteMarkdownTable(w, []string{"stat", "value"}, rows)
+		return nil
+	case features.FormatCSV:
+		return features.WriteCSV(w, []string{"stat", "value"}, rows)
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-18s %s\n", row[0]+":", row[1])
+	}
+	return nil
+}
+
+// reloadResult is the JSON output of the reload command
+type reloadResult struct {
+	SSHKeys int `json:"ssh_keys"`
+	APIKeys int `json:"api_keys"`
+}
+
+// reload reads the SSH key allowlists and the API keys file again. Either
+// keeps its current keys when it fails to load. Progress lines report what
+// was reloaded, the JSON and CSV output the key counts once both have been
+// reloaded, and failures are reported by the returned error.
+func reload(c *cli.Context, app *App) error {
+	format, err := features.OutputFormat(c)
+	if err != nil {
+		return err
+	}
+
+	// Progress lines are plain text, Markdown has nothing better to offer
+	out := c.App.Writer
+	if format == features.FormatJSON || format == features.FormatCSV {
+		out = io.Discard
+	}
+
+	var failures []string
+	var errs []error
+	if err := app.SSHAuth.Reload(); err != nil {
+		failures = append(failures, fmt.Sprintf("SSH keys: %v", err))
+		errs = append(errs, fmt.Errorf("failed to reload ssh keys: %w", err))
+	} else {
+		fmt.Fprintf(out, "✓ Reloaded %d SSH keys\n", app.SSHAuth.Len())
+	}
+
+	if err := app.Keys.Reload(); err != nil {
+		failures = append(failures, fmt.Sprintf("API keys: %v", err))
+		errs = append(errs, fmt.Errorf("failed to reload api keys: %w", err))
+	} else {
+		fmt.Fprintf(out, "✓ Reloaded %d API keys\n", app.Keys.Len())
+	}
+
+	switch format {
+	case features.FormatJSON:
+		result := reloadResult{SSHKeys: app.SSHAuth.Len(), APIKeys: app.Keys.Len()}
+		if err := features.WriteJSON(c.App.Writer, result); err != nil {
+			return err
+		}
+	case features.FormatCSV:
+		err := features.WriteCSV(c.App.Writer, []string{"ssh_keys", "api_keys"},
+			[][]string{{fmt.Sprint(app.SSHAuth.Len()), fmt.Sprint(app.Keys.Len())}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		message := fmt.Sprintf("Reload failed, the keys that failed to load are unchanged: %s",
+			strings.Join(failures, "; "))
+		return features.InternalError(message, errors.Join(errs...))
+	}
+	slog.InfoContext(c.Context, "reloaded keys", "ssh_keys", app.SSHAuth.Len(), "api_keys", app.Keys.Len())
+	return nil
+}
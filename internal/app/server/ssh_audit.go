@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+
+	"prospero/internal/sshaudit"
+)
+
+// auditedSession wraps a session to count the bytes it sends, remember its
+// exit status and record its output
+type auditedSession struct {
+	ssh.Session
+
+	start    time.Time
+	bytesOut atomic.Int64
+	recorder *sshaudit.Recorder
+
+	// windows forwards the terminal resizes once they are recorded
+	windows <-chan ssh.Window
+
+	mu         sync.Mutex
+	exitStatus int
+	exited     bool
+}
+
+// newAuditedSession starts auditing s. Sessions with a terminal are
+// recorded when audit records them.
+func newAuditedSession(ctx context.Context, s ssh.Session, audit *sshaudit.Log) *auditedSession {
+	a := &auditedSession{Session: s, start: time.Now()}
+
+	pty, windows, hasPty := s.Pty()
+	if audit == nil || !hasPty {
+		return a
+	}
+
+	recorder, err := audit.Record(s.Context().SessionID(), sshaudit.Header{
+		Width:  pty.Window.Width,
+		Height: pty.Window.Height,
+		Title:  strings.Join(s.Command(), " "),
+		Env:    map[string]string{"TERM": pty.Term},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to record ssh session", "error", err)
+		return a
+	}
+	if recorder == nil {
+		return a
+	}
+
+	a.recorder = recorder
+	a.windows = recordResizes(s.Context(), windows, recorder)
+	return a
+}
+
+// recordResizes records every terminal resize before passing it on
+func recordResizes(ctx context.Context, windows <-chan ssh.Window, recorder *sshaudit.Recorder) <-chan ssh.Window {
+	forwarded := make(chan ssh.Window, 1)
+	go func() {
+		defer close(forwarded)
+		for window := range windows {
+			recorder.Resize(window.Width, window.Height)
+			select {
+			case forwarded <- window:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return forwarded
+}
+
+func (a *auditedSession) Write(p []byte) (int, error) {
+	n, err := a.Session.Write(p)
+	a.sent(p[:n])
+	return n, err
+}
+
+func (a *auditedSession) Stderr() io.ReadWriter {
+	return auditedStderr{ReadWriter: a.Session.Stderr(), session: a}
+}
+
+func (a *auditedSession) Pty() (ssh.Pty, <-chan ssh.Window, bool) {
+	pty, windows, ok := a.Session.Pty()
+	if a.windows != nil {
+		windows = a.windows
+	}
+	return pty, windows, ok
+}
+
+// Exit remembers the first exit status sent to the client
+func (a *auditedSession) Exit(code int) error {
+	a.mu.Lock()
+	if !a.exited {
+		a.exitStatus, a.exited = code, true
+	}
+	a.mu.Unlock()
+	return a.Session.Exit(code)
+}
+
+func (a *auditedSession) sent(p []byte) {
+	a.bytesOut.Add(int64(len(p)))
+	if a.recorder != nil {
+		_, _ = a.recorder.Write(p)
+	}
+}
+
+// finish closes the recording and returns the audit event of the session,
+// which is interactive when it had a terminal. A session that never exited
+// explicitly exits with status 0.
+func (a *auditedSession) finish(ctx context.Context, interactive bool) sshaudit.Event {
+	identity := sessionIdentity(a)
+	a.mu.Lock()
+	exitStatus := a.exitStatus
+	a.mu.Unlock()
+
+	event := sshaudit.Event{
+		SessionID:      a.Context().SessionID(),
+		Start:          a.start.UTC(),
+		DurationMS:     time.Since(a.start).Milliseconds(),
+		RemoteAddr:     a.RemoteAddr().String(),
+		User:           a.User(),
+		AuthMethod:     identity.Method,
+		Role:           identity.Role,
+		KeyFingerprint: identity.Fingerprint,
+		Command:        strings.Join(a.Command(), " "),
+		Subsystem:      a.Subsystem(),
+		Interactive:    interactive,
+		ExitStatus:     exitStatus,
+		BytesOut:       a.bytesOut.Load(),
+	}
+	if a.recorder != nil {
+		if err := a.recorder.Close(); err != nil {
+			slog.WarnContext(ctx, "failed to save ssh session recording", "error", err)
+		}
+		event.Recording = a.recorder.Name()
+	}
+	return event
+}
+
+// auditedStderr counts and records what a session writes to stderr
+type auditedStderr struct {
+	io.ReadWriter
+	session *auditedSession
+}
+
+func (w auditedStderr) Write(p []byte) (int, error) {
+	n, err := w.ReadWriter.Write(p)
+	w.session.sent(p[:n])
+	return n, err
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/sshaudit"
+	"prospero/internal/sshauth"
+)
+
+func TestAuditedSession(t *testing.T) {
+	t.Run("should write the fingerprint of anonymous keys", func(t *testing.T) {
+		dir := t.TempDir()
+		audit, err := sshaudit.Open(dir, false)
+		require.NoError(t, err)
+		defer audit.Close()
+
+		authn, err := newSSHAuthenticator(config.Default().SSH.Auth)
+		require.NoError(t, err)
+		addr := startTestSSHServer(t, func(s ssh.Session) {
+			audited := newAuditedSession(context.Background(), s, audit)
+			fmt.Fprint(audited, "o brave new world")
+			_ = audited.Exit(0)
+			assert.NoError(t, audit.Write(audited.finish(context.Background(), false)))
+		}, withSSHAuth(authn))
+
+		key := newClientKey(t)
+		output, err := dialSSH(t, addr, "guest", cryptossh.PublicKeys(key), "topten")
+		require.NoError(t, err)
+		assert.Equal(t, "o brave new world", output)
+
+		events, err := sshaudit.ReadEvents(dir)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, sshauth.MethodAnonymous, events[0].AuthMethod)
+		assert.Equal(t, sshauth.RoleUser, events[0].Role)
+		assert.Equal(t, cryptossh.FingerprintSHA256(key.PublicKey()), events[0].KeyFingerprint)
+		assert.Equal(t, "topten", events[0].Command)
+		assert.EqualValues(t, len("o brave new world"), events[0].BytesOut)
+	})
+}
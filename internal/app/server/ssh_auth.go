@@ -0,0 +1,135 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/metrics"
+	"prospero/internal/sshauth"
+)
+
+// Permission extensions holding the identity of a connection. Keeping it in
+// the permissions returned by the successful callback ties it to the key
+// that authenticated, not to the last key the client offered.
+const (
+	identityUserExt        = "prospero-user"
+	identityRoleExt        = "prospero-role"
+	identityMethodExt      = "prospero-method"
+	identityFingerprintExt = "prospero-fingerprint"
+)
+
+// newSSHAuthenticator builds the authenticator configured by cfg
+func newSSHAuthenticator(cfg config.SSHAuthConfig) (*sshauth.Authenticator, error) {
+	users := make(map[string]sshauth.User, len(cfg.Users))
+	for name, user := range cfg.Users {
+		users[name] = sshauth.User{Role: user.Role, PasswordHash: user.PasswordHash}
+	}
+	return sshauth.New(sshauth.Options{
+		Anonymous:      cfg.Anonymous,
+		AnonymousRole:  cfg.AnonymousRole,
+		AuthorizedKeys: cfg.AuthorizedKeys,
+		KeysDir:        cfg.KeysDir,
+		KeyRole:        cfg.KeyRole,
+		Users:          users,
+	})
+}
+
+// sshAuthSummary describes the enabled authentication methods for the banner
+func sshAuthSummary(cfg config.SSHAuthConfig, authn *sshauth.Authenticator) string {
+	var methods []string
+	if cfg.AuthorizedKeys || cfg.KeysDir != "" {
+		methods = append(methods, fmt.Sprintf("%d listed keys", authn.Len()))
+	}
+	if len(cfg.Users) > 0 {
+		methods = append(methods, fmt.Sprintf("%d password users", len(cfg.Users)))
+	}
+	if cfg.Anonymous {
+		methods = append(methods, fmt.Sprintf("anonymous as %s", cfg.AnonymousRole))
+	}
+	return strings.Join(methods, ", ")
+}
+
+// withSSHAuth authenticates clients with authn. Listed keys are accepted by
+// public-key authentication, and so are unlisted keys, anonymously, when
+// that is allowed. Keyboard-interactive authentication asks password users
+// for their password and lets anyone else in anonymously when that is
+// allowed; password authentication does the same for clients that only
+// offer a password.
+func withSSHAuth(authn *sshauth.Authenticator) ssh.Option {
+	return func(srv *ssh.Server) error {
+		srv.PublicKeyHandler = func(ctx ssh.Context, key ssh.PublicKey) bool {
+			if identity, ok := authn.PublicKey(ctx.User(), key); ok {
+				return authenticated(ctx, sshauth.MethodPublicKey, identity, true)
+			}
+			if identity, ok := authn.AnonymousKey(ctx.User(), key); ok {
+				return authenticated(ctx, sshauth.MethodAnonymous, identity, true)
+			}
+			return authenticated(ctx, sshauth.MethodPublicKey, sshauth.Identity{}, false)
+		}
+
+		srv.PasswordHandler = func(ctx ssh.Context, password string) bool {
+			if !authn.HasPassword(ctx.User()) {
+				identity, ok := authn.Anonymous(ctx.User())
+				return authenticated(ctx, sshauth.MethodAnonymous, identity, ok)
+			}
+			identity, ok := authn.Password(ctx.User(), password)
+			return authenticated(ctx, sshauth.MethodPassword, identity, ok)
+		}
+
+		srv.KeyboardInteractiveHandler = func(ctx ssh.Context, challenge cryptossh.KeyboardInteractiveChallenge) bool {
+			if !authn.HasPassword(ctx.User()) {
+				identity, ok := authn.Anonymous(ctx.User())
+				return authenticated(ctx, sshauth.MethodAnonymous, identity, ok)
+			}
+
+			answers, err := challenge(ctx.User(), "", []string{"Password: "}, []bool{false})
+			if err != nil || len(answers) != 1 {
+				return authenticated(ctx, sshauth.MethodPassword, sshauth.Identity{}, false)
+			}
+			identity, ok := authn.Password(ctx.User(), answers[0])
+			return authenticated(ctx, sshauth.MethodPassword, identity, ok)
+		}
+		return nil
+	}
+}
+
+// authenticated records an authentication attempt and, when it succeeded,
+// stores identity with the connection permissions
+func authenticated(ctx ssh.Context, method string, identity sshauth.Identity, ok bool) bool {
+	metrics.IncSSHAuth(method, ok)
+
+	// Rejected keys are routine, as clients offer every key they hold
+	if !ok {
+		if method != sshauth.MethodPublicKey {
+			slog.Warn("ssh authentication failed",
+				"method", method, "user", ctx.User(), "remote_addr", ctx.RemoteAddr().String())
+		}
+		return false
+	}
+
+	permissions := ctx.Permissions()
+	if permissions.Extensions == nil {
+		permissions.Extensions = map[string]string{}
+	}
+	permissions.Extensions[identityUserExt] = identity.User
+	permissions.Extensions[identityRoleExt] = identity.Role
+	permissions.Extensions[identityMethodExt] = identity.Method
+	permissions.Extensions[identityFingerprintExt] = identity.Fingerprint
+	return true
+}
+
+// sessionIdentity returns the identity the session authenticated as
+func sessionIdentity(s ssh.Session) sshauth.Identity {
+	extensions := s.Context().Permissions().Extensions
+	return sshauth.Identity{
+		User:        extensions[identityUserExt],
+		Role:        extensions[identityRoleExt],
+		Method:      extensions[identityMethodExt],
+		Fingerprint: extensions[identityFingerprintExt],
+	}
+}
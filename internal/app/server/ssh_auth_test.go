@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/hostkey"
+	"prospero/internal/sshauth"
+)
+
+// startTestSSHServer serves handler with a fresh host key on a random local
+// port and returns its address
+func startTestSSHServer(t *testing.T, handler ssh.Handler, options ...ssh.Option) string {
+	t.Helper()
+	key, err := hostkey.Generate(hostkey.Ed25519)
+	require.NoError(t, err)
+	signers, err := hostkey.Parse(key)
+	require.NoError(t, err)
+
+	srv := &ssh.Server{Handler: handler}
+	for _, option := range append([]ssh.Option{withHostKeys(signers)}, options...) {
+		require.NoError(t, srv.SetOption(option))
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return listener.Addr().String()
+}
+
+// newClientKey creates a client key pair
+func newClientKey(t *testing.T) cryptossh.Signer {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := cryptossh.NewSignerFromKey(private)
+	require.NoError(t, err)
+	return signer
+}
+
+// dialSSH connects to addr as user offering only auth, and returns the
+// output of a session running command
+func dialSSH(t *testing.T, addr, user string, auth cryptossh.AuthMethod, command string) (string, error) {
+	t.Helper()
+	client, err := cryptossh.Dial("tcp", addr, &cryptossh.ClientConfig{
+		User:            user,
+		Auth:            []cryptossh.AuthMethod{auth},
+		HostKeyCallback: cryptossh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	output, err := session.Output(command)
+	return string(output), err
+}
+
+// printIdentity writes the identity a session authenticated as
+func printIdentity(s ssh.Session) {
+	identity := sessionIdentity(s)
+	fmt.Fprintf(s, "%s %s %s %s", identity.Method, identity.Role, identity.User, identity.Fingerprint)
+}
+
+func TestWithSSHAuth(t *testing.T) {
+	newServer := func(t *testing.T, cfg config.SSHAuthConfig) string {
+		authn, err := newSSHAuthenticator(cfg)
+		require.NoError(t, err)
+		return startTestSSHServer(t, printIdentity, withSSHAuth(authn))
+	}
+
+	t.Run("should let unlisted keys in anonymously by default", func(t *testing.T) {
+		addr := newServer(t, config.Default().SSH.Auth)
+		key := newClientKey(t)
+
+		output, err := dialSSH(t, addr, "guest", cryptossh.PublicKeys(key), "")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%s %s guest %s",
+			sshauth.MethodAnonymous, sshauth.RoleUser, cryptossh.FingerprintSHA256(key.PublicKey())), output)
+	})
+
+	t.Run("should refuse unlisted keys without anonymous access", func(t *testing.T) {
+		cfg := config.Default().SSH.Auth
+		cfg.Anonymous = false
+		cfg.KeysDir = t.TempDir()
+		listed := cryptossh.MarshalAuthorizedKey(newClientKey(t).PublicKey())
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.KeysDir, "ariel.keys"), listed, 0o600))
+		addr := newServer(t, cfg)
+
+		_, err := dialSSH(t, addr, "guest", cryptossh.PublicKeys(newClientKey(t)), "")
+		assert.ErrorContains(t, err, "unable to authenticate")
+	})
+
+	t.Run("should ask password users for their password", func(t *testing.T) {
+		hash, err := sshauth.HashPassword("full fathom five")
+		require.NoError(t, err)
+		cfg := config.Default().SSH.Auth
+		cfg.Users = map[string]config.SSHUser{"miranda": {Role: sshauth.RoleUser, PasswordHash: hash}}
+		addr := newServer(t, cfg)
+
+		_, err = dialSSH(t, addr, "miranda", cryptossh.PublicKeys(newClientKey(t)), "")
+		assert.ErrorContains(t, err, "unable to authenticate")
+
+		output, err := dialSSH(t, addr, "miranda", cryptossh.Password("full fathom five"), "")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%s %s miranda ", sshauth.MethodPassword, sshauth.RoleUser), output)
+	})
+}
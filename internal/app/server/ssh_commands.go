@@ -0,0 +1,295 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/features"
+	"prospero/internal/ratelimit"
+	"prospero/internal/sshauth"
+)
+
+// sshCommands builds the command tree SSH command lines run on: the CLI
+// commands of every feature followed by the built-in SSH commands. Flags,
+// aliases and help are those of the local CLI.
+type sshCommands struct {
+	app     *App
+	limiter *ratelimit.Limiter
+}
+
+// newSSHCommands creates the command tree of app's features, counting
+// command lines run from the shell against limiter
+func newSSHCommands(app *App, limiter *ratelimit.Limiter) *sshCommands {
+	return &sshCommands{app: app, limiter: limiter}
+}
+
+// newApp returns the CLI application running command lines for s, bound to
+// its stdin, stdout and stderr. Commands the role may not run are hidden
+// from help and completion and refused. urfave/cli keeps parsing state in
+// the commands, so every command line needs its own application. s is nil
+// when the application is only inspected.
+func (c *sshCommands) newApp(s ssh.Session, role string) *cli.App {
+	commands := c.app.Features.Commands()
+	for _, command := range commands {
+		markUsageErrors(command)
+	}
+
+	builtins := []*cli.Command{c.infoCommand(), c.shellCommand(s)}
+	for _, command := range adminCommands(c.app) {
+		restrict(command, sshauth.RoleAdmin, role)
+		builtins = append(builtins, command)
+	}
+	for _, command := range builtins {
+		markUsageErrors(command)
+	}
+
+	app := &cli.App{
+		Name:      "prospero",
+		HelpName:  "ssh <host>",
+		Usage:     "Explore classic literature and entertainment over SSH",
+		UsageText: "ssh <host> -p <port> <command> [command options] [arguments...]",
+		Description: "Connect with a terminal and no command for the interactive browser.\n\n" +
+			"Examples:\n   ssh <host> " + strings.Join(c.examples(), "\n   ssh <host> "),
+		HideVersion: true,
+		Commands:    append(commands, builtins...),
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() > 0 {
+				return features.UsageError("Unknown command: %s. Run help for the list of commands", ctx.Args().First())
+			}
+			return cli.ShowAppHelp(ctx)
+		},
+		OnUsageError: usageError,
+		// Exit statuses are set on the session, never by exiting the process
+		ExitErrHandler: func(*cli.Context, error) {},
+	}
+	if s != nil {
+		app.Reader = s
+		app.Writer = s
+		app.ErrWriter = s.Stderr()
+	}
+	app.Setup()
+	return app
+}
+
+// run runs the command line args on s and returns the failure of the
+// command. Flag and help errors of urfave/cli are usage errors.
+func (c *sshCommands) run(ctx context.Context, s ssh.Session, args []string) error {
+	app := c.newApp(s, sessionIdentity(s).Role)
+	err := app.RunContext(ctx, append([]string{app.Name}, args...))
+
+	var commandErr *features.CommandError
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) && !errors.As(err, &commandErr) {
+		return features.UsageError("%s", exitErr.Error())
+	}
+	return err
+}
+
+// usageError marks a flag parsing failure as a usage error
+func usageError(c *cli.Context, err error, isSubcommand bool) error {
+	return features.UsageError("%v", err)
+}
+
+// markUsageErrors makes the flag parsing failures of command and its
+// subcommands usage errors
+func markUsageErrors(command *cli.Command) {
+	command.OnUsageError = usageError
+	for _, sub := range command.Subcommands {
+		markUsageErrors(sub)
+	}
+}
+
+// restrict hides command from clients whose role does not allow required,
+// and refuses to run it for them
+func restrict(command *cli.Command, required, role string) {
+	if sshauth.Allows(role, required) {
+		return
+	}
+	command.Hidden = true
+	command.Before = func(c *cli.Context) error {
+		slog.WarnContext(c.Context, "ssh command denied", "command", command.Name, "required_role", required)
+		return features.DeniedError("Permission denied: %s requires the %s role", command.Name, required)
+	}
+}
+
+// examples returns example command lines from every feature
+func (c *sshCommands) examples() []string {
+	var examples []string
+	for _, f := range c.app.Features.All() {
+		examples = append(examples, f.Help().Examples...)
+	}
+	return append(examples, "info --color", "-t shell")
+}
+
+// names returns the name of every command open to all clients
+func (c *sshCommands) names() []string {
+	var names []string
+	for _, command := range c.newApp(nil, sshauth.RoleUser).VisibleCommands() {
+		if command.Name != "help" {
+			names = append(names, command.Name)
+		}
+	}
+	return names
+}
+
+// label maps a command line to a bounded metrics label: the command and
+// subcommand names, whatever alias was used and wherever flags were put
+func (c *sshCommands) label(args []string) string {
+	if len(args) == 0 {
+		return "help"
+	}
+	command := c.newApp(nil, sshauth.RoleAdmin).Command(args[0])
+	if command == nil {
+		return "unknown"
+	}
+
+	label := command.Name
+	expectValue := false
+	for _, arg := range args[1:] {
+		if expectValue {
+			expectValue = false
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			expectValue = takesValue(command, arg)
+			continue
+		}
+		if sub := command.Command(arg); sub != nil {
+			label += " " + sub.Name
+		}
+		break
+	}
+	return label
+}
+
+// commandUsage is one form of a command in help listings
+type commandUsage struct {
+	Command     string `json:"command"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+// usages lists the visible commands of app, a line per subcommand for
+// commands that have them
+func usages(app *cli.App) []commandUsage {
+	var usages []commandUsage
+	for _, command := range app.VisibleCommands() {
+		if command.Name == "help" {
+			continue
+		}
+		var subcommands []*cli.Command
+		for _, sub := range command.VisibleCommands() {
+			if sub.Name != "help" {
+				subcommands = append(subcommands, sub)
+			}
+		}
+		if len(subcommands) == 0 {
+			usages = append(usages, commandUsage{
+				Command:     command.Name,
+				Usage:       strings.TrimSpace(command.Name + " " + command.ArgsUsage),
+				Description: command.Usage,
+			})
+		}
+		for _, sub := range subcommands {
+			usages = append(usages, commandUsage{
+				Command:     command.Name,
+				Usage:       strings.TrimSpace(command.Name + " " + sub.Name + " " + sub.ArgsUsage),
+				Description: sub.Usage,
+			})
+		}
+	}
+	return usages
+}
+
+// completions returns the candidates for the word following words: the
+// commands role may run, then the subcommands, flags and arguments of the
+// command those words name. Arguments come from the command's BashComplete.
+func (c *sshCommands) completions(ctx context.Context, role string, words []string) []string {
+	app := c.newApp(nil, role)
+	if len(words) == 0 {
+		var names []string
+		for _, command := range app.VisibleCommands() {
+			if command.Name != shellName && command.Name != "help" {
+				names = append(names, command.Name)
+			}
+		}
+		names = append(names, shellBuiltins...)
+		slices.Sort(names)
+		return names
+	}
+
+	command := app.Command(words[0])
+	if command == nil || command.Hidden {
+		return nil
+	}
+
+	// Walk down the subcommands, skipping flags and their values
+	arguments := 0
+	expectValue := false
+	for _, word := range words[1:] {
+		switch {
+		case expectValue:
+			expectValue = false
+		case strings.HasPrefix(word, "-"):
+			expectValue = takesValue(command, word)
+		case arguments == 0 && command.Command(word) != nil:
+			command = command.Command(word)
+		default:
+			arguments++
+		}
+	}
+	if expectValue {
+		if strings.TrimLeft(words[len(words)-1], "-") == "format" {
+			return features.Formats
+		}
+		return nil
+	}
+
+	var candidates []string
+	if arguments == 0 {
+		for _, sub := range command.VisibleCommands() {
+			if sub.Name != "help" {
+				candidates = append(candidates, sub.Name)
+			}
+		}
+		if command.BashComplete != nil {
+			var out bytes.Buffer
+			app.Writer = &out
+			completion := cli.NewContext(app, nil, nil)
+			completion.Context = ctx
+			command.BashComplete(completion)
+			candidates = append(candidates, strings.Fields(out.String())...)
+		}
+	}
+	for _, flag := range command.VisibleFlags() {
+		if name := flag.Names()[0]; name != "help" && len(name) > 1 {
+			candidates = append(candidates, "--"+name)
+		}
+	}
+	return candidates
+}
+
+// takesValue reports whether the flag written as arg takes the next word as
+// its value
+func takesValue(command *cli.Command, arg string) bool {
+	name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	if hasValue {
+		return false
+	}
+	for _, flag := range command.Flags {
+		if !slices.Contains(flag.Names(), name) {
+			continue
+		}
+		if valued, ok := flag.(interface{ TakesValue() bool }); ok {
+			return valued.TakesValue()
+		}
+	}
+	return false
+}
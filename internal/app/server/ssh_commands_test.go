@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/sshauth"
+)
+
+// booksFeature is a feature with a command tree like the real ones: an
+// alias, output flags and subcommands completing their arguments
+type booksFeature struct{}
+
+func (booksFeature) Name() string                                     { return "books" }
+func (booksFeature) Help() features.Help                              { return features.Help{} }
+func (booksFeature) Open(context.Context) error                       { return nil }
+func (booksFeature) Close() error                                     { return nil }
+func (booksFeature) Check(context.Context) error                      { return nil }
+func (booksFeature) MCPTools() []features.MCPTool                     { return nil }
+func (booksFeature) MCPPrompts() []features.MCPPrompt                 { return nil }
+func (booksFeature) RegisterRoutes(chi.Router, features.RouteOptions) {}
+
+func (booksFeature) Commands() []*cli.Command {
+	return []*cli.Command{{
+		Name:    "books",
+		Aliases: []string{"library"},
+		Flags:   features.FormatFlags(),
+		Subcommands: []*cli.Command{
+			{Name: "list", Flags: features.FormatFlags()},
+			{
+				Name:  "show",
+				Flags: features.FormatFlags(),
+				BashComplete: func(c *cli.Context) {
+					fmt.Fprintln(c.App.Writer, "dune")
+				},
+			},
+		},
+	}}
+}
+
+func newTestSSHCommands() *sshCommands {
+	return newSSHCommands(&App{Features: features.NewRegistry(booksFeature{})}, nil)
+}
+
+func TestSSHCommands_Label(t *testing.T) {
+	commands := newTestSSHCommands()
+
+	t.Run("should name the command and subcommand", func(t *testing.T) {
+		assert.Equal(t, "books list", commands.label([]string{"books", "list"}))
+	})
+
+	t.Run("should name commands by their name whatever alias was used", func(t *testing.T) {
+		assert.Equal(t, "books list", commands.label([]string{"library", "list"}))
+	})
+
+	t.Run("should leave out unknown subcommands and arguments", func(t *testing.T) {
+		assert.Equal(t, "books", commands.label([]string{"books", "dune"}))
+		assert.Equal(t, "books", commands.label([]string{"books"}))
+	})
+
+	t.Run("should skip flags and their values", func(t *testing.T) {
+		assert.Equal(t, "books list", commands.label([]string{"books", "--format", "json", "list"}))
+		assert.Equal(t, "books list", commands.label([]string{"books", "--format=json", "list"}))
+		assert.Equal(t, "books list", commands.label([]string{"books", "--json", "list"}))
+	})
+
+	t.Run("should label unknown commands and empty command lines", func(t *testing.T) {
+		assert.Equal(t, "unknown", commands.label([]string{"poems"}))
+		assert.Equal(t, "help", commands.label(nil))
+	})
+}
+
+func TestSSHCommands_Completions(t *testing.T) {
+	commands := newTestSSHCommands()
+	ctx := context.Background()
+
+	t.Run("should complete the commands of the role", func(t *testing.T) {
+		user := commands.completions(ctx, sshauth.RoleUser, nil)
+		assert.Contains(t, user, "books")
+		assert.NotContains(t, user, "stats")
+		assert.NotContains(t, user, "reload")
+
+		admin := commands.completions(ctx, sshauth.RoleAdmin, nil)
+		assert.Contains(t, admin, "stats")
+		assert.Contains(t, admin, "reload")
+	})
+
+	t.Run("should complete subcommands and flags", func(t *testing.T) {
+		candidates := commands.completions(ctx, sshauth.RoleUser, []string{"library"})
+		assert.Contains(t, candidates, "list")
+		assert.Contains(t, candidates, "show")
+		assert.Contains(t, candidates, "--format")
+	})
+
+	t.Run("should defer arguments to the command's completer", func(t *testing.T) {
+		candidates := commands.completions(ctx, sshauth.RoleUser, []string{"books", "--format", "json", "show"})
+		assert.Contains(t, candidates, "dune")
+	})
+
+	t.Run("should complete format values", func(t *testing.T) {
+		assert.Equal(t, features.Formats, commands.completions(ctx, sshauth.RoleUser, []string{"books", "--format"}))
+	})
+
+	t.Run("should not complete unknown or hidden commands", func(t *testing.T) {
+		assert.Empty(t, commands.completions(ctx, sshauth.RoleUser, []string{"poems"}))
+		assert.Empty(t, commands.completions(ctx, sshauth.RoleUser, []string{"stats"}))
+	})
+}
+
+func TestTakesValue(t *testing.T) {
+	command := booksFeature{}.Commands()[0]
+
+	t.Run("should take the next word for valued flags", func(t *testing.T) {
+		assert.True(t, takesValue(command, "--format"))
+	})
+
+	t.Run("should not take the next word for inline values", func(t *testing.T) {
+		assert.False(t, takesValue(command, "--format=json"))
+	})
+
+	t.Run("should not take the next word for boolean or unknown flags", func(t *testing.T) {
+		assert.False(t, takesValue(command, "--json"))
+		assert.False(t, takesValue(command, "--genre"))
+	})
+}
+
+func TestSSHCommands_Restrict(t *testing.T) {
+	commands := newTestSSHCommands()
+	authn, err := newSSHAuthenticator(config.Default().SSH.Auth)
+	require.NoError(t, err)
+	addr := startTestSSHServer(t, func(s ssh.Session) {
+		if err := commands.run(s.Context(), s, s.Command()); err != nil {
+			exitWithError(s.Context(), s, err)
+		}
+	}, withSSHAuth(authn))
+
+	for _, name := range []string{"stats", "reload"} {
+		t.Run("should refuse "+name+" to user sessions", func(t *testing.T) {
+			client, err := cryptossh.Dial("tcp", addr, &cryptossh.ClientConfig{
+				User:            "guest",
+				Auth:            []cryptossh.AuthMethod{cryptossh.PublicKeys(newClientKey(t))},
+				HostKeyCallback: cryptossh.InsecureIgnoreHostKey(),
+			})
+			require.NoError(t, err)
+			defer client.Close()
+			session, err := client.NewSession()
+			require.NoError(t, err)
+			defer session.Close()
+
+			var stdout, stderr bytes.Buffer
+			session.Stdout = &stdout
+			session.Stderr = &stderr
+			err = session.Run(name)
+
+			var exitErr *cryptossh.ExitError
+			require.True(t, errors.As(err, &exitErr), "expected an exit status, got %v", err)
+			assert.Equal(t, features.ExitDenied, exitErr.ExitStatus())
+			assert.Contains(t, stderr.String(), "Permission denied: "+name+" requires the admin role")
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"path"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish/scp"
+
+	"prospero/internal/features"
+	"prospero/internal/sftp"
+	"prospero/internal/textfs"
+)
+
+// sftpSubsystem is the subsystem SFTP clients, and scp in its default
+// mode, request
+const sftpSubsystem = "sftp"
+
+// withSFTP routes SFTP sessions through the server's session handler, so
+// they are logged, counted and rate limited like any other session
+func withSFTP() ssh.Option {
+	return func(srv *ssh.Server) error {
+		if srv.SubsystemHandlers == nil {
+			srv.SubsystemHandlers = map[string]ssh.SubsystemHandler{}
+		}
+		srv.SubsystemHandlers[sftpSubsystem] = func(s ssh.Session) {
+			srv.Handler(s)
+		}
+		return nil
+	}
+}
+
+// isSFTP reports whether s is an SFTP session
+func isSFTP(s ssh.Session) bool {
+	return s.Subsystem() == sftpSubsystem
+}
+
+// isSCP reports whether s runs scp, which copies files with its own
+// protocol when the client passes -O
+func isSCP(s ssh.Session) bool {
+	return scp.GetInfo(s.Command()).Ok
+}
+
+// exportFS is the read-only filesystem of the texts for one session
+func exportFS(ctx context.Context, app *App) *textfs.FS {
+	return textfs.New(ctx, textfsSources(app.Features), app.Started)
+}
+
+// textfsSources finds the services the exported filesystem is generated
+// from among those the features export
+func textfsSources(registry *features.Registry) textfs.Sources {
+	var sources textfs.Sources
+	for _, service := range registry.Exports() {
+		if library, ok := service.(textfs.Library); ok {
+			sources.Library = library
+		}
+		if lists, ok := service.(textfs.TopTen); ok {
+			sources.TopTen = lists
+		}
+	}
+	return sources
+}
+
+// serveSFTP serves the texts to an SFTP client until it disconnects
+func serveSFTP(ctx context.Context, s ssh.Session, app *App) {
+	if err := sftp.Serve(ctx, s, exportFS(ctx, app)); err != nil {
+		slog.WarnContext(ctx, "sftp session failed", "error", err)
+		_ = s.Exit(1)
+	}
+}
+
+// serveSCP sends the requested texts to an scp client. Uploads are refused.
+func serveSCP(ctx context.Context, s ssh.Session, app *App) {
+	handler := scpHandler{scp.NewFSReadHandler(exportFS(ctx, app))}
+	scp.Middleware(handler, nil)(func(ssh.Session) {})(s)
+}
+
+// scpHandler resolves the paths clients ask for against the root of the
+// filesystem, so that "/works" and "works" name the same directory
+type scpHandler struct {
+	scp.CopyToClientHandler
+}
+
+func (h scpHandler) Glob(s ssh.Session, pattern string) ([]string, error) {
+	pattern = path.Clean("/" + pattern)
+	if pattern == "/" {
+		return h.CopyToClientHandler.Glob(s, ".")
+	}
+	return h.CopyToClientHandler.Glob(s, pattern[1:])
+}
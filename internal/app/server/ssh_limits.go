@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/metrics"
+	"prospero/internal/ratelimit"
+)
+
+// Session limits, as named in logs, metrics and audit events
+const (
+	limitIdleTimeout      = "idle_timeout"
+	limitMaxDuration      = "max_duration"
+	limitMaxSessions      = "max_sessions"
+	limitMaxSessionsPerIP = "max_sessions_per_ip"
+	limitMaxBytesOut      = "max_bytes_out"
+)
+
+// limitGrace is how long a session over a limit has to wind down, such as
+// the TUI restoring the terminal, before it is closed regardless
+const limitGrace = 2 * time.Second
+
+// connectionGrace is how much longer than the session limits connections
+// may stay open, so that connections without a session, or whose client
+// ignores the session being closed, are dropped too
+const connectionGrace = time.Minute
+
+// errSessionLimit is returned by writes refused because the session sent
+// its maximum
+var errSessionLimit = errors.New("ssh session limit reached")
+
+// withConnectionTimeouts drops connections that idle or last longer than
+// the session limits allow, by connectionGrace. Sessions are closed with a
+// message before that happens.
+func withConnectionTimeouts(cfg config.SSHLimitsConfig) ssh.Option {
+	return func(srv *ssh.Server) error {
+		if cfg.IdleTimeout > 0 {
+			srv.IdleTimeout = cfg.IdleTimeout + connectionGrace
+		}
+		if cfg.MaxDuration > 0 {
+			srv.MaxTimeout = cfg.MaxDuration + connectionGrace
+		}
+		return nil
+	}
+}
+
+// sessionLimits enforces ssh.limits: it counts the open sessions, in total
+// and per remote address, and closes sessions that idle, last or send too
+// much
+type sessionLimits struct {
+	cfg config.SSHLimitsConfig
+
+	mu     sync.Mutex
+	open   int
+	byAddr map[string]int
+}
+
+func newSessionLimits(cfg config.SSHLimitsConfig) *sessionLimits {
+	return &sessionLimits{cfg: cfg, byAddr: map[string]int{}}
+}
+
+// wrap starts enforcing the limits of s. The returned context is cancelled
+// when s goes over a limit, and end must be called once the session is
+// done.
+func (l *sessionLimits) wrap(ctx context.Context, s ssh.Session) (*limitedSession, context.Context) {
+	_, _, hasPty := s.Pty()
+	limitCtx, cancel := context.WithCancel(ctx)
+	limited := &limitedSession{
+		Session: s,
+		cfg:     l.cfg,
+		addr:    ratelimit.ClientAddr(s.RemoteAddr().String()),
+		pty:     hasPty,
+		logCtx:  ctx,
+		cancel:  cancel,
+	}
+	limited.touch()
+
+	limited.mu.Lock()
+	defer limited.mu.Unlock()
+	if l.cfg.IdleTimeout > 0 {
+		limited.idleTimer = time.AfterFunc(l.cfg.IdleTimeout, limited.checkIdle)
+	}
+	if l.cfg.MaxDuration > 0 {
+		limited.durationTimer = time.AfterFunc(l.cfg.MaxDuration, func() {
+			limited.hit(limitMaxDuration)
+		})
+	}
+	return limited, limitCtx
+}
+
+// admit counts s as open, or refuses it when it would go over the number of
+// sessions allowed at once. Admitted sessions are released when they end.
+func (l *sessionLimits) admit(s *limitedSession) bool {
+	l.mu.Lock()
+	limit := ""
+	switch {
+	case l.cfg.MaxSessions > 0 && l.open >= l.cfg.MaxSessions:
+		limit = limitMaxSessions
+	case l.cfg.MaxSessionsPerIP > 0 && l.byAddr[s.addr] >= l.cfg.MaxSessionsPerIP:
+		limit = limitMaxSessionsPerIP
+	default:
+		l.open++
+		l.byAddr[s.addr]++
+	}
+	l.mu.Unlock()
+
+	if limit != "" {
+		s.hit(limit)
+		return false
+	}
+	return true
+}
+
+// release stops counting the admitted session s
+func (l *sessionLimits) release(s *limitedSession) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.open--
+	if l.byAddr[s.addr]--; l.byAddr[s.addr] <= 0 {
+		delete(l.byAddr, s.addr)
+	}
+}
+
+// limitedSession wraps a session to notice when it idles or sends too much.
+// Once it goes over a limit its context is cancelled, and the session is
+// closed with a message when its handler returns or limitGrace has passed.
+type limitedSession struct {
+	ssh.Session
+
+	cfg    config.SSHLimitsConfig
+	addr   string
+	pty    bool
+	logCtx context.Context
+	cancel context.CancelFunc
+
+	lastActive atomic.Int64
+	bytesOut   atomic.Int64
+
+	mu            sync.Mutex
+	limit         string
+	ended         bool
+	idleTimer     *time.Timer
+	durationTimer *time.Timer
+	closeOnce     sync.Once
+}
+
+func (l *limitedSession) Read(p []byte) (int, error) {
+	n, err := l.Session.Read(p)
+	if n > 0 {
+		l.touch()
+	}
+	return n, err
+}
+
+func (l *limitedSession) Write(p []byte) (int, error) {
+	if !l.send(len(p)) {
+		return 0, errSessionLimit
+	}
+	n, err := l.Session.Write(p)
+	l.touch()
+	return n, err
+}
+
+func (l *limitedSession) Stderr() io.ReadWriter {
+	return limitedStderr{ReadWriter: l.Session.Stderr(), session: l}
+}
+
+// Limit returns the limit the session went over, if any
+func (l *limitedSession) Limit() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// touch records activity on the session
+func (l *limitedSession) touch() {
+	l.lastActive.Store(time.Now().UnixNano())
+}
+
+// send counts n bytes about to be sent, and reports whether the session may
+// send them
+func (l *limitedSession) send(n int) bool {
+	if l.cfg.MaxBytesOut > 0 && l.bytesOut.Add(int64(n)) > l.cfg.MaxBytesOut {
+		l.hit(limitMaxBytesOut)
+		return false
+	}
+	return true
+}
+
+// checkIdle closes the session once it has been inactive for the idle
+// timeout, and otherwise checks again when it could have been
+func (l *limitedSession) checkIdle() {
+	idle := time.Since(time.Unix(0, l.lastActive.Load()))
+	if idle >= l.cfg.IdleTimeout {
+		l.hit(limitIdleTimeout)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.ended {
+		l.idleTimer.Reset(l.cfg.IdleTimeout - idle)
+	}
+}
+
+// hit records that the session went over limit, which is logged and
+// counted once, and winds the session down
+func (l *limitedSession) hit(limit string) {
+	l.mu.Lock()
+	if l.limit != "" {
+		l.mu.Unlock()
+		return
+	}
+	l.limit = limit
+	l.mu.Unlock()
+
+	metrics.IncSSHSessionLimit(limit)
+	slog.WarnContext(l.logCtx, "ssh session limit reached", "limit", limit, "message", l.message(limit))
+
+	l.cancel()
+	time.AfterFunc(limitGrace, l.close)
+}
+
+// end stops watching the session. A session over a limit is closed with
+// its message.
+func (l *limitedSession) end() {
+	l.mu.Lock()
+	l.ended = true
+	for _, timer := range []*time.Timer{l.idleTimer, l.durationTimer} {
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	limit := l.limit
+	l.mu.Unlock()
+
+	l.cancel()
+	if limit != "" {
+		l.close()
+	}
+}
+
+// close tells the client which limit the session went over and closes it
+// with ExitLimit
+func (l *limitedSession) close() {
+	l.closeOnce.Do(func() {
+		newline := "\n"
+		if l.pty {
+			newline = "\r\n"
+		}
+		fmt.Fprintf(l.Session.Stderr(), "%s%s", l.message(l.Limit()), newline)
+		_ = l.Session.Exit(features.ExitLimit)
+		_ = l.Session.Close()
+	})
+}
+
+// message explains limit to the client
+func (l *limitedSession) message(limit string) string {
+	switch limit {
+	case limitIdleTimeout:
+		return fmt.Sprintf("Session closed after %s without activity", l.cfg.IdleTimeout)
+	case limitMaxDuration:
+		return fmt.Sprintf("Session closed: sessions last at most %s", l.cfg.MaxDuration)
+	case limitMaxBytesOut:
+		return fmt.Sprintf("Session closed: sessions send at most %s", byteSize(l.cfg.MaxBytesOut))
+	case limitMaxSessions:
+		return "Too many SSH sessions are open, try again later"
+	case limitMaxSessionsPerIP:
+		return fmt.Sprintf("Too many SSH sessions are open from %s (at most %d), try again later",
+			l.addr, l.cfg.MaxSessionsPerIP)
+	}
+	return "Session closed"
+}
+
+// byteSize formats n bytes for people
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// limitedStderr counts what a session writes to stderr against its limits
+type limitedStderr struct {
+	io.ReadWriter
+	session *limitedSession
+}
+
+func (w limitedStderr) Write(p []byte) (int, error) {
+	if !w.session.send(len(p)) {
+		return 0, errSessionLimit
+	}
+	n, err := w.ReadWriter.Write(p)
+	w.session.touch()
+	return n, err
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/metrics"
+)
+
+// fakeSession records what is written to a session and how it ends
+type fakeSession struct {
+	ssh.Session
+
+	addr net.Addr
+
+	mu     sync.Mutex
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	exits  []int
+	closes int
+}
+
+func newFakeSession(ip string) *fakeSession {
+	return &fakeSession{addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50022}}
+}
+
+func (s *fakeSession) RemoteAddr() net.Addr { return s.addr }
+
+func (s *fakeSession) Pty() (ssh.Pty, <-chan ssh.Window, bool) { return ssh.Pty{}, nil, false }
+
+func (s *fakeSession) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (s *fakeSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stdout.Write(p)
+}
+
+func (s *fakeSession) Stderr() io.ReadWriter { return fakeStderr{s} }
+
+func (s *fakeSession) Exit(code int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exits = append(s.exits, code)
+	return nil
+}
+
+func (s *fakeSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closes++
+	return nil
+}
+
+// ended returns the exit statuses, close count and stderr of the session
+func (s *fakeSession) ended() ([]int, int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.exits...), s.closes, s.stderr.String()
+}
+
+type fakeStderr struct{ s *fakeSession }
+
+func (w fakeStderr) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (w fakeStderr) Write(p []byte) (int, error) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+	return w.s.stderr.Write(p)
+}
+
+// sessionLimitCount scrapes how often limit was hit
+func sessionLimitCount(t *testing.T, limit string) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	match := regexp.MustCompile(`prospero_ssh_session_limits_total\{limit="` + limit + `"\} (\d+)`).
+		FindStringSubmatch(w.Body.String())
+	if match == nil {
+		return 0
+	}
+	count, err := strconv.Atoi(match[1])
+	require.NoError(t, err)
+	return count
+}
+
+func TestSessionLimits_Admit(t *testing.T) {
+	t.Run("should refuse sessions over the total", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{MaxSessions: 2})
+		ctx := context.Background()
+
+		for _, ip := range []string{"192.0.2.1", "192.0.2.2"} {
+			session, _ := limits.wrap(ctx, newFakeSession(ip))
+			assert.True(t, limits.admit(session))
+		}
+
+		refused, limitCtx := limits.wrap(ctx, newFakeSession("192.0.2.3"))
+		assert.False(t, limits.admit(refused))
+		assert.Equal(t, limitMaxSessions, refused.Limit())
+		assert.Error(t, limitCtx.Err(), "refused sessions must be cancelled")
+		refused.end()
+	})
+
+	t.Run("should refuse sessions over the total of an address", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{MaxSessions: 10, MaxSessionsPerIP: 1})
+		ctx := context.Background()
+
+		first, _ := limits.wrap(ctx, newFakeSession("192.0.2.1"))
+		assert.True(t, limits.admit(first))
+
+		refused, _ := limits.wrap(ctx, newFakeSession("192.0.2.1"))
+		assert.False(t, limits.admit(refused))
+		assert.Equal(t, limitMaxSessionsPerIP, refused.Limit())
+		refused.end()
+
+		other, _ := limits.wrap(ctx, newFakeSession("192.0.2.2"))
+		assert.True(t, limits.admit(other), "other addresses have their own count")
+	})
+
+	t.Run("should free the slot of released sessions", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{MaxSessions: 1, MaxSessionsPerIP: 1})
+		ctx := context.Background()
+
+		session, _ := limits.wrap(ctx, newFakeSession("192.0.2.1"))
+		require.True(t, limits.admit(session))
+		limits.release(session)
+		assert.Zero(t, limits.open)
+		assert.NotContains(t, limits.byAddr, session.addr)
+
+		again, _ := limits.wrap(ctx, newFakeSession("192.0.2.1"))
+		assert.True(t, limits.admit(again))
+	})
+}
+
+func TestLimitedSession(t *testing.T) {
+	t.Run("should refuse writes past the maximum, stderr included", func(t *testing.T) {
+		before := sessionLimitCount(t, limitMaxBytesOut)
+		limits := newSessionLimits(config.SSHLimitsConfig{MaxBytesOut: 10})
+		fake := newFakeSession("192.0.2.1")
+		session, limitCtx := limits.wrap(context.Background(), fake)
+		defer session.end()
+
+		_, err := io.WriteString(session, "12345")
+		require.NoError(t, err)
+		_, err = io.WriteString(session.Stderr(), "123456")
+		assert.ErrorIs(t, err, errSessionLimit)
+		_, err = io.WriteString(session, "7")
+		assert.ErrorIs(t, err, errSessionLimit)
+
+		assert.Equal(t, limitMaxBytesOut, session.Limit())
+		assert.Error(t, limitCtx.Err())
+		assert.Equal(t, before+1, sessionLimitCount(t, limitMaxBytesOut), "the limit is counted once")
+		assert.Equal(t, "12345", fake.stdout.String())
+	})
+
+	t.Run("should close sessions after the idle timeout without activity", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{IdleTimeout: 200 * time.Millisecond})
+		session, _ := limits.wrap(context.Background(), newFakeSession("192.0.2.1"))
+		defer session.end()
+
+		time.Sleep(120 * time.Millisecond)
+		_, err := io.WriteString(session, "still here")
+		require.NoError(t, err)
+
+		// The first check comes at 200ms, 80ms after the write
+		time.Sleep(130 * time.Millisecond)
+		assert.Empty(t, session.Limit(), "activity must re-arm the idle timer")
+
+		assert.Eventually(t, func() bool {
+			return session.Limit() == limitIdleTimeout
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should stop watching ended sessions", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{
+			IdleTimeout: 50 * time.Millisecond,
+			MaxDuration: 50 * time.Millisecond,
+		})
+		fake := newFakeSession("192.0.2.1")
+		session, _ := limits.wrap(context.Background(), fake)
+		session.end()
+
+		time.Sleep(150 * time.Millisecond)
+		assert.Empty(t, session.Limit())
+		exits, closes, _ := fake.ended()
+		assert.Empty(t, exits)
+		assert.Zero(t, closes)
+	})
+
+	t.Run("should close sessions over a limit once, with its message", func(t *testing.T) {
+		limits := newSessionLimits(config.SSHLimitsConfig{MaxDuration: 10 * time.Millisecond})
+		fake := newFakeSession("192.0.2.1")
+		session, limitCtx := limits.wrap(context.Background(), fake)
+
+		<-limitCtx.Done()
+		session.end()
+		session.end()
+		session.close()
+
+		exits, closes, stderr := fake.ended()
+		assert.Equal(t, []int{features.ExitLimit}, exits)
+		assert.Equal(t, 1, closes)
+		assert.Equal(t, "Session closed: sessions last at most 10ms\n", stderr)
+	})
+}
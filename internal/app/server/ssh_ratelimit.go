@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+
+	"github.com/charmbracelet/ssh"
+
+	"prospero/internal/config"
+	"prospero/internal/features"
+	"prospero/internal/metrics"
+	"prospero/internal/ratelimit"
+)
+
+// withConnectionLimit drops connections from remote addresses that have
+// opened too many recently. Rejected connections are closed before the SSH
+// handshake, so the client only sees the connection reset.
+func withConnectionLimit(cfg config.RateLimitConfig, store ratelimit.Store) ssh.Option {
+	return func(srv *ssh.Server) error {
+		if !cfg.Enabled || !cfg.SSHConnections.Limit().Enabled() {
+			return nil
+		}
+
+		limiter := ratelimit.NewLimiter(store, cfg.SSHConnections.Limit())
+		srv.ConnCallback = func(ctx ssh.Context, conn net.Conn) net.Conn {
+			addr := ratelimit.ClientAddr(conn.RemoteAddr().String())
+			if !limiter.Allow("ssh_connections " + addr).Allowed {
+				metrics.IncRateLimited("ssh", "connections")
+				slog.Warn("ssh connection rate limited", "remote_addr", addr)
+				return nil
+			}
+			return conn
+		}
+		return nil
+	}
+}
+
+// sshCommandLimiter returns the limiter for commands run over SSH, or nil
+// when commands are not limited
+func sshCommandLimiter(cfg config.RateLimitConfig, store ratelimit.Store) *ratelimit.Limiter {
+	if !cfg.Enabled || !cfg.SSHCommands.Limit().Enabled() {
+		return nil
+	}
+	return ratelimit.NewLimiter(store, cfg.SSHCommands.Limit())
+}
+
+// allowSSHCommand takes a command token for the session's remote address. A
+// rejected session is told when to retry and exits with ExitRateLimited.
+func allowSSHCommand(ctx context.Context, s ssh.Session, limiter *ratelimit.Limiter) bool {
+	if limiter == nil {
+		return true
+	}
+
+	result := limiter.Allow("ssh_commands " + ratelimit.ClientAddr(s.RemoteAddr().String()))
+	if result.Allowed {
+		return true
+	}
+
+	metrics.IncRateLimited("ssh", "commands")
+	slog.WarnContext(ctx, "ssh command rate limited", "retry_after", result.RetryAfter)
+	fmt.Fprintf(s.Stderr(), "Rate limit exceeded, retry in %d seconds\n", int(math.Ceil(result.RetryAfter.Seconds())))
+	_ = s.Exit(features.ExitRateLimited)
+	return false
+}
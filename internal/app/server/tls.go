@@ -0,0 +1,115 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"prospero/assets"
+	"prospero/internal/config"
+	"prospero/internal/shared"
+	"prospero/internal/tlscert"
+)
+
+// loadTLSConfig returns the TLS configuration for the HTTP server, or nil
+// when TLS is disabled. A self-signed certificate also covers serverHost
+// unless it is a wildcard address.
+func loadTLSConfig(cfg config.TLSConfig, serverHost string) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	var (
+		cert   tls.Certificate
+		source string
+		err    error
+	)
+	switch {
+	case cfg.SelfSigned:
+		source = "self-signed"
+		cert, err = tlscert.SelfSigned(selfSignedHosts(cfg.Hosts, serverHost), time.Now())
+	case cfg.Embedded:
+		source = "embedded"
+		cert, err = loadTLSAssets()
+	default:
+		source = cfg.CertFile
+		cert, err = loadTLSFiles(cfg.CertFile, cfg.KeyFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s TLS certificate: %w", source, err)
+	}
+
+	slog.Info("tls certificate loaded",
+		"source", source,
+		"names", tlscert.Names(cert),
+		"expires", cert.Leaf.NotAfter,
+		"fingerprint", tlscert.Fingerprint(cert),
+	)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// selfSignedHosts adds serverHost to hosts when it names a specific host
+func selfSignedHosts(hosts []string, serverHost string) []string {
+	switch serverHost {
+	case "", "0.0.0.0", "::":
+		return hosts
+	}
+	if slices.Contains(hosts, serverHost) {
+		return hosts
+	}
+	return append([]string{serverHost}, hosts...)
+}
+
+// loadTLSAssets loads the tls.crt and tls.key assets
+func loadTLSAssets() (tls.Certificate, error) {
+	certPEM, err := shared.ReadAsset(assets.TLSCertAsset)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := shared.ReadAsset(assets.TLSKeyAsset)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return tls.Certificate{}, fmt.Errorf("no certificate is embedded, see prospero dev pack tls")
+	}
+	return tlscert.Parse(certPEM, keyPEM, time.Now())
+}
+
+// loadTLSFiles loads a PEM certificate and key, decrypting files that end
+// in .age
+func loadTLSFiles(certFile, keyFile string) (tls.Certificate, error) {
+	certPEM, err := readPEMFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := readPEMFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tlscert.Parse(certPEM, keyPEM, time.Now())
+}
+
+func readPEMFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".age") {
+		return data, nil
+	}
+
+	decrypted, err := shared.DecryptAge(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/muesli/termenv"
+
+	"prospero/internal/features"
+	"prospero/internal/tui"
+)
+
+// runTUI runs the interactive application on a session with a PTY until the
+// user quits or the connection closes
+func runTUI(ctx context.Context, s ssh.Session, registry *features.Registry) {
+	pty, windowChanges, _ := s.Pty()
+	environ := append(s.Environ(), "TERM="+pty.Term)
+
+	program := tea.NewProgram(
+		tui.New(ctx, tuiSources(registry), sessionRenderer(s, pty, environ)),
+		tea.WithInput(s),
+		tea.WithOutput(s),
+		tea.WithEnvironment(environ),
+		tea.WithAltScreen(),
+		tea.WithoutSignalHandler(),
+	)
+
+	// Forward terminal resizes until the session ends. The first window
+	// size is already queued by the PTY request.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				program.Quit()
+				return
+			case window, ok := <-windowChanges:
+				if !ok {
+					program.Quit()
+					return
+				}
+				program.Send(tea.WindowSizeMsg{Width: window.Width, Height: window.Height})
+			}
+		}
+	}()
+
+	if _, err := program.Run(); err != nil {
+		slog.ErrorContext(ctx, "tui exited with error", "error", err)
+	}
+	// Restore the terminal if the program did not exit cleanly
+	program.Kill()
+}
+
+// sessionRenderer styles output for the client's terminal. Each session
+// gets its own renderer so color settings never leak between clients.
+func sessionRenderer(s ssh.Session, pty ssh.Pty, environ []string) *lipgloss.Renderer {
+	if pty.Term == "" || pty.Term == "dumb" {
+		return lipgloss.NewRenderer(s, termenv.WithProfile(termenv.Ascii))
+	}
+
+	renderer := lipgloss.NewRenderer(s,
+		termenv.WithEnvironment(sessionEnviron(environ)),
+		termenv.WithUnsafe(),
+		termenv.WithColorCache(true),
+	)
+	// Querying the background color would race the program for input
+	renderer.SetHasDarkBackground(true)
+	return renderer
+}
+
+// tuiSources finds the services the interactive application browses among
+// those the features export
+func tuiSources(registry *features.Registry) tui.Sources {
+	var sources tui.Sources
+	for _, service := range registry.Exports() {
+		if library, ok := service.(tui.Library); ok {
+			sources.Library = library
+		}
+		if lists, ok := service.(tui.TopTen); ok {
+			sources.TopTen = lists
+		}
+	}
+	return sources
+}
+
+// sessionEnviron exposes the session's environment to termenv
+type sessionEnviron []string
+
+func (e sessionEnviron) Environ() []string {
+	return e
+}
+
+func (e sessionEnviron) Getenv(key string) string {
+	for _, kv := range e {
+		if value, ok := strings.CutPrefix(kv, key+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,305 @@
+// Package auth implements API key authentication. Keys are bearer tokens
+// stored only as SHA-256 hashes in an age-encrypted keys file, each carrying
+// the scopes it grants and optionally its own rate limit.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"prospero/assets"
+	"prospero/internal/ratelimit"
+	"prospero/internal/shared"
+)
+
+// Scopes granted by API keys
+const (
+	ScopeReadShakespert = "read:shakespert"
+	ScopeReadTopTen     = "read:topten"
+	ScopeMCP            = "mcp"
+
+	// ScopeAdmin grants every other scope
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every known scope
+var Scopes = []string{ScopeReadShakespert, ScopeReadTopTen, ScopeMCP, ScopeAdmin}
+
+// ValidScope reports whether scope is known
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// tokenPrefix starts every API key so leaked keys are easy to recognise
+const tokenPrefix = "prospero_"
+
+var (
+	// ErrInvalidKey is returned for tokens that match no key
+	ErrInvalidKey = errors.New("invalid API key")
+
+	// ErrRevokedKey is returned for tokens of revoked keys
+	ErrRevokedKey = errors.New("API key has been revoked")
+)
+
+// Key is an API key as stored in the keys file. The token itself is never
+// stored, only its hash.
+type Key struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Scopes    []string   `json:"scopes"`
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the key has been revoked
+func (k *Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Has reports whether the key grants scope
+func (k *Key) Has(scope string) bool {
+	return hasScope(k.Scopes, scope)
+}
+
+// RateLimit is the budget of a single key, replacing the route budgets for
+// requests made with it
+type RateLimit struct {
+	Requests int    `json:"requests"`
+	Period   string `json:"period"`
+	Burst    int    `json:"burst,omitempty"`
+}
+
+// Limit converts the budget for the rate limiter
+func (l RateLimit) Limit() (ratelimit.Limit, error) {
+	period, err := time.ParseDuration(l.Period)
+	if err != nil {
+		return ratelimit.Limit{}, fmt.Errorf("invalid rate limit period %q: %w", l.Period, err)
+	}
+	if l.Requests < 1 || period <= 0 || l.Burst < 0 {
+		return ratelimit.Limit{}, fmt.Errorf("invalid rate limit %d/%s", l.Requests, l.Period)
+	}
+	return ratelimit.Limit{Requests: l.Requests, Period: period, Burst: l.Burst}, nil
+}
+
+// String formats the budget as requests/period
+func (l RateLimit) String() string {
+	if l.Burst > 0 {
+		return fmt.Sprintf("%d/%s burst %d", l.Requests, l.Period, l.Burst)
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// KeyFile is the decrypted contents of the keys file
+type KeyFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// ParseKeyFile decodes a keys file. Empty data is a file without keys.
+func ParseKeyFile(data []byte) (*KeyFile, error) {
+	file := &KeyFile{}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return file, nil
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse keys file: %w", err)
+	}
+	return file, nil
+}
+
+// Marshal encodes the keys file
+func (f *KeyFile) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode keys file: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Find returns the key with id
+func (f *KeyFile) Find(id string) (*Key, bool) {
+	for i := range f.Keys {
+		if f.Keys[i].ID == id {
+			return &f.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// NewKey generates a key granting scopes. The returned token is shown to
+// the user once; only its hash is kept in the key.
+func NewKey(name string, scopes []string, limit *RateLimit) (Key, string, error) {
+	for _, scope := range scopes {
+		if !ValidScope(scope) {
+			return Key{}, "", fmt.Errorf("unknown scope %q, valid scopes: %s", scope, strings.Join(Scopes, ", "))
+		}
+	}
+	if len(scopes) == 0 {
+		return Key{}, "", errors.New("at least one scope is required")
+	}
+	if limit != nil {
+		if _, err := limit.Limit(); err != nil {
+			return Key{}, "", err
+		}
+	}
+
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, "", fmt.Errorf("failed to generate key ID: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, "", fmt.Errorf("failed to generate key secret: %w", err)
+	}
+
+	key := Key{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Scopes:    scopes,
+		RateLimit: limit,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	token := tokenPrefix + key.ID + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = HashToken(token)
+	return key, token, nil
+}
+
+// HashToken returns the hex SHA-256 hash a token is stored as
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Keyring authenticates tokens against a set of keys. Its keys can be
+// reloaded while the server runs.
+type Keyring struct {
+	mu     sync.RWMutex
+	byHash map[string]*Key
+}
+
+// NewKeyring creates a keyring holding keys
+func NewKeyring(keys []Key) (*Keyring, error) {
+	k := &Keyring{byHash: make(map[string]*Key, len(keys))}
+	for i := range keys {
+		key := &keys[i]
+		if key.RateLimit != nil {
+			if _, err := key.RateLimit.Limit(); err != nil {
+				return nil, fmt.Errorf("key %s: %w", key.ID, err)
+			}
+		}
+		k.byHash[key.Hash] = key
+	}
+	return k, nil
+}
+
+// LoadKeyring reads the keys file from the data directory or the embedded
+// copy. A missing or empty keys file yields an empty keyring.
+func LoadKeyring() (*Keyring, error) {
+	data, err := shared.ReadAsset(assets.KeysAsset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API keys: %w", err)
+	}
+	file, err := ParseKeyFile(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(file.Keys)
+}
+
+// Reload replaces the keys with those of the keys file. The current keys
+// are kept when it cannot be loaded.
+func (k *Keyring) Reload() error {
+	loaded, err := LoadKeyring()
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.byHash = loaded.byHash
+	return nil
+}
+
+// Len returns the number of active keys
+func (k *Keyring) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	n := 0
+	for _, key := range k.byHash {
+		if !key.Revoked() {
+			n++
+		}
+	}
+	return n
+}
+
+// Authenticate returns the key a token belongs to
+func (k *Keyring) Authenticate(token string) (*Key, error) {
+	k.mu.RLock()
+	key, ok := k.byHash[HashToken(token)]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	if key.Revoked() {
+		return nil, ErrRevokedKey
+	}
+	return key, nil
+}
+
+// Principal is the caller of a request: either an API key or an anonymous
+// client holding the configured public scopes
+type Principal struct {
+	// Key is nil for anonymous callers
+	Key    *Key
+	Scopes []string
+}
+
+// Anonymous returns the principal of unauthenticated callers
+func Anonymous(publicScopes []string) Principal {
+	return Principal{Scopes: publicScopes}
+}
+
+// ForKey returns the principal authenticated by key
+func ForKey(key *Key) Principal {
+	return Principal{Key: key, Scopes: key.Scopes}
+}
+
+// Anonymous reports whether the caller did not authenticate
+func (p Principal) Anonymous() bool {
+	return p.Key == nil
+}
+
+// Has reports whether the principal holds scope
+func (p Principal) Has(scope string) bool {
+	return hasScope(p.Scopes, scope)
+}
+
+func hasScope(scopes []string, scope string) bool {
+	return slices.Contains(scopes, scope) || slices.Contains(scopes, ScopeAdmin)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
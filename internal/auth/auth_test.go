@@ -0,0 +1,124 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/auth"
+	"prospero/internal/ratelimit"
+)
+
+func TestNewKey(t *testing.T) {
+	t.Run("should store only the hash of the token", func(t *testing.T) {
+		key, token, err := auth.NewKey("ci", []string{auth.ScopeReadTopTen}, nil)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(token, "prospero_"+key.ID+"_"))
+		assert.Equal(t, auth.HashToken(token), key.Hash)
+		assert.NotContains(t, key.Hash, token)
+		assert.Len(t, key.ID, 8)
+	})
+
+	t.Run("should reject unknown or missing scopes", func(t *testing.T) {
+		_, _, err := auth.NewKey("ci", []string{"write:everything"}, nil)
+		assert.ErrorContains(t, err, "unknown scope")
+
+		_, _, err = auth.NewKey("ci", nil, nil)
+		assert.ErrorContains(t, err, "at least one scope")
+	})
+
+	t.Run("should reject invalid rate limits", func(t *testing.T) {
+		_, _, err := auth.NewKey("ci", []string{auth.ScopeMCP}, &auth.RateLimit{Requests: 10, Period: "soon"})
+		assert.ErrorContains(t, err, "invalid rate limit period")
+	})
+}
+
+func TestKeyring(t *testing.T) {
+	reader, readerToken, err := auth.NewKey("reader", []string{auth.ScopeReadShakespert}, &auth.RateLimit{Requests: 10, Period: "1m"})
+	require.NoError(t, err)
+	revoked, revokedToken, err := auth.NewKey("old", []string{auth.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	now := time.Now()
+	revoked.RevokedAt = &now
+
+	keys, err := auth.NewKeyring([]auth.Key{reader, revoked})
+	require.NoError(t, err)
+
+	t.Run("should authenticate tokens by hash", func(t *testing.T) {
+		key, err := keys.Authenticate(readerToken)
+		require.NoError(t, err)
+		assert.Equal(t, reader.ID, key.ID)
+
+		limit, err := key.RateLimit.Limit()
+		require.NoError(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 10, Period: time.Minute}, limit)
+	})
+
+	t.Run("should reject unknown and revoked tokens", func(t *testing.T) {
+		_, err := keys.Authenticate("prospero_00000000_nope")
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+
+		_, err = keys.Authenticate(revokedToken)
+		assert.ErrorIs(t, err, auth.ErrRevokedKey)
+	})
+
+	t.Run("should count only active keys", func(t *testing.T) {
+		assert.Equal(t, 1, keys.Len())
+	})
+}
+
+func TestKeyFile(t *testing.T) {
+	t.Run("should treat empty data as a file without keys", func(t *testing.T) {
+		file, err := auth.ParseKeyFile(nil)
+		require.NoError(t, err)
+		assert.Empty(t, file.Keys)
+	})
+
+	t.Run("should round-trip through Marshal", func(t *testing.T) {
+		key, _, err := auth.NewKey("ci", []string{auth.ScopeMCP}, nil)
+		require.NoError(t, err)
+
+		data, err := (&auth.KeyFile{Keys: []auth.Key{key}}).Marshal()
+		require.NoError(t, err)
+
+		file, err := auth.ParseKeyFile(data)
+		require.NoError(t, err)
+		found, ok := file.Find(key.ID)
+		require.True(t, ok)
+		assert.Equal(t, key.Hash, found.Hash)
+		assert.True(t, key.CreatedAt.Equal(found.CreatedAt))
+	})
+}
+
+func TestPrincipal(t *testing.T) {
+	t.Run("should grant every scope to admin keys", func(t *testing.T) {
+		p := auth.ForKey(&auth.Key{Scopes: []string{auth.ScopeAdmin}})
+
+		assert.True(t, p.Has(auth.ScopeMCP))
+		assert.True(t, p.Has(auth.ScopeReadTopTen))
+		assert.False(t, p.Anonymous())
+	})
+
+	t.Run("should give anonymous callers the public scopes", func(t *testing.T) {
+		p := auth.Anonymous([]string{auth.ScopeReadShakespert})
+
+		assert.True(t, p.Anonymous())
+		assert.True(t, p.Has(auth.ScopeReadShakespert))
+		assert.False(t, p.Has(auth.ScopeMCP))
+	})
+
+	t.Run("should travel in the context", func(t *testing.T) {
+		_, ok := auth.FromContext(context.Background())
+		assert.False(t, ok)
+
+		ctx := auth.NewContext(context.Background(), auth.Anonymous(nil))
+		p, ok := auth.FromContext(ctx)
+		assert.True(t, ok)
+		assert.True(t, p.Anonymous())
+	})
+}
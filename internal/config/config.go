@@ -0,0 +1,611 @@
+// Package config defines Prospero's typed configuration and loads it in
+// layers: built-in defaults, then an optional TOML file, then PROSPERO_*
+// environment variables, then command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"prospero/internal/auth"
+	"prospero/internal/logging"
+	"prospero/internal/ratelimit"
+	"prospero/internal/sshauth"
+)
+
+// Config is the complete Prospero configuration
+type Config struct {
+	// DataDir overrides embedded data assets with files from this directory
+	DataDir string `toml:"data_dir"`
+
+	Log       LogConfig       `toml:"log"`
+	Server    ServerConfig    `toml:"server"`
+	HTTP      HTTPConfig      `toml:"http"`
+	SSH       SSHConfig       `toml:"ssh"`
+	MCP       MCPConfig       `toml:"mcp"`
+	Metrics   MetricsConfig   `toml:"metrics"`
+	RateLimit RateLimitConfig `toml:"rate_limit"`
+	Auth      AuthConfig      `toml:"auth"`
+	Platform  PlatformConfig  `toml:"platform"`
+}
+
+// LogConfig configures the process-wide logger
+type LogConfig struct {
+	Format string `toml:"format"` // text or json
+	Level  string `toml:"level"`  // debug, info, warn or error
+}
+
+// ServerConfig holds settings shared by every listener
+type ServerConfig struct {
+	Host string `toml:"host"`
+
+	// ShutdownTimeout bounds how long serve waits for all listeners to stop
+	ShutdownTimeout time.Duration `toml:"shutdown_timeout"`
+
+	// DrainDelay is how long readiness reports "draining" before the HTTP
+	// listener closes, giving load balancers time to stop routing traffic
+	DrainDelay time.Duration `toml:"drain_delay"`
+}
+
+// HTTPConfig configures the HTTP server
+type HTTPConfig struct {
+	Port            string        `toml:"port"`
+	RequestTimeout  time.Duration `toml:"request_timeout"`
+	ShutdownTimeout time.Duration `toml:"shutdown_timeout"`
+	CORS            CORSConfig    `toml:"cors"`
+	TLS             TLSConfig     `toml:"tls"`
+}
+
+// TLSConfig enables HTTPS. The certificate comes from exactly one of the
+// cert/key files, the embedded tls.crt/tls.key assets or a generated
+// self-signed certificate.
+type TLSConfig struct {
+	// CertFile and KeyFile are PEM files. Files ending in .age are
+	// decrypted with AGE_ENCRYPTION_PASSWORD.
+	CertFile string `toml:"cert_file"`
+	KeyFile  string `toml:"key_file"`
+
+	// Embedded serves the tls.crt and tls.key assets, which like hostkey are
+	// age-encrypted and can be overridden from the data directory
+	Embedded bool `toml:"embedded"`
+
+	// SelfSigned generates an in-memory certificate for Hosts at startup.
+	// Meant for development only.
+	SelfSigned bool     `toml:"self_signed"`
+	Hosts      []string `toml:"hosts"`
+
+	// RedirectAddr is the host:port of a plain HTTP listener that redirects
+	// every request to HTTPS. Empty disables it.
+	RedirectAddr string `toml:"redirect_addr"`
+
+	HSTS HSTSConfig `toml:"hsts"`
+}
+
+// Enabled reports whether HTTPS is configured
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.Embedded || c.SelfSigned
+}
+
+// HSTSConfig configures the Strict-Transport-Security header sent on HTTPS
+// responses
+type HSTSConfig struct {
+	// MaxAge is how long browsers must only use HTTPS. Zero disables HSTS.
+	MaxAge            time.Duration `toml:"max_age"`
+	IncludeSubdomains bool          `toml:"include_subdomains"`
+	Preload           bool          `toml:"preload"`
+}
+
+// CORSConfig configures cross-origin access to the HTTP API
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API. "*" allows any,
+	// and patterns such as https://*.example.com match with wildcards.
+	AllowedOrigins []string `toml:"allowed_origins"`
+
+	// AllowCredentials lets browsers send credentials. It cannot be combined
+	// with the "*" origin.
+	AllowCredentials bool `toml:"allow_credentials"`
+
+	// AllowedHeaders lists request headers preflights may ask for
+	AllowedHeaders []string `toml:"allowed_headers"`
+
+	// ExposedHeaders lists response headers browser scripts may read
+	ExposedHeaders []string `toml:"exposed_headers"`
+
+	// MaxAge is how long browsers may cache preflight responses
+	MaxAge time.Duration `toml:"max_age"`
+}
+
+// SSHConfig configures the SSH server
+type SSHConfig struct {
+	Port            string        `toml:"port"`
+	ShutdownTimeout time.Duration `toml:"shutdown_timeout"`
+
+	// Force starts the SSH server even where the platform disables it
+	Force bool `toml:"force"`
+
+	Auth   SSHAuthConfig   `toml:"auth"`
+	Audit  SSHAuditConfig  `toml:"audit"`
+	Limits SSHLimitsConfig `toml:"limits"`
+}
+
+// SSHAuthConfig configures how SSH clients authenticate. A listed key is
+// tried first, then a password for the configured users, and finally
+// anonymous access. The role of each client gates the commands it may run.
+type SSHAuthConfig struct {
+	// Anonymous lets any client in with AnonymousRole
+	Anonymous     bool   `toml:"anonymous"`
+	AnonymousRole string `toml:"anonymous_role"`
+
+	// AuthorizedKeys allows the keys of the encrypted authorized_keys asset.
+	// Keys get KeyRole unless their line has a role="admin" option.
+	AuthorizedKeys bool `toml:"authorized_keys"`
+
+	// KeysDir is a directory of <user>.keys files, such as those served by
+	// https://github.com/<user>.keys, whose keys get KeyRole
+	KeysDir string `toml:"keys_dir"`
+	KeyRole string `toml:"key_role"`
+
+	// Users are keyboard-interactive password users by name. Being a table,
+	// it can only be set in the config file.
+	Users map[string]SSHUser `toml:"users"`
+}
+
+// SSHUser is a keyboard-interactive password user
+type SSHUser struct {
+	Role string `toml:"role"`
+
+	// PasswordHash is the bcrypt hash of the password
+	PasswordHash string `toml:"password_hash"`
+}
+
+// SSHAuditConfig configures the audit log of SSH sessions. Every session is
+// logged; with Dir set it is also appended to Dir/sessions.jsonl.
+type SSHAuditConfig struct {
+	Dir string `toml:"dir"`
+
+	// Record saves asciicast v2 recordings of interactive sessions in Dir
+	Record bool `toml:"record"`
+}
+
+// SSHLimitsConfig bounds what a single SSH session may hold on to. A
+// session over a limit is told why and closed. Zero disables a limit.
+type SSHLimitsConfig struct {
+	// IdleTimeout closes sessions that neither read nor write for this long
+	IdleTimeout time.Duration `toml:"idle_timeout"`
+
+	// MaxDuration closes sessions open for this long
+	MaxDuration time.Duration `toml:"max_duration"`
+
+	// MaxSessions and MaxSessionsPerIP cap the sessions open at once, in
+	// total and per remote address. Sessions over either are refused.
+	MaxSessions      int `toml:"max_sessions"`
+	MaxSessionsPerIP int `toml:"max_sessions_per_ip"`
+
+	// MaxBytesOut closes sessions once they have sent this many bytes
+	MaxBytesOut int64 `toml:"max_bytes_out"`
+}
+
+// MCPConfig configures the MCP server
+type MCPConfig struct {
+	// Name and Version are the identity reported to clients
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+
+	// AllowedOrigins lists the browser origins allowed to call /mcp, using
+	// the same patterns as http.cors.allowed_origins. Requests without an
+	// Origin header are always allowed. This guards against DNS rebinding.
+	AllowedOrigins []string `toml:"allowed_origins"`
+}
+
+// MetricsConfig configures Prometheus metrics
+type MetricsConfig struct {
+	// Addr is the host:port of a separate admin listener serving /metrics.
+	// When empty, /metrics is served on the main HTTP server.
+	Addr string `toml:"addr"`
+}
+
+// RateLimitConfig configures per-client token-bucket rate limiting. Clients
+// are identified by address, after X-Forwarded-For / X-Real-IP handling.
+type RateLimitConfig struct {
+	Enabled bool `toml:"enabled"`
+
+	// Default applies to API and MCP routes without a budget of their own
+	Default Budget `toml:"default"`
+
+	// Routes overrides the budget for route patterns such as
+	// "/api/v1/topten". Each route has its own bucket per client. Being a
+	// table keyed by pattern, it can only be set in the config file, where
+	// it replaces the default routes.
+	Routes map[string]Budget `toml:"routes"`
+
+	// SSHConnections limits new SSH connections per remote address
+	SSHConnections Budget `toml:"ssh_connections"`
+
+	// SSHCommands limits SSH commands per remote address
+	SSHCommands Budget `toml:"ssh_commands"`
+}
+
+// Budget allows Requests every Period with bursts of up to Burst (which
+// defaults to Requests). A zero budget is unlimited.
+type Budget struct {
+	Requests int           `toml:"requests"`
+	Period   time.Duration `toml:"period"`
+	Burst    int           `toml:"burst"`
+}
+
+// Limit converts the budget for the rate limiter
+func (b Budget) Limit() ratelimit.Limit {
+	return ratelimit.Limit{Requests: b.Requests, Period: b.Period, Burst: b.Burst}
+}
+
+// AuthConfig configures API key authentication of the HTTP API and /mcp.
+// Keys themselves live in the encrypted keys.json asset.
+type AuthConfig struct {
+	// PublicScopes are granted to requests without an Authorization header.
+	// Leave empty to require an API key for every API and MCP request.
+	PublicScopes []string `toml:"public_scopes"`
+}
+
+// PlatformConfig controls detection of hosting platforms that need
+// different defaults
+type PlatformConfig struct {
+	// DetectBunny disables the SSH server when running on a bunny.net Magic
+	// Container, which only routes HTTP traffic
+	DetectBunny bool `toml:"detect_bunny"`
+
+	// BunnyEnvVar is the environment variable whose presence identifies a
+	// bunny.net Magic Container
+	BunnyEnvVar string `toml:"bunny_env_var"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Log: LogConfig{
+			Format: logging.FormatText,
+			Level:  "info",
+		},
+		Server: ServerConfig{
+			Host:            "localhost",
+			ShutdownTimeout: 10 * time.Second,
+		},
+		HTTP: HTTPConfig{
+			Port:            "8080",
+			RequestTimeout:  60 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
+			TLS: TLSConfig{
+				Hosts: []string{"localhost", "127.0.0.1", "::1"},
+			},
+			CORS: CORSConfig{
+				AllowedOrigins: []string{"*"},
+				AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "Mcp-Session-Id"},
+				ExposedHeaders: []string{
+					"Deprecation", "ETag", "Link", "Retry-After",
+					"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+				},
+				MaxAge: 10 * time.Minute,
+			},
+		},
+		SSH: SSHConfig{
+			Port:            "2222",
+			ShutdownTimeout: 5 * time.Second,
+			Auth: SSHAuthConfig{
+				Anonymous:     true,
+				AnonymousRole: sshauth.RoleUser,
+				KeyRole:       sshauth.RoleUser,
+			},
+			Limits: SSHLimitsConfig{
+				IdleTimeout:      15 * time.Minute,
+				MaxDuration:      2 * time.Hour,
+				MaxSessions:      200,
+				MaxSessionsPerIP: 10,
+				MaxBytesOut:      256 << 20,
+			},
+		},
+		MCP: MCPConfig{
+			Name:    "prospero",
+			Version: "1.0.0",
+			AllowedOrigins: []string{
+				"*://localhost", "*://localhost:*",
+				"*://127.0.0.1", "*://127.0.0.1:*",
+				"*://[::1]", "*://[::1]:*",
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Default: Budget{Requests: 120, Period: time.Minute},
+			Routes: map[string]Budget{
+				// Every request draws a new list, so scraping is cheap to attempt
+				"/api/v1/topten": {Requests: 30, Period: time.Minute},
+				"/api/topten":    {Requests: 30, Period: time.Minute},
+			},
+			SSHConnections: Budget{Requests: 10, Period: time.Minute},
+			SSHCommands:    Budget{Requests: 30, Period: time.Minute},
+		},
+		Auth: AuthConfig{
+			PublicScopes: []string{auth.ScopeReadShakespert, auth.ScopeReadTopTen, auth.ScopeMCP},
+		},
+		Platform: PlatformConfig{
+			DetectBunny: true,
+			BunnyEnvVar: "BUNNYNET_MC_APPID",
+		},
+	}
+}
+
+// Load builds a configuration from the defaults, the TOML file at path (if
+// path is not empty) and the PROSPERO_* environment variables. Flags are
+// applied afterwards by the caller with Set.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadFile merges the TOML file at path into c. Keys missing from the file
+// keep their current values; unknown keys are an error. A routes table in
+// the file replaces the current routes rather than adding to them, so that
+// default budgets can be removed.
+func (c *Config) LoadFile(path string) error {
+	routes := c.RateLimit.Routes
+	c.RateLimit.Routes = nil
+
+	meta, err := toml.DecodeFile(path, c)
+	if !meta.IsDefined("rate_limit", "routes") {
+		c.RateLimit.Routes = routes
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown keys in config file %s: %v", path, undecoded)
+	}
+	return nil
+}
+
+// Write encodes c as TOML
+func (c *Config) Write(w io.Writer) error {
+	enc := toml.NewEncoder(w)
+	enc.Indent = ""
+	return enc.Encode(c)
+}
+
+// BunnyMagicContainer reports whether platform detection is enabled and the
+// process is running inside a bunny.net Magic Container
+func (c *Config) BunnyMagicContainer() bool {
+	return c.Platform.DetectBunny && c.Platform.BunnyEnvVar != "" && os.Getenv(c.Platform.BunnyEnvVar) != ""
+}
+
+// SSHEnabled reports whether the SSH server should start
+func (c *Config) SSHEnabled() bool {
+	return c.SSH.Force || !c.BunnyMagicContainer()
+}
+
+// Validate checks the configuration for values that would fail at runtime
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := logging.New(io.Discard, logging.Options{Format: c.Log.Format, Level: c.Log.Level}); err != nil {
+		errs = append(errs, fmt.Errorf("log: %w", err))
+	}
+
+	if c.DataDir != "" {
+		if info, err := os.Stat(c.DataDir); err != nil {
+			errs = append(errs, fmt.Errorf("data_dir: %w", err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("data_dir: %s is not a directory", c.DataDir))
+		}
+	}
+
+	errs = append(errs,
+		validatePort("http.port", c.HTTP.Port),
+		validatePort("ssh.port", c.SSH.Port),
+		validateDuration("server.shutdown_timeout", c.Server.ShutdownTimeout, false),
+		validateDuration("server.drain_delay", c.Server.DrainDelay, true),
+		validateDuration("http.request_timeout", c.HTTP.RequestTimeout, false),
+		validateDuration("http.shutdown_timeout", c.HTTP.ShutdownTimeout, false),
+		validateDuration("ssh.shutdown_timeout", c.SSH.ShutdownTimeout, false),
+	)
+
+	for _, origin := range c.HTTP.CORS.AllowedOrigins {
+		errs = append(errs, validateOrigin("http.cors.allowed_origins", origin))
+	}
+	if c.HTTP.CORS.AllowCredentials && slices.Contains(c.HTTP.CORS.AllowedOrigins, "*") {
+		errs = append(errs, errors.New(`http.cors.allow_credentials: cannot be combined with allowed_origins "*"`))
+	}
+	errs = append(errs, validateDuration("http.cors.max_age", c.HTTP.CORS.MaxAge, true))
+	errs = append(errs, c.HTTP.TLS.validate())
+	for _, origin := range c.MCP.AllowedOrigins {
+		errs = append(errs, validateOrigin("mcp.allowed_origins", origin))
+	}
+
+	errs = append(errs,
+		validateBudget("rate_limit.default", c.RateLimit.Default),
+		validateBudget("rate_limit.ssh_connections", c.RateLimit.SSHConnections),
+		validateBudget("rate_limit.ssh_commands", c.RateLimit.SSHCommands),
+	)
+	for pattern, budget := range c.RateLimit.Routes {
+		errs = append(errs, validateBudget(fmt.Sprintf("rate_limit.routes.%q", pattern), budget))
+	}
+
+	for _, scope := range c.Auth.PublicScopes {
+		if !auth.ValidScope(scope) {
+			errs = append(errs, fmt.Errorf("auth.public_scopes: unknown scope %q", scope))
+		} else if scope == auth.ScopeAdmin {
+			errs = append(errs, errors.New("auth.public_scopes: admin cannot be public"))
+		}
+	}
+
+	errs = append(errs, c.SSH.Auth.validate())
+	if c.SSH.Audit.Record && c.SSH.Audit.Dir == "" {
+		errs = append(errs, errors.New("ssh.audit.record: set ssh.audit.dir to store recordings"))
+	}
+	errs = append(errs, c.SSH.Limits.validate())
+
+	if c.MCP.Name == "" {
+		errs = append(errs, errors.New("mcp.name must not be empty"))
+	}
+	if c.MCP.Version == "" {
+		errs = append(errs, errors.New("mcp.version must not be empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c SSHAuthConfig) validate() error {
+	var errs []error
+
+	errs = append(errs,
+		validateRole("ssh.auth.anonymous_role", c.AnonymousRole),
+		validateRole("ssh.auth.key_role", c.KeyRole),
+	)
+	if c.KeysDir != "" {
+		if info, err := os.Stat(c.KeysDir); err != nil {
+			errs = append(errs, fmt.Errorf("ssh.auth.keys_dir: %w", err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("ssh.auth.keys_dir: %s is not a directory", c.KeysDir))
+		}
+	}
+	for name, user := range c.Users {
+		errs = append(errs, validateRole(fmt.Sprintf("ssh.auth.users.%s.role", name), user.Role))
+		if !sshauth.ValidPasswordHash(user.PasswordHash) {
+			errs = append(errs, fmt.Errorf("ssh.auth.users.%s.password_hash: not a bcrypt hash", name))
+		}
+	}
+	if !c.Anonymous && !c.AuthorizedKeys && c.KeysDir == "" && len(c.Users) == 0 {
+		errs = append(errs, errors.New("ssh.auth: enable anonymous, authorized_keys, keys_dir or users"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c SSHLimitsConfig) validate() error {
+	var errs []error
+
+	errs = append(errs,
+		validateDuration("ssh.limits.idle_timeout", c.IdleTimeout, true),
+		validateDuration("ssh.limits.max_duration", c.MaxDuration, true),
+	)
+	if c.MaxSessions < 0 || c.MaxSessionsPerIP < 0 || c.MaxBytesOut < 0 {
+		errs = append(errs, errors.New("ssh.limits: max_sessions, max_sessions_per_ip and max_bytes_out must not be negative"))
+	}
+	if c.MaxSessions > 0 && c.MaxSessionsPerIP > c.MaxSessions {
+		errs = append(errs, fmt.Errorf("ssh.limits.max_sessions_per_ip: %d is more than max_sessions (%d)", c.MaxSessionsPerIP, c.MaxSessions))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateRole(key, role string) error {
+	if !sshauth.ValidRole(role) {
+		return fmt.Errorf("%s: unknown role %q, valid roles: %s", key, role, strings.Join(sshauth.Roles, ", "))
+	}
+	return nil
+}
+
+// hstsPreloadMinAge is the shortest max-age accepted by the HSTS preload list
+const hstsPreloadMinAge = 365 * 24 * time.Hour
+
+func (c TLSConfig) validate() error {
+	var errs []error
+
+	sources := 0
+	if c.CertFile != "" || c.KeyFile != "" {
+		sources++
+		if c.CertFile == "" || c.KeyFile == "" {
+			errs = append(errs, errors.New("http.tls: cert_file and key_file must be set together"))
+		}
+	}
+	if c.Embedded {
+		sources++
+	}
+	if c.SelfSigned {
+		sources++
+		if len(c.Hosts) == 0 {
+			errs = append(errs, errors.New("http.tls.hosts: required for a self-signed certificate"))
+		}
+	}
+	if sources > 1 {
+		errs = append(errs, errors.New("http.tls: use only one of cert_file/key_file, embedded and self_signed"))
+	}
+
+	if c.RedirectAddr != "" {
+		if !c.Enabled() {
+			errs = append(errs, errors.New("http.tls.redirect_addr: requires TLS to be enabled"))
+		}
+		if _, port, err := net.SplitHostPort(c.RedirectAddr); err != nil {
+			errs = append(errs, fmt.Errorf("http.tls.redirect_addr: %q is not a host:port", c.RedirectAddr))
+		} else {
+			errs = append(errs, validatePort("http.tls.redirect_addr", port))
+		}
+	}
+
+	errs = append(errs, validateDuration("http.tls.hsts.max_age", c.HSTS.MaxAge, true))
+	if c.HSTS.Preload && (c.HSTS.MaxAge < hstsPreloadMinAge || !c.HSTS.IncludeSubdomains) {
+		errs = append(errs, errors.New("http.tls.hsts.preload: requires max_age of at least a year and include_subdomains"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validatePort(key, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s: %q is not a valid port", key, port)
+	}
+	return nil
+}
+
+func validateDuration(key string, d time.Duration, allowZero bool) error {
+	if d < 0 || (d == 0 && !allowZero) {
+		return fmt.Errorf("%s: must be positive, got %s", key, d)
+	}
+	return nil
+}
+
+func validateBudget(key string, b Budget) error {
+	if b.Requests < 0 || b.Burst < 0 || b.Period < 0 {
+		return fmt.Errorf("%s: requests, burst and period must not be negative", key)
+	}
+	if b.Requests > 0 && b.Period == 0 {
+		return fmt.Errorf("%s: period is required when requests is set", key)
+	}
+	return nil
+}
+
+// validateOrigin checks an origin or origin pattern. Wildcards are replaced
+// by placeholders valid in their position before parsing.
+func validateOrigin(key, origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	candidate := origin
+	if scheme, rest, ok := strings.Cut(candidate, "://"); ok {
+		candidate = strings.ReplaceAll(scheme, "*", "x") + "://" + strings.ReplaceAll(rest, "*", "0")
+	}
+	u, err := url.Parse(candidate)
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("%s: %q is not an origin like https://example.com", key, origin)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/config"
+)
+
+func TestLoad(t *testing.T) {
+	writeFile := func(t *testing.T, contents string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "prospero.toml")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+		return path
+	}
+
+	t.Run("should return defaults without a file or environment", func(t *testing.T) {
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+
+		assert.Equal(t, config.Default(), cfg)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("should override defaults from the TOML file", func(t *testing.T) {
+		path := writeFile(t, `
+[http]
+port = "9000"
+request_timeout = "30s"
+
+[http.cors]
+allowed_origins = ["https://example.com"]
+
+[mcp]
+version = "2.0.0"
+`)
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "9000", cfg.HTTP.Port)
+		assert.Equal(t, 30*time.Second, cfg.HTTP.RequestTimeout)
+		assert.Equal(t, []string{"https://example.com"}, cfg.HTTP.CORS.AllowedOrigins)
+		assert.Equal(t, "2.0.0", cfg.MCP.Version)
+		assert.Equal(t, "prospero", cfg.MCP.Name)
+		assert.Equal(t, "2222", cfg.SSH.Port)
+	})
+
+	t.Run("should replace the default routes with those of the file", func(t *testing.T) {
+		path := writeFile(t, `
+[rate_limit.routes."/api/v1/shakespert/works"]
+requests = 10
+period = "1m"
+`)
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]config.Budget{
+			"/api/v1/shakespert/works": {Requests: 10, Period: time.Minute},
+		}, cfg.RateLimit.Routes)
+
+		path = writeFile(t, "[rate_limit.routes]\n")
+		cfg, err = config.Load(path)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.RateLimit.Routes, "an empty table removes every default route")
+
+		path = writeFile(t, "[rate_limit]\nenabled = false\n")
+		cfg, err = config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, config.Default().RateLimit.Routes, cfg.RateLimit.Routes)
+	})
+
+	t.Run("should let environment variables override the file", func(t *testing.T) {
+		path := writeFile(t, "[http]\nport = \"9000\"\n")
+		t.Setenv("PROSPERO_HTTP_PORT", "9100")
+		t.Setenv("PROSPERO_SSH_FORCE", "true")
+		t.Setenv("PROSPERO_HTTP_CORS_ALLOWED_ORIGINS", "https://a.example, https://b.example")
+
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "9100", cfg.HTTP.Port)
+		assert.True(t, cfg.SSH.Force)
+		assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.HTTP.CORS.AllowedOrigins)
+	})
+
+	t.Run("should reject unknown keys in the file", func(t *testing.T) {
+		path := writeFile(t, "[http]\nprot = \"9000\"\n")
+
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "http.prot")
+	})
+
+	t.Run("should reject malformed environment values", func(t *testing.T) {
+		t.Setenv("PROSPERO_SERVER_SHUTDOWN_TIMEOUT", "soon")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "PROSPERO_SERVER_SHUTDOWN_TIMEOUT")
+	})
+}
+
+func TestSet(t *testing.T) {
+	t.Run("should set values by dotted key", func(t *testing.T) {
+		cfg := config.Default()
+
+		require.NoError(t, cfg.Set("server.drain_delay", "5s"))
+		require.NoError(t, cfg.Set("platform.detect_bunny", "false"))
+		require.NoError(t, cfg.Set("data_dir", "/srv/prospero"))
+
+		assert.Equal(t, 5*time.Second, cfg.Server.DrainDelay)
+		assert.False(t, cfg.Platform.DetectBunny)
+		assert.Equal(t, "/srv/prospero", cfg.DataDir)
+	})
+
+	t.Run("should reject unknown keys", func(t *testing.T) {
+		assert.Error(t, config.Default().Set("http.nope", "1"))
+	})
+}
+
+func TestKeys(t *testing.T) {
+	t.Run("should list every key with its environment variable", func(t *testing.T) {
+		keys := config.Keys()
+
+		assert.Contains(t, keys, "http.cors.allowed_origins")
+		assert.Contains(t, keys, "log.level")
+		assert.Equal(t, "PROSPERO_HTTP_CORS_ALLOWED_ORIGINS", config.EnvName("http.cors.allowed_origins"))
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should report every invalid value", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.HTTP.Port = "http"
+		cfg.Log.Level = "loud"
+		cfg.HTTP.RequestTimeout = 0
+		cfg.HTTP.CORS.AllowedOrigins = []string{"example.com"}
+		cfg.MCP.Name = ""
+
+		err := cfg.Validate()
+		require.Error(t, err)
+
+		assert.ErrorContains(t, err, "http.port")
+		assert.ErrorContains(t, err, "log")
+		assert.ErrorContains(t, err, "http.request_timeout")
+		assert.ErrorContains(t, err, "allowed_origins")
+		assert.ErrorContains(t, err, "mcp.name")
+	})
+
+	t.Run("should accept the default origin patterns", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.HTTP.CORS.AllowedOrigins = []string{"https://*.example.com", "http://localhost:*"}
+
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("should reject credentials with the wildcard origin", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.HTTP.CORS.AllowCredentials = true
+
+		assert.ErrorContains(t, cfg.Validate(), "http.cors.allow_credentials")
+	})
+
+	t.Run("should reject conflicting TLS settings", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.HTTP.TLS.CertFile = "tls.crt"
+		cfg.HTTP.TLS.SelfSigned = true
+		cfg.HTTP.TLS.HSTS.Preload = true
+
+		err := cfg.Validate()
+		assert.ErrorContains(t, err, "cert_file and key_file must be set together")
+		assert.ErrorContains(t, err, "use only one of")
+		assert.ErrorContains(t, err, "http.tls.hsts.preload")
+	})
+
+	t.Run("should require TLS for the redirect listener", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.HTTP.TLS.RedirectAddr = ":8081"
+
+		assert.ErrorContains(t, cfg.Validate(), "http.tls.redirect_addr: requires TLS")
+
+		cfg.HTTP.TLS.SelfSigned = true
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("should reject unknown and admin public scopes", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Auth.PublicScopes = []string{"read:everything", "admin"}
+
+		err := cfg.Validate()
+		assert.ErrorContains(t, err, `unknown scope "read:everything"`)
+		assert.ErrorContains(t, err, "admin cannot be public")
+	})
+
+	t.Run("should reject SSH auth that lets nobody in", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.SSH.Auth.Anonymous = false
+
+		assert.ErrorContains(t, cfg.Validate(), "ssh.auth: enable anonymous")
+	})
+
+	t.Run("should reject unknown SSH roles and plain-text passwords", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.SSH.Auth.KeyRole = "root"
+		cfg.SSH.Auth.Users = map[string]config.SSHUser{
+			"miranda": {Role: "admin", PasswordHash: "brave new world"},
+		}
+
+		err := cfg.Validate()
+		assert.ErrorContains(t, err, `ssh.auth.key_role: unknown role "root"`)
+		assert.ErrorContains(t, err, "ssh.auth.users.miranda.password_hash: not a bcrypt hash")
+	})
+
+	t.Run("should reject recordings without an audit directory", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.SSH.Audit.Record = true
+
+		assert.ErrorContains(t, cfg.Validate(), "ssh.audit.record: set ssh.audit.dir")
+	})
+
+	t.Run("should reject negative SSH session limits", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.SSH.Limits.IdleTimeout = -time.Second
+		cfg.SSH.Limits.MaxBytesOut = -1
+
+		err := cfg.Validate()
+		assert.ErrorContains(t, err, "ssh.limits.idle_timeout: must be positive")
+		assert.ErrorContains(t, err, "ssh.limits: max_sessions, max_sessions_per_ip and max_bytes_out must not be negative")
+	})
+
+	t.Run("should reject a per-address session limit above the total", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.SSH.Limits.MaxSessions = 5
+		cfg.SSH.Limits.MaxSessionsPerIP = 10
+
+		assert.ErrorContains(t, cfg.Validate(), "ssh.limits.max_sessions_per_ip: 10 is more than max_sessions (5)")
+
+		cfg.SSH.Limits.MaxSessions = 0
+		assert.NoError(t, cfg.Validate())
+	})
+}
+
+func TestSSHEnabled(t *testing.T) {
+	t.Run("should disable SSH on bunny.net unless forced", func(t *testing.T) {
+		t.Setenv("BUNNYNET_MC_APPID", "app-123")
+		cfg := config.Default()
+		assert.False(t, cfg.SSHEnabled())
+
+		cfg.SSH.Force = true
+		assert.True(t, cfg.SSHEnabled())
+	})
+
+	t.Run("should ignore bunny.net when detection is off", func(t *testing.T) {
+		t.Setenv("BUNNYNET_MC_APPID", "app-123")
+		cfg := config.Default()
+		cfg.Platform.DetectBunny = false
+		assert.True(t, cfg.SSHEnabled())
+	})
+}
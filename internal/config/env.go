@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is prepended to every environment variable name
+const EnvPrefix = "PROSPERO_"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvName returns the environment variable for a dotted config key, for
+// example "http.request_timeout" becomes PROSPERO_HTTP_REQUEST_TIMEOUT
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Keys returns every settable dotted config key in sorted order
+func Keys() []string {
+	var keys []string
+	walk(reflect.ValueOf(Default()).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyEnv sets every key whose environment variable is present. lookup is
+// normally os.LookupEnv.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	var err error
+	walk(reflect.ValueOf(c).Elem(), "", func(key string, field reflect.Value) {
+		if err != nil {
+			return
+		}
+		name := EnvName(key)
+		if value, ok := lookup(name); ok {
+			if setErr := setValue(field, value); setErr != nil {
+				err = fmt.Errorf("%s: %w", name, setErr)
+			}
+		}
+	})
+	return err
+}
+
+// Set assigns a value, given as a string, to the dotted config key. Slices
+// are comma-separated and durations use time.ParseDuration syntax.
+func (c *Config) Set(key, value string) error {
+	var found bool
+	var err error
+	walk(reflect.ValueOf(c).Elem(), "", func(k string, field reflect.Value) {
+		if k == key {
+			found = true
+			err = setValue(field, value)
+		}
+	})
+
+	if !found {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field of the struct v with its dotted key
+func walk(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Map {
+			// Tables keyed by name have no single environment variable
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			walk(field, key, fn)
+			continue
+		}
+		fn(key, field)
+	}
+}
+
+// setValue parses value into field according to the field's type
+func setValue(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/hostkey"
+)
+
+// HostKeyOptions defines options for managing the SSH host keys
+type HostKeyOptions struct {
+	// Dir holds hostkey.age, normally assets/data
+	Dir string
+
+	// Algorithms are the key types to generate
+	Algorithms []string
+
+	// Force overwrites existing host keys when generating
+	Force bool
+
+	// Hosts and Port are the addresses clients reach the server at, for
+	// known_hosts lines
+	Hosts []string
+	Port  string
+}
+
+// DefaultHostKeyOptions returns default options for managing the SSH host
+// keys: every algorithm, for localhost on the default SSH port
+func DefaultHostKeyOptions() HostKeyOptions {
+	return HostKeyOptions{
+		Dir:        "assets/data",
+		Algorithms: hostkey.Algorithms,
+		Hosts:      []string{"localhost"},
+		Port:       "2222",
+	}
+}
+
+// path returns the location of the encrypted host keys
+func (o HostKeyOptions) path() string {
+	return filepath.Join(o.Dir, "hostkey.age")
+}
+
+// GenerateHostKeys creates a host key for each algorithm and encrypts them
+// into hostkey.age. Existing keys are only replaced with Force, since every
+// client that trusted them will refuse the new ones.
+func GenerateHostKeys(opts HostKeyOptions) error {
+	if !opts.Force {
+		if info, err := os.Stat(opts.path()); err == nil && info.Size() > 0 {
+			return fmt.Errorf("file %s already exists, use --force to overwrite or rotate to replace it with a backup", opts.path())
+		}
+	}
+
+	signers, err := writeHostKeys(opts)
+	if err != nil {
+		return err
+	}
+	for _, signer := range signers {
+		fmt.Printf("✓ Generated %s host key %s\n",
+			hostkey.Algorithm(signer.PublicKey()), cryptossh.FingerprintSHA256(signer.PublicKey()))
+	}
+	fmt.Printf("✓ Created %s\n", opts.path())
+	return nil
+}
+
+// RotateHostKeys replaces the host keys with new keys of the same
+// algorithms, or of opts.Algorithms when set, keeping the previous file as
+// hostkey.age.bak
+func RotateHostKeys(ctx context.Context, opts HostKeyOptions) error {
+	previous, err := readHostKeys(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Algorithms) == 0 {
+		for _, signer := range previous {
+			opts.Algorithms = append(opts.Algorithms, hostkey.Algorithm(signer.PublicKey()))
+		}
+	}
+
+	backupPath := opts.path() + ".bak"
+	if err := createBackup(opts.path(), backupPath); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", opts.path(), err)
+	}
+	fmt.Printf("✓ Backed up previous host keys to %s\n", backupPath)
+
+	signers, err := writeHostKeys(opts)
+	if err != nil {
+		return err
+	}
+	for _, signer := range previous {
+		fmt.Printf("  - %s %s\n", hostkey.Algorithm(signer.PublicKey()), cryptossh.FingerprintSHA256(signer.PublicKey()))
+	}
+	for _, signer := range signers {
+		fmt.Printf("  + %s %s\n", hostkey.Algorithm(signer.PublicKey()), cryptossh.FingerprintSHA256(signer.PublicKey()))
+	}
+	fmt.Printf("✓ Rotated %s\n", opts.path())
+	fmt.Printf("Clients will see a changed host key: distribute the lines of prospero dev hostkey fingerprint\n")
+	return nil
+}
+
+// PrintHostKeys prints the fingerprint and known_hosts line of every host
+// key, for distribution to clients
+func PrintHostKeys(ctx context.Context, opts HostKeyOptions) error {
+	signers, err := readHostKeys(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, signer := range signers {
+		key := signer.PublicKey()
+		fmt.Printf("# %s %s\n", hostkey.Algorithm(key), cryptossh.FingerprintSHA256(key))
+		fmt.Println(hostkey.KnownHosts(opts.Hosts, opts.Port, key))
+	}
+	return nil
+}
+
+// readHostKeys decrypts and parses hostkey.age
+func readHostKeys(ctx context.Context, opts HostKeyOptions) ([]cryptossh.Signer, error) {
+	data, err := os.ReadFile(opts.path())
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil, fmt.Errorf("no host keys in %s, create them with prospero dev hostkey generate", opts.Dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host keys: %w", err)
+	}
+
+	decrypted, err := decryptAgeData(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", opts.path(), err)
+	}
+	return hostkey.Parse(decrypted)
+}
+
+// writeHostKeys generates the keys of opts.Algorithms and encrypts them
+// into hostkey.age with AGE_ENCRYPTION_PASSWORD
+func writeHostKeys(opts HostKeyOptions) ([]cryptossh.Signer, error) {
+	password := os.Getenv("AGE_ENCRYPTION_PASSWORD")
+	if password == "" {
+		return nil, fmt.Errorf("AGE_ENCRYPTION_PASSWORD environment variable is not set")
+	}
+	recipient, err := age.NewScryptRecipient(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create age recipient: %w", err)
+	}
+
+	keys, err := hostkey.GenerateAll(opts.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := hostkey.Parse(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryptWithRecipient(keys, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt host keys: %w", err)
+	}
+	if err := writeFileAtomically(opts.path(), encrypted); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", opts.path(), err)
+	}
+	return signers, nil
+}
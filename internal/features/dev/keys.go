@@ -0,0 +1,159 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"filippo.io/age"
+
+	"prospero/internal/auth"
+)
+
+// KeysOptions defines options for managing API keys
+type KeysOptions struct {
+	// Dir holds keys.json.age, normally assets/data
+	Dir string
+}
+
+// DefaultKeysOptions returns default options for managing API keys
+func DefaultKeysOptions() KeysOptions {
+	return KeysOptions{
+		Dir: "assets/data",
+	}
+}
+
+// path returns the location of the encrypted keys file
+func (o KeysOptions) path() string {
+	return filepath.Join(o.Dir, "keys.json.age")
+}
+
+// CreateKey adds a key granting scopes to the keys file and prints its token,
+// which cannot be recovered later
+func CreateKey(ctx context.Context, opts KeysOptions, name string, scopes []string, limit *auth.RateLimit) error {
+	file, err := readKeyFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	key, token, err := auth.NewKey(name, scopes, limit)
+	if err != nil {
+		return err
+	}
+	file.Keys = append(file.Keys, key)
+
+	if err := writeKeyFile(opts, file); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Created key %s (%s) with scopes %s\n", key.ID, key.Name, strings.Join(key.Scopes, ", "))
+	fmt.Printf("\n  %s\n\n", token)
+	fmt.Printf("⚠️  Store this token now, it cannot be shown again.\n")
+	fmt.Printf("Rebuild the binary to embed %s, or serve it with --data-dir.\n", opts.path())
+	return nil
+}
+
+// ListKeys prints every key in the keys file
+func ListKeys(ctx context.Context, opts KeysOptions) error {
+	file, err := readKeyFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(file.Keys) == 0 {
+		fmt.Printf("No API keys in %s\n", opts.path())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tNAME\tSCOPES\tRATE LIMIT\tCREATED\tSTATUS\n")
+	for _, key := range file.Keys {
+		limit := "default"
+		if key.RateLimit != nil {
+			limit = key.RateLimit.String()
+		}
+		status := "active"
+		if key.Revoked() {
+			status = "revoked " + key.RevokedAt.Format(time.DateOnly)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, strings.Join(key.Scopes, ","), limit, key.CreatedAt.Format(time.DateOnly), status)
+	}
+	return w.Flush()
+}
+
+// RevokeKey marks the key with id as revoked. Revoked keys are kept in the
+// file so their IDs remain recognisable.
+func RevokeKey(ctx context.Context, opts KeysOptions, id string) error {
+	file, err := readKeyFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	key, ok := file.Find(id)
+	if !ok {
+		return fmt.Errorf("no key with ID %s in %s", id, opts.path())
+	}
+	if key.Revoked() {
+		return fmt.Errorf("key %s was already revoked on %s", id, key.RevokedAt.Format(time.DateOnly))
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key.RevokedAt = &now
+
+	if err := writeKeyFile(opts, file); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Revoked key %s (%s)\n", key.ID, key.Name)
+	return nil
+}
+
+// readKeyFile decrypts the keys file. A missing or empty file has no keys.
+func readKeyFile(ctx context.Context, opts KeysOptions) (*auth.KeyFile, error) {
+	data, err := os.ReadFile(opts.path())
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(data) == 0) {
+		return &auth.KeyFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys file: %w", err)
+	}
+
+	decrypted, err := decryptAgeData(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", opts.path(), err)
+	}
+	return auth.ParseKeyFile(decrypted)
+}
+
+// writeKeyFile encrypts the keys file with AGE_ENCRYPTION_PASSWORD
+func writeKeyFile(opts KeysOptions, file *auth.KeyFile) error {
+	password := os.Getenv("AGE_ENCRYPTION_PASSWORD")
+	if password == "" {
+		return fmt.Errorf("AGE_ENCRYPTION_PASSWORD environment variable is not set")
+	}
+	recipient, err := age.NewScryptRecipient(password)
+	if err != nil {
+		return fmt.Errorf("failed to create age recipient: %w", err)
+	}
+
+	data, err := file.Marshal()
+	if err != nil {
+		return err
+	}
+	encrypted, err := encryptWithRecipient(data, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keys file: %w", err)
+	}
+
+	if err := writeFileAtomically(opts.path(), encrypted); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.path(), err)
+	}
+	return nil
+}
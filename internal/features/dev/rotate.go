@@ -68,6 +68,20 @@ func RotateKeys(ctx context.Context, opts RotateKeyOptions) error {
 		},
 	}
 
+	// Optional files stay empty until an API key is created or an SSH
+	// allowlist or TLS certificate is embedded
+	for _, optional := range []ageFile{
+		{Name: "keys.json.age", EmbeddedData: assets.GetEmbeddedKeys()},
+		{Name: "authorized_keys.age", EmbeddedData: assets.GetEmbeddedAuthorizedKeys()},
+		{Name: "tls.crt.age", EmbeddedData: assets.GetEmbeddedTLSCert()},
+		{Name: "tls.key.age", EmbeddedData: assets.GetEmbeddedTLSKey()},
+	} {
+		if len(optional.EmbeddedData) > 0 {
+			optional.OutputPath = "assets/data/" + optional.Name
+			files = append(files, optional)
+		}
+	}
+
 	fmt.Printf("🔑 Starting key rotation for %d files...\n", len(files))
 
 	if opts.DryRun {
@@ -0,0 +1,92 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"prospero/internal/sshaudit"
+)
+
+// SessionsOptions defines options for inspecting the SSH session audit log
+type SessionsOptions struct {
+	// Dir is the audit directory configured as ssh.audit.dir
+	Dir string
+
+	// Speed and IdleLimit control replays
+	Speed     float64
+	IdleLimit time.Duration
+}
+
+// ListSessions prints every session of the audit log, oldest first
+func ListSessions(opts SessionsOptions) error {
+	events, err := sshaudit.ReadEvents(opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No sessions in %s\n", filepath.Join(opts.Dir, sshaudit.LogFile))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tSTART\tUSER\tREMOTE\tKEY\tCOMMAND\tEXIT\tDURATION\tBYTES OUT\tRECORDED\n")
+	for _, e := range events {
+		command := e.Command
+		switch {
+		case e.Subsystem != "":
+			command = "(" + e.Subsystem + ")"
+		case command == "" && e.Interactive:
+			command = "(interactive)"
+		case command == "":
+			command = "(help)"
+		}
+		key := "-"
+		if e.KeyFingerprint != "" {
+			key = e.KeyFingerprint
+		}
+		exit := fmt.Sprint(e.ExitStatus)
+		if e.Limit != "" {
+			exit += " (" + e.Limit + ")"
+		}
+		recorded := "no"
+		if e.Recording != "" {
+			recorded = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			e.ShortID(), e.Start.Local().Format(time.DateTime), e.User, e.RemoteAddr, key, command,
+			exit, e.Duration().Round(time.Millisecond), e.BytesOut, recorded)
+	}
+	return w.Flush()
+}
+
+// ReplaySession plays the recording of the session whose ID starts with id
+// on the terminal
+func ReplaySession(ctx context.Context, opts SessionsOptions, id string) error {
+	events, err := sshaudit.ReadEvents(opts.Dir)
+	if err != nil {
+		return err
+	}
+	event, err := sshaudit.Find(events, id)
+	if err != nil {
+		return err
+	}
+	if event.Recording == "" {
+		return fmt.Errorf("session %s was not recorded", event.ShortID())
+	}
+
+	file, err := os.Open(filepath.Join(opts.Dir, event.Recording))
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	return sshaudit.Replay(ctx, file, os.Stdout, sshaudit.ReplayOptions{
+		Speed:     opts.Speed,
+		IdleLimit: opts.IdleLimit,
+	})
+}
@@ -0,0 +1,97 @@
+package dev
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"golang.org/x/term"
+
+	"prospero/internal/sshauth"
+)
+
+// PackAuthorizedKeys encrypts an authorized_keys file, ./authorized_keys
+// unless opts.InputFile is set, into authorized_keys.age for embedding. The
+// keys are parsed first, so a malformed allowlist is never embedded.
+func PackAuthorizedKeys(opts PackOptions) error {
+	input := opts.InputFile
+	if input == "" {
+		input = "authorized_keys"
+	}
+	output := filepath.Join(opts.OutputDir, "authorized_keys.age")
+
+	// The placeholder committed for the embed directive is empty
+	if info, err := os.Stat(output); err == nil && info.Size() > 0 && !opts.Force {
+		return fmt.Errorf("file %s already exists, use --force to overwrite", output)
+	}
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read authorized keys: %w", err)
+	}
+	keys, err := sshauth.ParseAuthorizedKeys(data, sshauth.RoleUser)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", input, err)
+	}
+	admins := 0
+	for _, key := range keys {
+		if key.Role == sshauth.RoleAdmin {
+			admins++
+		}
+	}
+	fmt.Printf("✓ Loaded %d keys (%d admin) from %s\n", len(keys), admins, input)
+
+	password := os.Getenv("AGE_ENCRYPTION_PASSWORD")
+	if password == "" {
+		return fmt.Errorf("AGE_ENCRYPTION_PASSWORD environment variable is not set")
+	}
+	recipient, err := age.NewScryptRecipient(password)
+	if err != nil {
+		return fmt.Errorf("failed to create age recipient: %w", err)
+	}
+
+	encrypted, err := encryptWithRecipient(data, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", input, err)
+	}
+	if err := writeFileAtomically(output, encrypted); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("✓ Created %s\n", output)
+
+	return nil
+}
+
+// HashSSHPassword prints the bcrypt hash of a password for an SSH password
+// user. The password is prompted for without echo on a terminal and read
+// from the first line of stdin otherwise.
+func HashSSHPassword() error {
+	var password string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		password = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	hash, err := sshauth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hash)
+	return nil
+}
@@ -0,0 +1,69 @@
+package dev
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"filippo.io/age"
+
+	"prospero/internal/tlscert"
+)
+
+// PackTLS encrypts tls.crt and tls.key from the current directory into
+// tls.crt.age and tls.key.age for embedding. The pair is checked first, so a
+// mismatched or expired certificate is never embedded.
+func PackTLS(opts PackOptions) error {
+	files := []struct{ input, output string }{
+		{"tls.crt", filepath.Join(opts.OutputDir, "tls.crt.age")},
+		{"tls.key", filepath.Join(opts.OutputDir, "tls.key.age")},
+	}
+
+	if !opts.Force {
+		for _, file := range files {
+			// The placeholders committed for the embed directives are empty
+			if info, err := os.Stat(file.output); err == nil && info.Size() > 0 {
+				return fmt.Errorf("file %s already exists, use --force to overwrite", file.output)
+			}
+		}
+	}
+
+	certPEM, err := os.ReadFile(files[0].input)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(files[1].input)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	cert, err := tlscert.Parse(certPEM, keyPEM, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Loaded certificate for %s (expires %s)\n",
+		tlscert.Names(cert), cert.Leaf.NotAfter.Format(time.DateOnly))
+
+	password := os.Getenv("AGE_ENCRYPTION_PASSWORD")
+	if password == "" {
+		return fmt.Errorf("AGE_ENCRYPTION_PASSWORD environment variable is not set")
+	}
+	recipient, err := age.NewScryptRecipient(password)
+	if err != nil {
+		return fmt.Errorf("failed to create age recipient: %w", err)
+	}
+
+	for i, data := range [][]byte{certPEM, keyPEM} {
+		encrypted, err := encryptWithRecipient(data, recipient)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", files[i].input, err)
+		}
+		if err := writeFileAtomically(files[i].output, encrypted); err != nil {
+			return fmt.Errorf("failed to write %s: %w", files[i].output, err)
+		}
+		fmt.Printf("✓ Created %s\n", files[i].output)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package features
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit statuses of SSH sessions, one per class of failure, so scripts can
+// tell why a command failed without parsing its output
+const (
+	ExitInternal    = 1 // The server failed to answer
+	ExitUsage       = 2 // The command line is wrong
+	ExitNotFound    = 3 // The requested work, character or list does not exist
+	ExitDenied      = 4 // The client's role may not run the command
+	ExitRateLimited = 5 // The client ran too many commands
+	ExitLimit       = 6 // The session was refused or closed by a session limit
+)
+
+// CommandError is the failure of a command. Over SSH, Message is written to
+// the session's stderr and Status is its exit status; Err, if any, is the
+// cause, which is logged but not shown to the client.
+type CommandError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// UsageError reports a command line the command does not understand
+func UsageError(format string, args ...any) error {
+	return &CommandError{Status: ExitUsage, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFoundError reports that what the command was asked for does not exist
+func NotFoundError(format string, args ...any) error {
+	return &CommandError{Status: ExitNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// DeniedError reports that the client's role may not run the command
+func DeniedError(format string, args ...any) error {
+	return &CommandError{Status: ExitDenied, Message: fmt.Sprintf(format, args...)}
+}
+
+// InternalError reports a failure of the server, described to the client
+// by message
+func InternalError(message string, err error) error {
+	return &CommandError{Status: ExitInternal, Message: message, Err: err}
+}
+
+// AsCommandError returns err as a CommandError. Any other error is an
+// internal error with a generic message.
+func AsCommandError(err error) *CommandError {
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		return commandErr
+	}
+	return &CommandError{Status: ExitInternal, Message: "Internal error", Err: err}
+}
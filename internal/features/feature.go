@@ -0,0 +1,191 @@
+// Package features defines the Feature interface implemented by each package
+// under internal/features, and the Registry the application assembles its
+// CLI and SSH commands, HTTP routes and MCP tools from.
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/mcp"
+)
+
+// Feature is a self-contained module that plugs into every interface
+type Feature interface {
+	// Name identifies the feature in logs, metrics and readiness checks
+	Name() string
+
+	// Help describes the feature for help pages and the info endpoint
+	Help() Help
+
+	// Open initializes the feature's service. It is called once before any
+	// routes, SSH commands or MCP tools are used.
+	Open(ctx context.Context) error
+
+	// Close releases resources acquired by Open
+	Close() error
+
+	// Check reports whether the feature is ready to serve traffic
+	Check(ctx context.Context) error
+
+	// Commands returns the feature's commands, which the CLI and the SSH
+	// server both run. Each call returns new commands, since urfave/cli
+	// keeps parsing state in them. Actions write to c.App.Writer and open
+	// the feature themselves unless it is already open, as it is in the
+	// server. A CommandError sets the message and exit status over SSH.
+	Commands() []*cli.Command
+
+	// RegisterRoutes mounts the feature's HTTP routes on the API router
+	RegisterRoutes(r chi.Router, opts RouteOptions)
+
+	// MCPTools returns the tools the feature exposes over MCP
+	MCPTools() []MCPTool
+
+	// MCPPrompts returns the prompts the feature exposes over MCP
+	MCPPrompts() []MCPPrompt
+}
+
+// Exporter is implemented by features whose service the SSH server also
+// presents outside their commands, in the interactive browser and the
+// exported filesystem. Those pick the services they can use by the
+// interfaces the services implement.
+type Exporter interface {
+	// Export returns the feature's service, or nil before Open
+	Export() any
+}
+
+// Help is the metadata shown on help pages
+type Help struct {
+	Summary   string
+	Endpoints []Endpoint // relative to the API prefix
+	Examples  []string   // command lines, e.g. "topten --color"
+}
+
+// Endpoint describes an HTTP endpoint for the info page
+type Endpoint struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Parameters  string `json:"parameters,omitempty"`
+}
+
+// RouteOptions carries server-wide settings features need when mounting
+// routes
+type RouteOptions struct {
+	// DataVersion identifies the served data, embedded or from the data
+	// directory, for ETags
+	DataVersion string
+
+	// DataModTime is when the served data last changed
+	DataModTime time.Time
+}
+
+// MCPTool pairs an MCP tool definition with its handler
+type MCPTool struct {
+	Tool    mcp.Tool
+	Handler mcp.ToolHandler
+}
+
+// MCPPrompt pairs an MCP prompt definition with its handler
+type MCPPrompt struct {
+	Prompt  mcp.Prompt
+	Handler mcp.PromptHandler
+}
+
+// Registry holds the features the application is assembled from
+type Registry struct {
+	features []Feature
+}
+
+// NewRegistry creates a registry of features in display order
+func NewRegistry(features ...Feature) *Registry {
+	return &Registry{features: features}
+}
+
+// All returns the registered features
+func (r *Registry) All() []Feature {
+	return r.features
+}
+
+// Open opens every feature, closing those already opened if one fails
+func (r *Registry) Open(ctx context.Context) error {
+	for i, f := range r.features {
+		if err := f.Open(ctx); err != nil {
+			for _, opened := range r.features[:i] {
+				opened.Close()
+			}
+			return fmt.Errorf("failed to initialize %s: %w", f.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Close closes every feature
+func (r *Registry) Close() error {
+	var errs []error
+	for _, f := range r.features {
+		if err := f.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Commands returns the CLI commands of every feature
+func (r *Registry) Commands() []*cli.Command {
+	var commands []*cli.Command
+	for _, f := range r.features {
+		commands = append(commands, f.Commands()...)
+	}
+	return commands
+}
+
+// Exports returns the services of the features that export one
+func (r *Registry) Exports() []any {
+	var services []any
+	for _, f := range r.features {
+		if exporter, ok := f.(Exporter); ok {
+			if service := exporter.Export(); service != nil {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
+
+// Endpoints returns the HTTP endpoints of every feature with prefix
+// prepended to their paths
+func (r *Registry) Endpoints(prefix string) []Endpoint {
+	var endpoints []Endpoint
+	for _, f := range r.features {
+		for _, endpoint := range f.Help().Endpoints {
+			endpoint.Path = prefix + endpoint.Path
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// RegisterRoutes mounts the HTTP routes of every feature
+func (r *Registry) RegisterRoutes(router chi.Router, opts RouteOptions) {
+	for _, f := range r.features {
+		f.RegisterRoutes(router, opts)
+	}
+}
+
+// RegisterMCP registers the tools and prompts of every feature
+func (r *Registry) RegisterMCP(server *mcp.Server) {
+	for _, f := range r.features {
+		for _, tool := range f.MCPTools() {
+			server.RegisterTool(tool.Tool, tool.Handler)
+		}
+		for _, prompt := range f.MCPPrompts() {
+			server.RegisterPrompt(prompt.Prompt, prompt.Handler)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package features_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/features"
+)
+
+type fakeFeature struct {
+	name    string
+	openErr error
+	opened  bool
+	closed  bool
+	help    features.Help
+}
+
+func (f *fakeFeature) Name() string                     { return f.name }
+func (f *fakeFeature) Help() features.Help              { return f.help }
+func (f *fakeFeature) Check(context.Context) error      { return nil }
+func (f *fakeFeature) Commands() []*cli.Command         { return []*cli.Command{{Name: f.name}} }
+func (f *fakeFeature) MCPTools() []features.MCPTool     { return nil }
+func (f *fakeFeature) MCPPrompts() []features.MCPPrompt { return nil }
+
+func (f *fakeFeature) RegisterRoutes(chi.Router, features.RouteOptions) {}
+
+func (f *fakeFeature) Open(context.Context) error {
+	if f.openErr != nil {
+		return f.openErr
+	}
+	f.opened = true
+	return nil
+}
+
+func (f *fakeFeature) Close() error {
+	f.closed = true
+	return nil
+}
+
+// exportingFeature is a feature exporting its service once opened
+type exportingFeature struct {
+	fakeFeature
+	service any
+}
+
+func (f *exportingFeature) Export() any {
+	if !f.opened {
+		return nil
+	}
+	return f.service
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("should close opened features when a later one fails to open", func(t *testing.T) {
+		first := &fakeFeature{name: "first"}
+		second := &fakeFeature{name: "second", openErr: errors.New("boom")}
+		registry := features.NewRegistry(first, second)
+
+		err := registry.Open(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to initialize second")
+		assert.True(t, first.closed)
+	})
+
+	t.Run("should collect CLI commands in registration order", func(t *testing.T) {
+		registry := features.NewRegistry(&fakeFeature{name: "a"}, &fakeFeature{name: "b"})
+
+		commands := registry.Commands()
+		require.Len(t, commands, 2)
+		assert.Equal(t, "a", commands[0].Name)
+		assert.Equal(t, "b", commands[1].Name)
+	})
+
+	t.Run("should prefix feature endpoints", func(t *testing.T) {
+		registry := features.NewRegistry(&fakeFeature{
+			name: "books",
+			help: features.Help{Endpoints: []features.Endpoint{{Method: "GET", Path: "/books"}}},
+		})
+
+		endpoints := registry.Endpoints("/api/v1")
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, "/api/v1/books", endpoints[0].Path)
+	})
+
+	t.Run("should collect the services of opened exporters", func(t *testing.T) {
+		books := &exportingFeature{fakeFeature: fakeFeature{name: "books"}, service: "library"}
+		registry := features.NewRegistry(&fakeFeature{name: "a"}, books)
+		assert.Empty(t, registry.Exports())
+
+		require.NoError(t, registry.Open(context.Background()))
+		assert.Equal(t, []any{"library"}, registry.Exports())
+	})
+}
+
+func TestAsCommandError(t *testing.T) {
+	t.Run("should keep the class of command errors", func(t *testing.T) {
+		err := fmt.Errorf("running books: %w", features.NotFoundError("Book not found: %s", "dune"))
+
+		commandErr := features.AsCommandError(err)
+		assert.Equal(t, features.ExitNotFound, commandErr.Status)
+		assert.Equal(t, "Book not found: dune", commandErr.Message)
+		assert.Equal(t, features.ExitUsage, features.AsCommandError(features.UsageError("books needs an ID")).Status)
+	})
+
+	t.Run("should hide the cause of internal errors from the message", func(t *testing.T) {
+		cause := errors.New("database is locked")
+
+		commandErr := features.AsCommandError(features.InternalError("Error listing books", cause))
+		assert.Equal(t, features.ExitInternal, commandErr.Status)
+		assert.Equal(t, "Error listing books", commandErr.Message)
+		assert.ErrorIs(t, commandErr, cause)
+
+		commandErr = features.AsCommandError(cause)
+		assert.Equal(t, features.ExitInternal, commandErr.Status)
+		assert.Equal(t, "Internal error", commandErr.Message)
+	})
+}
+
+// runFormat runs a command with the format flags on args and returns the
+// format it chose
+func runFormat(t *testing.T, args ...string) (features.Format, error) {
+	t.Helper()
+
+	var format features.Format
+	app := &cli.App{
+		Name: "test",
+		Commands: []*cli.Command{{
+			Name:  "works",
+			Flags: features.FormatFlags(),
+			Action: func(c *cli.Context) error {
+				var err error
+				format, err = features.OutputFormat(c)
+				return err
+			},
+		}},
+	}
+	err := app.Run(append([]string{"test", "works"}, args...))
+	return format, err
+}
+
+func TestOutputFormat(t *testing.T) {
+	t.Run("should default to text", func(t *testing.T) {
+		format, err := runFormat(t)
+		require.NoError(t, err)
+		assert.Equal(t, features.FormatText, format)
+	})
+
+	t.Run("should accept every spelling of the format option", func(t *testing.T) {
+		for want, args := range map[features.Format][]string{
+			features.FormatJSON:     {"--json"},
+			features.FormatCSV:      {"--format=csv"},
+			features.FormatMarkdown: {"--format", "MD"},
+		} {
+			format, err := runFormat(t, args...)
+			require.NoError(t, err, args)
+			assert.Equal(t, want, format, args)
+		}
+	})
+
+	t.Run("should reject unknown formats as usage errors", func(t *testing.T) {
+		_, err := runFormat(t, "--format", "yaml")
+		assert.Equal(t, features.ExitUsage, features.AsCommandError(err).Status)
+	})
+}
+
+func TestWriteMarkdownTable(t *testing.T) {
+	var b strings.Builder
+	features.WriteMarkdownTable(&b, []string{"id", "title"}, [][]string{{"hamlet", "Hamlet | Prince"}})
+
+	assert.Equal(t, "| id | title |\n| --- | --- |\n| hamlet | Hamlet \\| Prince |\n", b.String())
+}
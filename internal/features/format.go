@@ -0,0 +1,134 @@
+package features
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// Format is the output format of a command
+type Format string
+
+// Output formats of commands. JSON matches the schemas of the HTTP API.
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatCSV      Format = "csv"
+)
+
+// Formats lists every output format for help and completion
+var Formats = []string{string(FormatText), string(FormatJSON), string(FormatMarkdown), string(FormatCSV)}
+
+// FormatFlags returns the output format options of a command: --format and
+// --json, short for --format json
+func FormatFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Value: string(FormatText),
+			Usage: "Output format: " + strings.Join(Formats, ", "),
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Same as --format json",
+		},
+	}
+}
+
+// OutputFormat returns the format chosen with the FormatFlags of c
+func OutputFormat(c *cli.Context) (Format, error) {
+	if c.Bool("json") {
+		return FormatJSON, nil
+	}
+	return parseFormatName(c.String("format"))
+}
+
+// ColorFlags returns the options choosing between colored and plain text
+// output
+func ColorFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "color",
+			Usage: "Use fancy colored output",
+		},
+		&cli.BoolFlag{
+			Name:  "ascii",
+			Usage: "Use plain text output, without colors",
+		},
+	}
+}
+
+// UseColor reports whether the text output of c is colored. Without
+// --color or --ascii, only output written to a terminal is.
+func UseColor(c *cli.Context) bool {
+	switch {
+	case c.Bool("ascii"):
+		return false
+	case c.Bool("color"):
+		return true
+	}
+	f, ok := c.App.Writer.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// parseFormatName returns the format called name
+func parseFormatName(name string) (Format, error) {
+	name = strings.ToLower(name)
+	if name == "md" {
+		return FormatMarkdown, nil
+	}
+	for _, format := range Formats {
+		if name == format {
+			return Format(format), nil
+		}
+	}
+	return "", UsageError("Unknown format: %s. Use %s", name, strings.Join(Formats, ", "))
+}
+
+// WriteJSON writes v to w the way the HTTP API encodes its responses
+func WriteJSON(w io.Writer, v any) error {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return InternalError("Error encoding JSON", err)
+	}
+	return nil
+}
+
+// WriteCSV writes a header line followed by rows to w
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return InternalError("Error writing CSV", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return InternalError("Error writing CSV", err)
+	}
+	return nil
+}
+
+// WriteMarkdownTable writes header and rows to w as a Markdown table
+func WriteMarkdownTable(w io.Writer, header []string, rows [][]string) {
+	writeMarkdownRow(w, header)
+	separators := make([]string, len(header))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeMarkdownRow(w, separators)
+	for _, row := range rows {
+		writeMarkdownRow(w, row)
+	}
+}
+
+func writeMarkdownRow(w io.Writer, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+}
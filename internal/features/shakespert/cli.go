@@ -0,0 +1,210 @@
+package shakespert
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/features"
+)
+
+func (f *Feature) Commands() []*cli.Command {
+	return []*cli.Command{f.command()}
+}
+
+func (f *Feature) command() *cli.Command {
+	return &cli.Command{
+		Name:        "shakespert",
+		Aliases:     []string{"shakespeare", "works"},
+		Usage:       "Access Shakespeare's complete works",
+		Description: `Access William Shakespeare's complete works including plays, poems, and sonnets.`,
+		Action:      unknownSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:        "works",
+				Usage:       "List all Shakespeare works",
+				Description: `List all of Shakespeare's works with basic information.`,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:    "genre",
+						Aliases: []string{"g"},
+						Usage:   "Filter works by genre (c=Comedy, h=History, p=Poem, s=Sonnet, t=Tragedy)",
+					},
+				}, features.FormatFlags()...),
+				Action: func(c *cli.Context) error {
+					return f.withService(c, func(service *Service, format features.Format) error {
+						return printWorks(c, service, format)
+					})
+				},
+			},
+			{
+				Name:         "work",
+				Usage:        "Show details about a specific work",
+				ArgsUsage:    "<workID>",
+				Description:  `Show detailed information about a specific Shakespeare work by ID.`,
+				Flags:        features.FormatFlags(),
+				BashComplete: f.completeWorkIDs,
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return features.UsageError("work requires exactly one work ID. Example: shakespert work hamlet")
+					}
+					return f.withService(c, func(service *Service, format features.Format) error {
+						workID := c.Args().Get(0)
+						work, err := service.GetWork(c.Context, workID)
+						if err != nil {
+							return workError(workID, err)
+						}
+						return writeWork(c.App.Writer, format, work)
+					})
+				},
+			},
+			{
+				Name:        "genres",
+				Usage:       "List all genres",
+				Description: `List all available genres in the Shakespeare collection.`,
+				Flags:       features.FormatFlags(),
+				Action: func(c *cli.Context) error {
+					return f.withService(c, func(service *Service, format features.Format) error {
+						genres, err := service.ListGenres(c.Context)
+						if err != nil {
+							return features.InternalError("Error listing genres", err)
+						}
+						return writeGenres(c.App.Writer, format, genres)
+					})
+				},
+			},
+			{
+				Name:         "characters",
+				Usage:        "List the characters of a work",
+				ArgsUsage:    "<workID>",
+				Description:  `List the characters of a work with the number of speeches each has.`,
+				Flags:        features.FormatFlags(),
+				BashComplete: f.completeWorkIDs,
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return features.UsageError("characters requires exactly one work ID. Example: shakespert characters hamlet")
+					}
+					return f.withService(c, func(service *Service, format features.Format) error {
+						workID := c.Args().Get(0)
+						work, err := service.GetWork(c.Context, workID)
+						if err != nil {
+							return workError(workID, err)
+						}
+
+						characters, err := service.ListCharacters(c.Context, workID)
+						if err != nil {
+							return features.InternalError("Error listing characters", err)
+						}
+						return writeCharacters(c.App.Writer, format, work, characters)
+					})
+				},
+			},
+			{
+				Name:         "character",
+				Usage:        "Show details for a character",
+				ArgsUsage:    "<charID>",
+				Description:  `Show a character's description, the works they appear in and their number of speeches.`,
+				Flags:        features.FormatFlags(),
+				BashComplete: f.completeCharacterIDs,
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return features.UsageError("character requires exactly one character ID. Example: shakespert character hamlet")
+					}
+					return f.withService(c, func(service *Service, format features.Format) error {
+						charID := c.Args().Get(0)
+						character, err := service.GetCharacter(c.Context, charID)
+						if errors.Is(err, ErrCharacterNotFound) {
+							return features.NotFoundError("Character not found: %s", charID)
+						}
+						if err != nil {
+							return features.InternalError("Error getting character", err)
+						}
+						return writeCharacter(c.App.Writer, format, character)
+					})
+				},
+			},
+		},
+	}
+}
+
+// unknownSubcommand shows the help of shakespert, or reports the subcommand
+// it was given as unknown
+func unknownSubcommand(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return cli.ShowSubcommandHelp(c)
+	}
+	return features.UsageError("Unknown shakespert subcommand: %s\n"+
+		"Available subcommands: works, work <id>, genres, characters <id>, character <id>", c.Args().First())
+}
+
+// withService runs fn with the feature's service and the output format of
+// the command. The CLI opens the feature for the duration of the command;
+// in the server it is already open and stays so.
+func (f *Feature) withService(c *cli.Context, fn func(service *Service, format features.Format) error) error {
+	format, err := features.OutputFormat(c)
+	if err != nil {
+		return err
+	}
+
+	if f.service == nil {
+		if err := f.Open(c.Context); err != nil {
+			return features.InternalError("Failed to initialize shakespert service", err)
+		}
+		defer f.Close()
+	}
+
+	return fn(f.service, format)
+}
+
+func printWorks(c *cli.Context, service *Service, format features.Format) error {
+	var err error
+	var works []WorkSummary
+
+	if genre := c.String("genre"); genre != "" {
+		works, err = service.GetWorksByGenre(c.Context, genre)
+		if err != nil {
+			return features.InternalError("Error getting works by genre", err)
+		}
+	} else {
+		works, err = service.ListWorks(c.Context)
+		if err != nil {
+			return features.InternalError("Error listing works", err)
+		}
+	}
+
+	return writeWorks(c.App.Writer, format, works)
+}
+
+// completeWorkIDs prints the ID of every work, one per line, for shell
+// completion
+func (f *Feature) completeWorkIDs(c *cli.Context) {
+	if f.service == nil {
+		return
+	}
+	works, err := f.service.ListWorks(c.Context)
+	if err != nil {
+		slog.WarnContext(c.Context, "failed to complete work IDs", "error", err)
+		return
+	}
+	for _, work := range works {
+		fmt.Fprintln(c.App.Writer, work.WorkID)
+	}
+}
+
+// completeCharacterIDs prints the ID of every character, one per line, for
+// shell completion
+func (f *Feature) completeCharacterIDs(c *cli.Context) {
+	if f.service == nil {
+		return
+	}
+	ids, err := f.service.ListCharacterIDs(c.Context)
+	if err != nil {
+		slog.WarnContext(c.Context, "failed to complete character IDs", "error", err)
+		return
+	}
+	for _, id := range ids {
+		fmt.Fprintln(c.App.Writer, id)
+	}
+}
@@ -0,0 +1,15 @@
+package shakespert
+
+import "errors"
+
+// Errors of the Shakespeare database lookups
+var (
+	// ErrWorkNotFound is returned when a work ID does not exist
+	ErrWorkNotFound = errors.New("work not found")
+
+	// ErrSceneNotFound is returned when a work has no such act and scene
+	ErrSceneNotFound = errors.New("scene not found")
+
+	// ErrCharacterNotFound is returned when a character ID does not exist
+	ErrCharacterNotFound = errors.New("character not found")
+)
@@ -0,0 +1,112 @@
+package shakespert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/auth"
+	"prospero/internal/features"
+	"prospero/internal/web/middleware"
+)
+
+// Feature plugs Shakespeare's works into the CLI, HTTP, SSH and MCP servers
+type Feature struct {
+	service *Service
+}
+
+var (
+	_ features.Feature  = (*Feature)(nil)
+	_ features.Exporter = (*Feature)(nil)
+)
+
+// NewFeature creates the shakespert feature. Its database is opened by Open.
+func NewFeature() *Feature {
+	return &Feature{}
+}
+
+func (f *Feature) Name() string {
+	return "shakespert"
+}
+
+func (f *Feature) Help() features.Help {
+	return features.Help{
+		Summary: "Shakespeare's complete works",
+		Endpoints: []features.Endpoint{
+			{
+				Method:      http.MethodGet,
+				Path:        "/shakespert/works",
+				Description: "List all Shakespeare works",
+				Parameters:  "?format=json|text|ascii&genre=<type>",
+			},
+			{
+				Method:      http.MethodGet,
+				Path:        "/shakespert/works/{id}",
+				Description: "Get specific work details",
+				Parameters:  "?format=json|text|ascii",
+			},
+			{
+				Method:      http.MethodGet,
+				Path:        "/shakespert/genres",
+				Description: "List all genres",
+				Parameters:  "?format=json|text|ascii",
+			},
+		},
+		Examples: []string{"shakespert works", "shakespert work hamlet"},
+	}
+}
+
+// Export returns the feature's service, or nil before Open
+func (f *Feature) Export() any {
+	if f.service == nil {
+		return nil
+	}
+	return f.service
+}
+
+func (f *Feature) Open(ctx context.Context) error {
+	service, err := NewService(ctx)
+	if err != nil {
+		return err
+	}
+	f.service = service
+	return nil
+}
+
+func (f *Feature) Close() error {
+	if f.service == nil {
+		return nil
+	}
+	err := f.service.Close()
+	f.service = nil
+	return err
+}
+
+func (f *Feature) Check(ctx context.Context) error {
+	if f.service == nil {
+		return errors.New("database is not open")
+	}
+	return f.service.Ping(ctx)
+}
+
+func (f *Feature) RegisterRoutes(r chi.Router, opts features.RouteOptions) {
+	// Shakespeare data is immutable for the lifetime of the binary
+	immutable := middleware.Cache(middleware.CachePolicy{
+		CacheControl: middleware.CacheImmutable,
+		Version:      opts.DataVersion,
+		LastModified: opts.DataModTime,
+	})
+
+	r.Route("/shakespert", func(r chi.Router) {
+		r.Use(middleware.RequireScope(auth.ScopeReadShakespert), immutable)
+		r.Get("/works", WorksHandler(f.service))
+		r.Get("/works/{workID}", WorkHandler(f.service))
+		r.Get("/genres", GenresHandler(f.service))
+	})
+}
+
+func (f *Feature) MCPPrompts() []features.MCPPrompt {
+	return nil
+}
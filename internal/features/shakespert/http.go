@@ -1,25 +1,29 @@
-package handlers
+package shakespert
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
-	"prospero/internal/features/shakespert"
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/web/handlers"
 )
 
-// shakespertService interface for dependency injection
-type shakespertService interface {
-	ListWorks(ctx context.Context) ([]shakespert.WorkSummary, error)
-	GetWork(ctx context.Context, workID string) (*shakespert.WorkDetail, error)
-	ListGenres(ctx context.Context) ([]shakespert.Genre, error)
-	GetWorksByGenre(ctx context.Context, genreType string) ([]shakespert.WorkSummary, error)
+// catalog is the subset of *Service the handlers need, for dependency injection
+type catalog interface {
+	ListWorks(ctx context.Context) ([]WorkSummary, error)
+	GetWork(ctx context.Context, workID string) (*WorkDetail, error)
+	ListGenres(ctx context.Context) ([]Genre, error)
+	GetWorksByGenre(ctx context.Context, genreType string) ([]WorkSummary, error)
 }
 
-// ShakespertWorks handles the /api/shakespert/works endpoint
-func ShakespertWorks(service shakespertService) http.HandlerFunc {
+// WorksHandler handles the /api/v1/shakespert/works endpoint
+func WorksHandler(service catalog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -30,20 +34,22 @@ func ShakespertWorks(service shakespertService) http.HandlerFunc {
 			format = "json"
 		}
 
-		var works []shakespert.WorkSummary
+		var works []WorkSummary
 		var err error
 
 		// Get works by genre or all works
 		if genre != "" {
 			works, err = service.GetWorksByGenre(ctx, genre)
 			if err != nil {
-				http.Error(w, fmt.Sprintf("Failed to get works by genre: %v", err), http.StatusInternalServerError)
+				slog.ErrorContext(ctx, "failed to get works by genre", "genre", genre, "error", err)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to get works by genre")
 				return
 			}
 		} else {
 			works, err = service.ListWorks(ctx)
 			if err != nil {
-				http.Error(w, fmt.Sprintf("Failed to list works: %v", err), http.StatusInternalServerError)
+				slog.ErrorContext(ctx, "failed to list works", "error", err)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to list works")
 				return
 			}
 		}
@@ -59,28 +65,27 @@ func ShakespertWorks(service shakespertService) http.HandlerFunc {
 				"works": works,
 				"count": len(works),
 			}); err != nil {
-				http.Error(w, fmt.Sprintf("Failed to encode JSON: %v", err), http.StatusInternalServerError)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to encode JSON")
 				return
 			}
 		default:
-			http.Error(w, "Invalid format parameter. Use 'json' or 'text'", http.StatusBadRequest)
+			handlers.WriteProblem(w, r, http.StatusBadRequest, handlers.CodeInvalidFormat, "Invalid format parameter. Use 'json' or 'text'")
 			return
 		}
 	}
 }
 
-// ShakespertWork handles the /api/shakespert/works/{workID} endpoint
-func ShakespertWork(service shakespertService) http.HandlerFunc {
+// WorkHandler handles the /api/v1/shakespert/works/{workID} endpoint.
+// The route must be registered with a {workID} URL parameter.
+func WorkHandler(service catalog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		// Extract workID from URL path
-		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-		if len(parts) < 4 {
-			http.Error(w, "Work ID is required", http.StatusBadRequest)
+		workID := chi.URLParam(r, "workID")
+		if workID == "" {
+			handlers.WriteProblem(w, r, http.StatusBadRequest, handlers.CodeMissingWorkID, "Work ID is required")
 			return
 		}
-		workID := parts[3] // /api/shakespert/works/{workID}
 
 		format := r.URL.Query().Get("format")
 		if format == "" {
@@ -89,10 +94,11 @@ func ShakespertWork(service shakespertService) http.HandlerFunc {
 
 		work, err := service.GetWork(ctx, workID)
 		if err != nil {
-			if strings.Contains(err.Error(), "not found") {
-				http.Error(w, fmt.Sprintf("Work not found: %s", workID), http.StatusNotFound)
+			if errors.Is(err, ErrWorkNotFound) {
+				handlers.WriteProblem(w, r, http.StatusNotFound, handlers.CodeWorkNotFound, fmt.Sprintf("Work not found: %s", workID))
 			} else {
-				http.Error(w, fmt.Sprintf("Failed to get work: %v", err), http.StatusInternalServerError)
+				slog.ErrorContext(ctx, "failed to get work", "work_id", workID, "error", err)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to get work")
 			}
 			return
 		}
@@ -105,18 +111,18 @@ func ShakespertWork(service shakespertService) http.HandlerFunc {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			if err := json.NewEncoder(w).Encode(work); err != nil {
-				http.Error(w, fmt.Sprintf("Failed to encode JSON: %v", err), http.StatusInternalServerError)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to encode JSON")
 				return
 			}
 		default:
-			http.Error(w, "Invalid format parameter. Use 'json' or 'text'", http.StatusBadRequest)
+			handlers.WriteProblem(w, r, http.StatusBadRequest, handlers.CodeInvalidFormat, "Invalid format parameter. Use 'json' or 'text'")
 			return
 		}
 	}
 }
 
-// ShakespertGenres handles the /api/shakespert/genres endpoint
-func ShakespertGenres(service shakespertService) http.HandlerFunc {
+// GenresHandler handles the /api/v1/shakespert/genres endpoint
+func GenresHandler(service catalog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -127,7 +133,8 @@ func ShakespertGenres(service shakespertService) http.HandlerFunc {
 
 		genres, err := service.ListGenres(ctx)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to list genres: %v", err), http.StatusInternalServerError)
+			slog.ErrorContext(ctx, "failed to list genres", "error", err)
+			handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to list genres")
 			return
 		}
 
@@ -142,18 +149,18 @@ func ShakespertGenres(service shakespertService) http.HandlerFunc {
 				"genres": genres,
 				"count":  len(genres),
 			}); err != nil {
-				http.Error(w, fmt.Sprintf("Failed to encode JSON: %v", err), http.StatusInternalServerError)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to encode JSON")
 				return
 			}
 		default:
-			http.Error(w, "Invalid format parameter. Use 'json' or 'text'", http.StatusBadRequest)
+			handlers.WriteProblem(w, r, http.StatusBadRequest, handlers.CodeInvalidFormat, "Invalid format parameter. Use 'json' or 'text'")
 			return
 		}
 	}
 }
 
 // writeWorksAsText formats works as plain text
-func writeWorksAsText(w http.ResponseWriter, works []shakespert.WorkSummary) {
+func writeWorksAsText(w http.ResponseWriter, works []WorkSummary) {
 	fmt.Fprintf(w, "Shakespeare's Complete Works (%d works)\n", len(works))
 	fmt.Fprintf(w, "%s\n", strings.Repeat("=", 50))
 	fmt.Fprintf(w, "\n")
@@ -181,7 +188,7 @@ func writeWorksAsText(w http.ResponseWriter, works []shakespert.WorkSummary) {
 }
 
 // writeWorkAsText formats a single work as plain text
-func writeWorkAsText(w http.ResponseWriter, work *shakespert.WorkDetail) {
+func writeWorkAsText(w http.ResponseWriter, work *WorkDetail) {
 	fmt.Fprintf(w, "%s\n", work.Title)
 	fmt.Fprintf(w, "%s\n", strings.Repeat("=", len(work.Title)))
 	fmt.Fprintf(w, "\n")
@@ -214,7 +221,7 @@ func writeWorkAsText(w http.ResponseWriter, work *shakespert.WorkDetail) {
 }
 
 // writeGenresAsText formats genres as plain text
-func writeGenresAsText(w http.ResponseWriter, genres []shakespert.Genre) {
+func writeGenresAsText(w http.ResponseWriter, genres []Genre) {
 	fmt.Fprintf(w, "Shakespeare Genres\n")
 	fmt.Fprintf(w, "%s\n", strings.Repeat("=", 17))
 	fmt.Fprintf(w, "\n")
@@ -1,14 +1,16 @@
-package handlers_test
+package shakespert_test
 
 import (
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -81,10 +83,10 @@ func TestShakespertWorks(t *testing.T) {
 
 	t.Run("should list all works in JSON format", func(t *testing.T) {
 		service := &mockShakespertService{works: sampleWorks}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works?format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -101,10 +103,10 @@ func TestShakespertWorks(t *testing.T) {
 
 	t.Run("should list all works in text format", func(t *testing.T) {
 		service := &mockShakespertService{works: sampleWorks}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works?format=text", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works?format=text", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -119,10 +121,10 @@ func TestShakespertWorks(t *testing.T) {
 	t.Run("should filter works by genre", func(t *testing.T) {
 		filteredWorks := []shakespert.WorkSummary{sampleWorks[0]}
 		service := &mockShakespertService{byGenreWorks: filteredWorks}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works?genre=t&format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works?genre=t&format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -136,22 +138,23 @@ func TestShakespertWorks(t *testing.T) {
 
 	t.Run("should return error when service fails", func(t *testing.T) {
 		service := &mockShakespertService{listErr: errors.New("database error")}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, w.Body.String(), "Failed to list works")
+		assert.NotContains(t, w.Body.String(), "database error")
 	})
 
 	t.Run("should return error when genre filter fails", func(t *testing.T) {
 		service := &mockShakespertService{byGenreErr: errors.New("database error")}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works?genre=t", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works?genre=t", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -160,14 +163,16 @@ func TestShakespertWorks(t *testing.T) {
 
 	t.Run("should return error for invalid format", func(t *testing.T) {
 		service := &mockShakespertService{works: sampleWorks}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works?format=invalid", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works?format=invalid", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWorks(service)
+		handler := shakespert.WorksHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
 		assert.Contains(t, w.Body.String(), "Invalid format parameter")
+		assert.Contains(t, w.Body.String(), handlers.CodeInvalidFormat)
 	})
 }
 
@@ -188,11 +193,12 @@ func TestShakespertWork(t *testing.T) {
 
 	t.Run("should get specific work in JSON format", func(t *testing.T) {
 		service := &mockShakespertService{work: sampleWork}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/hamlet?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/hamlet?format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
-		handler.ServeHTTP(w, req)
+		router := chi.NewRouter()
+		router.Get("/api/v1/shakespert/works/{workID}", shakespert.WorkHandler(service))
+		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
@@ -208,11 +214,12 @@ func TestShakespertWork(t *testing.T) {
 
 	t.Run("should get specific work in text format", func(t *testing.T) {
 		service := &mockShakespertService{work: sampleWork}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/hamlet?format=text", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/hamlet?format=text", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
-		handler.ServeHTTP(w, req)
+		router := chi.NewRouter()
+		router.Get("/api/v1/shakespert/works/{workID}", shakespert.WorkHandler(service))
+		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
@@ -224,24 +231,34 @@ func TestShakespertWork(t *testing.T) {
 	})
 
 	t.Run("should return 404 when work not found", func(t *testing.T) {
-		service := &mockShakespertService{getErr: errors.New("work not found: unknown")}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/unknown", nil)
+		service := &mockShakespertService{getErr: fmt.Errorf("%w: unknown", shakespert.ErrWorkNotFound)}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/unknown", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
-		handler.ServeHTTP(w, req)
+		router := chi.NewRouter()
+		router.Get("/api/v1/shakespert/works/{workID}", shakespert.WorkHandler(service))
+		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Contains(t, w.Body.String(), "Work not found")
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem handlers.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, handlers.CodeWorkNotFound, problem.Code)
+		assert.Contains(t, problem.Detail, "Work not found")
 	})
 
 	t.Run("should return 500 when service fails", func(t *testing.T) {
-		service := &mockShakespertService{getErr: errors.New("database error")}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/hamlet", nil)
+		service := &mockShakespertService{getErr: errors.New("work not found in cache")}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/hamlet", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
-		handler.ServeHTTP(w, req)
+		router := chi.NewRouter()
+		router.Get("/api/v1/shakespert/works/{workID}", shakespert.WorkHandler(service))
+		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, w.Body.String(), "Failed to get work")
@@ -249,10 +266,10 @@ func TestShakespertWork(t *testing.T) {
 
 	t.Run("should return error when work ID is missing", func(t *testing.T) {
 		service := &mockShakespertService{work: sampleWork}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
+		handler := shakespert.WorkHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -261,11 +278,12 @@ func TestShakespertWork(t *testing.T) {
 
 	t.Run("should return error for invalid format", func(t *testing.T) {
 		service := &mockShakespertService{work: sampleWork}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/hamlet?format=invalid", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/hamlet?format=invalid", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertWork(service)
-		handler.ServeHTTP(w, req)
+		router := chi.NewRouter()
+		router.Get("/api/v1/shakespert/works/{workID}", shakespert.WorkHandler(service))
+		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "Invalid format parameter")
@@ -286,10 +304,10 @@ func TestShakespertGenres(t *testing.T) {
 
 	t.Run("should list genres in JSON format", func(t *testing.T) {
 		service := &mockShakespertService{genres: sampleGenres}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/genres?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/genres?format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertGenres(service)
+		handler := shakespert.GenresHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -306,10 +324,10 @@ func TestShakespertGenres(t *testing.T) {
 
 	t.Run("should list genres in text format", func(t *testing.T) {
 		service := &mockShakespertService{genres: sampleGenres}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/genres?format=text", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/genres?format=text", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertGenres(service)
+		handler := shakespert.GenresHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -322,10 +340,10 @@ func TestShakespertGenres(t *testing.T) {
 
 	t.Run("should return error when service fails", func(t *testing.T) {
 		service := &mockShakespertService{genresErr: errors.New("database error")}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/genres", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/genres", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertGenres(service)
+		handler := shakespert.GenresHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -334,10 +352,10 @@ func TestShakespertGenres(t *testing.T) {
 
 	t.Run("should return error for invalid format", func(t *testing.T) {
 		service := &mockShakespertService{genres: sampleGenres}
-		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/genres?format=invalid", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/genres?format=invalid", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.ShakespertGenres(service)
+		handler := shakespert.GenresHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
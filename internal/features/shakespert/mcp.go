@@ -0,0 +1,100 @@
+package shakespert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"prospero/internal/features"
+	"prospero/internal/mcp"
+)
+
+func (f *Feature) MCPTools() []features.MCPTool {
+	return []features.MCPTool{
+		{
+			Tool: mcp.Tool{
+				Name:        "shakespert_list_works",
+				Description: "List Shakespeare's works, optionally filtered by genre",
+				InputSchema: mcp.InputSchema{
+					Properties: map[string]mcp.SchemaProperty{
+						"genre": {Type: "string", Description: "Genre code: c=Comedy, h=History, p=Poem, s=Sonnet, t=Tragedy"},
+					},
+				},
+			},
+			Handler: f.listWorksTool,
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "shakespert_get_work",
+				Description: "Get details about a Shakespeare work by ID, such as hamlet",
+				InputSchema: mcp.InputSchema{
+					Properties: map[string]mcp.SchemaProperty{
+						"work_id": {Type: "string", Description: "Work ID as returned by shakespert_list_works"},
+					},
+					Required: []string{"work_id"},
+				},
+			},
+			Handler: f.getWorkTool,
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "shakespert_list_genres",
+				Description: "List the genres of Shakespeare's works",
+			},
+			Handler: f.listGenresTool,
+		},
+	}
+}
+
+func (f *Feature) listWorksTool(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	var works []WorkSummary
+	var err error
+	if genre := mcp.StringArg(args, "genre"); genre != "" {
+		works, err = f.service.GetWorksByGenre(ctx, genre)
+	} else {
+		works, err = f.service.ListWorks(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list works: %w", err)
+	}
+
+	return jsonResult(map[string]interface{}{
+		"works": works,
+		"count": len(works),
+	})
+}
+
+func (f *Feature) getWorkTool(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	workID := mcp.StringArg(args, "work_id")
+	if workID == "" {
+		return nil, errors.New("work_id is required")
+	}
+
+	work, err := f.service.GetWork(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(work)
+}
+
+func (f *Feature) listGenresTool(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	genres, err := f.service.ListGenres(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genres: %w", err)
+	}
+
+	return jsonResult(map[string]interface{}{
+		"genres": genres,
+		"count":  len(genres),
+	})
+}
+
+// jsonResult encodes v as the text of a tool result, matching the HTTP API
+func jsonResult(v interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return mcp.TextResult(string(data)), nil
+}
@@ -0,0 +1,43 @@
+package shakespert
+
+import (
+	"bytes"
+	"io/fs"
+	"time"
+)
+
+// memFS is a read-only filesystem holding a single in-memory file. It lets
+// the SQLite VFS read a decrypted database without writing it to disk.
+type memFS struct {
+	name string
+	data []byte
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if name != m.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{Reader: bytes.NewReader(m.data), info: memFileInfo{name: m.name, size: int64(len(m.data))}}, nil
+}
+
+// memFile is an open memFS file. The embedded bytes.Reader provides the
+// Read, ReadAt and Seek methods the VFS relies on.
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
@@ -0,0 +1,294 @@
+package shakespert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"prospero/internal/features"
+)
+
+// writeWorks writes the works listing in format. JSON matches
+// GET /api/v1/shakespert/works.
+func writeWorks(w io.Writer, format features.Format, works []WorkSummary) error {
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, map[string]interface{}{
+			"works": works,
+			"count": len(works),
+		})
+	case features.FormatMarkdown:
+		fmt.Fprintf(w, "# Shakespeare's Complete Works (%d works)\n\n", len(works))
+		features.WriteMarkdownTable(w, workHeader, workRows(works))
+		return nil
+	case features.FormatCSV:
+		return features.WriteCSV(w, workHeader, workRows(works))
+	}
+
+	if len(works) == 0 {
+		fmt.Fprintln(w, "No works found.")
+		return nil
+	}
+
+	// Create tabwriter for formatted output
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "ID\tTITLE\tGENRE\tYEAR\tWORDS\tPARAGRAPHS\n")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		strings.Repeat("-", 15),
+		strings.Repeat("-", 30),
+		strings.Repeat("-", 10),
+		strings.Repeat("-", 6),
+		strings.Repeat("-", 8),
+		strings.Repeat("-", 12))
+
+	for _, work := range works {
+		fmt.Fprintf(tw, "%s\t%s\t%s (%s)\t%s\t%d\t%d\n",
+			work.WorkID,
+			truncateString(work.Title, 30),
+			work.GenreName,
+			work.GenreType,
+			yearString(work.Date),
+			work.TotalWords,
+			work.TotalParagraphs,
+		)
+	}
+
+	return tw.Flush()
+}
+
+// workHeader names the columns of workRows
+var workHeader = []string{"id", "title", "genre", "genre_type", "year", "words", "paragraphs"}
+
+// workRows returns a table row per work
+func workRows(works []WorkSummary) [][]string {
+	rows := make([][]string, len(works))
+	for i, work := range works {
+		rows[i] = []string{
+			work.WorkID,
+			work.Title,
+			work.GenreName,
+			work.GenreType,
+			yearString(work.Date),
+			strconv.FormatInt(work.TotalWords, 10),
+			strconv.FormatInt(work.TotalParagraphs, 10),
+		}
+	}
+	return rows
+}
+
+// writeWork writes the details of a work in format. JSON matches
+// GET /api/v1/shakespert/works/{id}.
+func writeWork(w io.Writer, format features.Format, work *WorkDetail) error {
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, work)
+	case features.FormatCSV:
+		return features.WriteCSV(w,
+			[]string{"id", "title", "long_title", "genre", "genre_type", "year", "words", "paragraphs", "source"},
+			[][]string{{
+				work.WorkID,
+				work.Title,
+				work.LongTitle,
+				work.GenreName,
+				work.GenreType,
+				yearString(work.Date),
+				strconv.FormatInt(work.TotalWords, 10),
+				strconv.FormatInt(work.TotalParagraphs, 10),
+				work.Source,
+			}})
+	case features.FormatMarkdown:
+		fmt.Fprintf(w, "# %s\n\n", work.Title)
+		if work.LongTitle != work.Title && work.LongTitle != "" {
+			fmt.Fprintf(w, "- **Full Title:** %s\n", work.LongTitle)
+		}
+		fmt.Fprintf(w, "- **Work ID:** %s\n", work.WorkID)
+		fmt.Fprintf(w, "- **Genre:** %s (%s)\n", work.GenreName, work.GenreType)
+		if work.Date > 0 {
+			fmt.Fprintf(w, "- **Year:** %d\n", work.Date)
+		}
+		fmt.Fprintf(w, "- **Words:** %d\n", work.TotalWords)
+		fmt.Fprintf(w, "- **Paragraphs:** %d\n", work.TotalParagraphs)
+		if work.Source != "" {
+			fmt.Fprintf(w, "- **Source:** %s\n", work.Source)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "╭─ %s ─╮\n", strings.Repeat("─", len(work.Title)+2))
+	fmt.Fprintf(w, "│ %s │\n", work.Title)
+	fmt.Fprintf(w, "╰─%s─╯\n", strings.Repeat("─", len(work.Title)+2))
+	fmt.Fprintf(w, "\n")
+
+	if work.LongTitle != work.Title && work.LongTitle != "" {
+		fmt.Fprintf(w, "Full Title: %s\n", work.LongTitle)
+	}
+
+	if work.ShortTitle != "" {
+		fmt.Fprintf(w, "Short Title: %s\n", work.ShortTitle)
+	}
+
+	fmt.Fprintf(w, "Work ID: %s\n", work.WorkID)
+	fmt.Fprintf(w, "Genre: %s (%s)\n", work.GenreName, work.GenreType)
+
+	if work.Date > 0 {
+		fmt.Fprintf(w, "Year: %d\n", work.Date)
+	}
+
+	fmt.Fprintf(w, "Words: %d\n", work.TotalWords)
+	fmt.Fprintf(w, "Paragraphs: %d\n", work.TotalParagraphs)
+
+	if work.Source != "" {
+		fmt.Fprintf(w, "Source: %s\n", work.Source)
+	}
+
+	if work.Notes != "" && work.Notes != "null" {
+		fmt.Fprintf(w, "Notes: %s\n", work.Notes)
+	}
+
+	return nil
+}
+
+// writeGenres writes the genres in format. JSON matches
+// GET /api/v1/shakespert/genres.
+func writeGenres(w io.Writer, format features.Format, genres []Genre) error {
+	rows := make([][]string, len(genres))
+	for i, genre := range genres {
+		rows[i] = []string{genre.Genretype, genre.Genrename.String}
+	}
+	header := []string{"code", "name"}
+
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, map[string]interface{}{
+			"genres": genres,
+			"count":  len(genres),
+		})
+	case features.FormatMarkdown:
+		fmt.Fprintf(w, "# Shakespeare Genres\n\n")
+		features.WriteMarkdownTable(w, header, rows)
+		return nil
+	case features.FormatCSV:
+		return features.WriteCSV(w, header, rows)
+	}
+
+	fmt.Fprintln(w, "Available Genres:")
+	fmt.Fprintln(w, "─────────────────")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "CODE\tNAME\n")
+	fmt.Fprintf(tw, "%s\t%s\n", strings.Repeat("-", 4), strings.Repeat("-", 20))
+
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", row[0], row[1])
+	}
+
+	return tw.Flush()
+}
+
+// writeCharacters writes the characters of work in format. The HTTP API has
+// no characters endpoint, so JSON follows the shape of the works listing.
+func writeCharacters(w io.Writer, format features.Format, work *WorkDetail, characters []CharacterSummary) error {
+	rows := make([][]string, len(characters))
+	for i, character := range characters {
+		rows[i] = []string{
+			character.CharID,
+			character.Name,
+			character.Description,
+			strconv.FormatInt(character.SpeechCount, 10),
+		}
+	}
+	header := []string{"id", "name", "description", "speeches"}
+
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, map[string]interface{}{
+			"work":       work.WorkID,
+			"characters": characters,
+			"count":      len(characters),
+		})
+	case features.FormatMarkdown:
+		fmt.Fprintf(w, "# Characters in %s (%d)\n\n", work.Title, len(characters))
+		features.WriteMarkdownTable(w, header, rows)
+		return nil
+	case features.FormatCSV:
+		return features.WriteCSV(w, header, rows)
+	}
+
+	title := fmt.Sprintf("🎭 Characters in %s (%d)", work.Title, len(characters))
+	fmt.Fprintf(w, "\n%s\n", title)
+	fmt.Fprintf(w, "%s\n\n", strings.Repeat("═", 50))
+
+	for _, character := range characters {
+		fmt.Fprintf(w, "  %s - %s", character.CharID, character.Name)
+		if character.Description != "" {
+			fmt.Fprintf(w, ", %s", character.Description)
+		}
+		fmt.Fprintf(w, " (%d speeches)\n", character.SpeechCount)
+	}
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// writeCharacter writes the details of a character in format
+func writeCharacter(w io.Writer, format features.Format, character *CharacterDetail) error {
+	switch format {
+	case features.FormatJSON:
+		return features.WriteJSON(w, character)
+	case features.FormatCSV:
+		return features.WriteCSV(w,
+			[]string{"id", "name", "description", "works", "speeches"},
+			[][]string{{
+				character.CharID,
+				character.Name,
+				character.Description,
+				strings.Join(character.Works, " "),
+				strconv.FormatInt(character.SpeechCount, 10),
+			}})
+	case features.FormatMarkdown:
+		fmt.Fprintf(w, "# %s\n\n", character.Name)
+		fmt.Fprintf(w, "- **Character ID:** %s\n", character.CharID)
+		if character.Description != "" {
+			fmt.Fprintf(w, "- **Description:** %s\n", character.Description)
+		}
+		fmt.Fprintf(w, "- **Works:** %s\n", strings.Join(character.Works, ", "))
+		fmt.Fprintf(w, "- **Speeches:** %d\n", character.SpeechCount)
+		return nil
+	}
+
+	fmt.Fprintf(w, "\n🎭 %s\n", character.Name)
+	fmt.Fprintf(w, "%s\n", strings.Repeat("═", len(character.Name)+4))
+	fmt.Fprintf(w, "Character ID: %s\n", character.CharID)
+	if character.Description != "" {
+		fmt.Fprintf(w, "Description: %s\n", character.Description)
+	}
+	fmt.Fprintf(w, "Works: %s\n", strings.Join(character.Works, ", "))
+	fmt.Fprintf(w, "Speeches: %d\n", character.SpeechCount)
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// yearString formats the year of a work, empty when it is unknown
+func yearString(date int64) string {
+	if date <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(date, 10)
+}
+
+// workError reports a failure to get a work, telling missing works apart
+func workError(workID string, err error) error {
+	if errors.Is(err, ErrWorkNotFound) {
+		return features.NotFoundError("Work not found: %s", workID)
+	}
+	return features.InternalError("Error getting work", err)
+}
+
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -32,4 +32,30 @@ ORDER BY c.CharName;
 SELECT ChapterID, Section, Chapter, Description
 FROM Chapters
 WHERE WorkID = ?
-ORDER BY Section, Chapter;
\ No newline at end of file
+ORDER BY Section, Chapter;
+
+-- name: GetSceneParagraphs :many
+SELECT p.ParagraphID, p.ParagraphNum, p.CharID, c.CharName, p.PlainText, p.ParagraphType
+FROM Paragraphs p
+LEFT JOIN Characters c ON p.CharID = c.CharID
+WHERE p.WorkID = ? AND p.Section = ? AND p.Chapter = ?
+ORDER BY p.ParagraphNum;
+
+-- name: SearchParagraphs :many
+SELECT p.WorkID, w.Title, p.ParagraphNum, p.Section, p.Chapter, c.CharName, p.PlainText
+FROM Paragraphs p
+JOIN Works w ON p.WorkID = w.WorkID
+LEFT JOIN Characters c ON p.CharID = c.CharID
+WHERE p.PlainText LIKE '%' || sqlc.arg(query) || '%'
+ORDER BY w.Title, p.ParagraphNum
+LIMIT sqlc.arg(max_results);
+
+-- name: GetCharacter :one
+SELECT CharID, CharName, Abbrev, Works, Description, SpeechCount
+FROM Characters
+WHERE CharID = ?;
+
+-- name: ListCharacterIDs :many
+SELECT CharID
+FROM Characters
+ORDER BY CharID;
@@ -10,6 +10,79 @@ import (
 	"database/sql"
 )
 
+const getCharacter = `-- name: GetCharacter :one
+SELECT CharID, CharName, Abbrev, Works, Description, SpeechCount
+FROM Characters
+WHERE CharID = ?
+`
+
+func (q *Queries) GetCharacter(ctx context.Context, charid string) (Character, error) {
+	row := q.db.QueryRowContext(ctx, getCharacter, charid)
+	var i Character
+	err := row.Scan(
+		&i.Charid,
+		&i.Charname,
+		&i.Abbrev,
+		&i.Works,
+		&i.Description,
+		&i.Speechcount,
+	)
+	return i, err
+}
+
+const getSceneParagraphs = `-- name: GetSceneParagraphs :many
+SELECT p.ParagraphID, p.ParagraphNum, p.CharID, c.CharName, p.PlainText, p.ParagraphType
+FROM Paragraphs p
+LEFT JOIN Characters c ON p.CharID = c.CharID
+WHERE p.WorkID = ? AND p.Section = ? AND p.Chapter = ?
+ORDER BY p.ParagraphNum
+`
+
+type GetSceneParagraphsParams struct {
+	Workid  sql.NullString
+	Section sql.NullInt64
+	Chapter sql.NullInt64
+}
+
+type GetSceneParagraphsRow struct {
+	Paragraphid   int64
+	Paragraphnum  sql.NullInt64
+	Charid        sql.NullString
+	Charname      sql.NullString
+	Plaintext     sql.NullString
+	Paragraphtype interface{}
+}
+
+func (q *Queries) GetSceneParagraphs(ctx context.Context, arg GetSceneParagraphsParams) ([]GetSceneParagraphsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSceneParagraphs, arg.Workid, arg.Section, arg.Chapter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSceneParagraphsRow
+	for rows.Next() {
+		var i GetSceneParagraphsRow
+		if err := rows.Scan(
+			&i.Paragraphid,
+			&i.Paragraphnum,
+			&i.Charid,
+			&i.Charname,
+			&i.Plaintext,
+			&i.Paragraphtype,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWork = `-- name: GetWork :one
 SELECT w.WorkID, w.Title, w.LongTitle, w.ShortTitle, w.Date, w.GenreType, g.GenreName, w.Notes, w.Source, w.TotalWords, w.TotalParagraphs
 FROM Works w
@@ -185,6 +258,35 @@ func (q *Queries) GetWorksByGenre(ctx context.Context, genretype sql.NullString)
 	return items, nil
 }
 
+const listCharacterIDs = `-- name: ListCharacterIDs :many
+SELECT CharID
+FROM Characters
+ORDER BY CharID
+`
+
+func (q *Queries) ListCharacterIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var charid string
+		if err := rows.Scan(&charid); err != nil {
+			return nil, err
+		}
+		items = append(items, charid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listGenres = `-- name: ListGenres :many
 SELECT GenreType, GenreName
 FROM Genres
@@ -263,3 +365,59 @@ func (q *Queries) ListWorks(ctx context.Context) ([]ListWorksRow, error) {
 	}
 	return items, nil
 }
+
+const searchParagraphs = `-- name: SearchParagraphs :many
+SELECT p.WorkID, w.Title, p.ParagraphNum, p.Section, p.Chapter, c.CharName, p.PlainText
+FROM Paragraphs p
+JOIN Works w ON p.WorkID = w.WorkID
+LEFT JOIN Characters c ON p.CharID = c.CharID
+WHERE p.PlainText LIKE '%' || ?1 || '%'
+ORDER BY w.Title, p.ParagraphNum
+LIMIT ?2
+`
+
+type SearchParagraphsParams struct {
+	Query      sql.NullString
+	MaxResults int64
+}
+
+type SearchParagraphsRow struct {
+	Workid       sql.NullString
+	Title        sql.NullString
+	Paragraphnum sql.NullInt64
+	Section      sql.NullInt64
+	Chapter      sql.NullInt64
+	Charname     sql.NullString
+	Plaintext    sql.NullString
+}
+
+func (q *Queries) SearchParagraphs(ctx context.Context, arg SearchParagraphsParams) ([]SearchParagraphsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchParagraphs, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchParagraphsRow
+	for rows.Next() {
+		var i SearchParagraphsRow
+		if err := rows.Scan(
+			&i.Workid,
+			&i.Title,
+			&i.Paragraphnum,
+			&i.Section,
+			&i.Chapter,
+			&i.Charname,
+			&i.Plaintext,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
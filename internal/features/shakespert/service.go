@@ -3,19 +3,27 @@ package shakespert
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"prospero/assets"
+	"prospero/internal/metrics"
+	"prospero/internal/shared"
 
 	_ "modernc.org/sqlite"
+	"modernc.org/sqlite/vfs"
 )
 
 type Service struct {
-	db           *sql.DB
-	queries      *Queries
-	tempFilePath string
+	db      *sql.DB
+	queries *Queries
+	vfs     *vfs.FS // nil when reading an on-disk database
 }
 
 // WorkSummary represents a simplified view of a work for listings
@@ -45,70 +53,156 @@ type WorkDetail struct {
 	TotalParagraphs int64
 }
 
-// NewService creates a new shakespert service with a temporary SQLite database file
+// CharacterSummary represents a character of a work for listings
+type CharacterSummary struct {
+	CharID      string
+	Name        string
+	Description string
+	SpeechCount int64
+}
+
+// CharacterDetail represents detailed information about a character
+type CharacterDetail struct {
+	CharID      string
+	Name        string
+	Abbrev      string
+	Works       []string
+	Description string
+	SpeechCount int64
+}
+
+// Scene is an act and scene of a work. Poems and sonnets are a single act
+// whose scenes are the individual poems.
+type Scene struct {
+	Act         int64
+	Scene       int64
+	Description string
+}
+
+// Passage is a paragraph of a scene: a speech or a stage direction
+type Passage struct {
+	Number      int64
+	CharacterID string
+	Character   string
+	Text        string
+}
+
+// StageDirection reports whether the passage is a stage direction rather
+// than spoken by a character
+func (p Passage) StageDirection() bool {
+	return p.CharacterID == stageDirectionsID
+}
+
+// SearchResult is a passage matching a search, with the scene it is in
+type SearchResult struct {
+	WorkID    string
+	Title     string
+	Act       int64
+	Scene     int64
+	Number    int64
+	Character string
+	Text      string
+}
+
+// stageDirectionsID is the character ID the database attributes stage
+// directions to
+const stageDirectionsID = "xxx"
+
+// NewService creates a new shakespert service from the shakespert.db asset.
+// The embedded database is read in place through a read-only SQLite VFS, so
+// nothing is written to disk. A plain database in the data directory is
+// opened directly, and an encrypted one is decrypted into memory.
 func NewService(ctx context.Context) (*Service, error) {
-	// Get embedded database data
-	dbData := assets.GetEmbeddedShakespertDB()
+	asset, err := assets.Resolve(assets.ShakespertAsset)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a temporary file for the database
-	tempFile, err := os.CreateTemp("", "shakespert-*.db")
+	switch {
+	case asset.Embedded():
+		return newVFSService(ctx, assets.GetEmbeddedShakespertFS(), assets.ShakespertDBPath)
+	case !asset.Encrypted:
+		return NewServiceFromFile(ctx, asset.Path)
+	}
+
+	data, err := shared.ReadAsset(assets.ShakespertAsset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		return nil, err
 	}
-	tempFilePath := tempFile.Name()
+	return newVFSService(ctx, memFS{name: assets.ShakespertAsset, data: data}, assets.ShakespertAsset)
+}
 
-	// Write the database data to the temporary file
-	if _, err := tempFile.Write(dbData); err != nil {
-		tempFile.Close()
-		os.Remove(tempFilePath)
-		return nil, fmt.Errorf("failed to write database to temporary file: %w", err)
+// newVFSService opens the database at name within fsys through a read-only
+// SQLite VFS
+func newVFSService(ctx context.Context, fsys fs.FS, name string) (*Service, error) {
+	vfsName, dbFS, err := vfs.New(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register database VFS: %w", err)
 	}
-	tempFile.Close()
 
-	// Open the SQLite database in read-only mode
-	dbPath := filepath.ToSlash(tempFilePath) + "?mode=ro"
-	db, err := sql.Open("sqlite", dbPath)
+	service, err := open(ctx, "file:"+name+"?vfs="+vfsName+"&mode=ro")
+	if err != nil {
+		dbFS.Close()
+		return nil, err
+	}
+	service.vfs = dbFS
+
+	return service, nil
+}
+
+// NewServiceFromFile creates a new shakespert service backed by the SQLite
+// database at path, opened read-only
+func NewServiceFromFile(ctx context.Context, path string) (*Service, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return open(ctx, "file:"+filepath.ToSlash(path)+"?mode=ro")
+}
+
+// open connects to the database described by dsn and verifies the connection
+func open(ctx context.Context, dsn string) (*Service, error) {
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
-		os.Remove(tempFilePath)
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Test the connection
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		os.Remove(tempFilePath)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Create queries instance
-	queries := New(db)
-
 	return &Service{
-		db:           db,
-		queries:      queries,
-		tempFilePath: tempFilePath,
+		db:      db,
+		queries: New(db),
 	}, nil
 }
 
-// Close closes the database connection and cleans up the temporary file
+// Close closes the database connection and unregisters the VFS, if any
 func (s *Service) Close() error {
 	if err := s.db.Close(); err != nil {
 		return fmt.Errorf("failed to close database: %w", err)
 	}
 
-	// Clean up the temporary file
-	if s.tempFilePath != "" {
-		if err := os.Remove(s.tempFilePath); err != nil {
-			// Log the error but don't fail the close operation
-			fmt.Printf("Warning: failed to remove temporary database file %s: %v\n", s.tempFilePath, err)
+	if s.vfs != nil {
+		if err := s.vfs.Close(); err != nil {
+			return fmt.Errorf("failed to close database VFS: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// Ping verifies the database connection is usable
+func (s *Service) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 // ListWorks returns a list of all Shakespeare works
 func (s *Service) ListWorks(ctx context.Context) ([]WorkSummary, error) {
+	defer metrics.ObserveShakespertQuery("ListWorks", time.Now())
+
 	rows, err := s.queries.ListWorks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list works: %w", err)
@@ -133,10 +227,12 @@ func (s *Service) ListWorks(ctx context.Context) ([]WorkSummary, error) {
 
 // GetWork returns detailed information about a specific work
 func (s *Service) GetWork(ctx context.Context, workID string) (*WorkDetail, error) {
+	defer metrics.ObserveShakespertQuery("GetWork", time.Now())
+
 	row, err := s.queries.GetWork(ctx, workID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("work not found: %s", workID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrWorkNotFound, workID)
 		}
 		return nil, fmt.Errorf("failed to get work: %w", err)
 	}
@@ -158,11 +254,15 @@ func (s *Service) GetWork(ctx context.Context, workID string) (*WorkDetail, erro
 
 // ListGenres returns all available genres
 func (s *Service) ListGenres(ctx context.Context) ([]Genre, error) {
+	defer metrics.ObserveShakespertQuery("ListGenres", time.Now())
+
 	return s.queries.ListGenres(ctx)
 }
 
 // GetWorksByGenre returns works filtered by genre
 func (s *Service) GetWorksByGenre(ctx context.Context, genreType string) ([]WorkSummary, error) {
+	defer metrics.ObserveShakespertQuery("GetWorksByGenre", time.Now())
+
 	rows, err := s.queries.GetWorksByGenre(ctx, sql.NullString{String: genreType, Valid: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get works by genre: %w", err)
@@ -185,6 +285,153 @@ func (s *Service) GetWorksByGenre(ctx context.Context, genreType string) ([]Work
 	return works, nil
 }
 
+// ListCharacters returns the characters appearing in a work, without the
+// stage directions
+func (s *Service) ListCharacters(ctx context.Context, workID string) ([]CharacterSummary, error) {
+	defer metrics.ObserveShakespertQuery("ListCharacters", time.Now())
+
+	rows, err := s.queries.GetWorkCharacters(ctx, sql.NullString{String: workID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	characters := make([]CharacterSummary, 0, len(rows))
+	for _, row := range rows {
+		if row.Charid == stageDirectionsID {
+			continue
+		}
+		characters = append(characters, CharacterSummary{
+			CharID:      row.Charid,
+			Name:        nullStringToString(row.Charname),
+			Description: nullStringToString(row.Description),
+			SpeechCount: nullInt64ToInt64(row.Speechcount),
+		})
+	}
+
+	return characters, nil
+}
+
+// GetCharacter returns detailed information about a character
+func (s *Service) GetCharacter(ctx context.Context, charID string) (*CharacterDetail, error) {
+	defer metrics.ObserveShakespertQuery("GetCharacter", time.Now())
+
+	row, err := s.queries.GetCharacter(ctx, charID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrCharacterNotFound, charID)
+		}
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+
+	var works []string
+	if row.Works.Valid && row.Works.String != "" {
+		works = strings.Split(row.Works.String, ",")
+	}
+
+	return &CharacterDetail{
+		CharID:      row.Charid,
+		Name:        nullStringToString(row.Charname),
+		Abbrev:      nullStringToString(row.Abbrev),
+		Works:       works,
+		Description: nullStringToString(row.Description),
+		SpeechCount: nullInt64ToInt64(row.Speechcount),
+	}, nil
+}
+
+// ListCharacterIDs returns the ID of every character, without the stage
+// directions
+func (s *Service) ListCharacterIDs(ctx context.Context) ([]string, error) {
+	defer metrics.ObserveShakespertQuery("ListCharacterIDs", time.Now())
+
+	ids, err := s.queries.ListCharacterIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list character IDs: %w", err)
+	}
+
+	return slices.DeleteFunc(ids, func(id string) bool { return id == stageDirectionsID }), nil
+}
+
+// ListScenes returns the acts and scenes of a work in order
+func (s *Service) ListScenes(ctx context.Context, workID string) ([]Scene, error) {
+	defer metrics.ObserveShakespertQuery("ListScenes", time.Now())
+
+	rows, err := s.queries.GetWorkChapters(ctx, sql.NullString{String: workID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenes: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrWorkNotFound, workID)
+	}
+
+	scenes := make([]Scene, len(rows))
+	for i, row := range rows {
+		scenes[i] = Scene{
+			Act:         nullInt64ToInt64(row.Section),
+			Scene:       nullInt64ToInt64(row.Chapter),
+			Description: nullStringToString(row.Description),
+		}
+	}
+
+	return scenes, nil
+}
+
+// GetScene returns the passages of a scene in order
+func (s *Service) GetScene(ctx context.Context, workID string, act, scene int64) ([]Passage, error) {
+	defer metrics.ObserveShakespertQuery("GetScene", time.Now())
+
+	rows, err := s.queries.GetSceneParagraphs(ctx, GetSceneParagraphsParams{
+		Workid:  sql.NullString{String: workID, Valid: true},
+		Section: sql.NullInt64{Int64: act, Valid: true},
+		Chapter: sql.NullInt64{Int64: scene, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scene: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s act %d scene %d", ErrSceneNotFound, workID, act, scene)
+	}
+
+	passages := make([]Passage, len(rows))
+	for i, row := range rows {
+		passages[i] = Passage{
+			Number:      nullInt64ToInt64(row.Paragraphnum),
+			CharacterID: nullStringToString(row.Charid),
+			Character:   nullStringToString(row.Charname),
+			Text:        nullStringToString(row.Plaintext),
+		}
+	}
+
+	return passages, nil
+}
+
+// Search returns up to limit passages containing query, ignoring case
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	defer metrics.ObserveShakespertQuery("Search", time.Now())
+
+	rows, err := s.queries.SearchParagraphs(ctx, SearchParagraphsParams{
+		Query:      sql.NullString{String: query, Valid: true},
+		MaxResults: int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = SearchResult{
+			WorkID:    nullStringToString(row.Workid),
+			Title:     nullStringToString(row.Title),
+			Act:       nullInt64ToInt64(row.Section),
+			Scene:     nullInt64ToInt64(row.Chapter),
+			Number:    nullInt64ToInt64(row.Paragraphnum),
+			Character: nullStringToString(row.Charname),
+			Text:      nullStringToString(row.Plaintext),
+		}
+	}
+
+	return results, nil
+}
+
 // Helper functions to handle sql.Null types
 func nullStringToString(ns sql.NullString) string {
 	if ns.Valid {
@@ -0,0 +1,72 @@
+package topten
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/urfave/cli/v2"
+
+	"prospero/internal/features"
+)
+
+func (f *Feature) Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:        "topten",
+			Usage:       "Display a random David Letterman Top 10 list",
+			Description: `Display a random David Letterman Top 10 list with colorful formatting.`,
+			Flags:       append(features.ColorFlags(), features.FormatFlags()...),
+			Action:      f.showRandomList,
+		},
+	}
+}
+
+func (f *Feature) showRandomList(c *cli.Context) error {
+	format, err := features.OutputFormat(c)
+	if err != nil {
+		return err
+	}
+
+	if f.service == nil {
+		if err := f.Open(c.Context); err != nil {
+			return features.InternalError("Failed to initialize service", err)
+		}
+		defer f.Close()
+	}
+
+	list, err := f.service.GetRandomList()
+	if errors.Is(err, ErrNoLists) {
+		return features.NotFoundError("No Top Ten lists available")
+	}
+	if err != nil {
+		return features.InternalError("Error getting random list", err)
+	}
+
+	w := c.App.Writer
+	switch format {
+	case features.FormatJSON:
+		// Matches GET /api/v1/topten
+		return features.WriteJSON(w, list)
+	case features.FormatMarkdown:
+		writeListMarkdown(w, list)
+		return nil
+	case features.FormatCSV:
+		items := list.NumberedItems()
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{item.Number, item.Text}
+		}
+		return features.WriteCSV(w, []string{"number", "item"}, rows)
+	}
+
+	// Print the list using the appropriate formatting
+	if features.UseColor(c) {
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		PrintList(w, list)
+	} else {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		PrintListASCII(w, list)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package topten
+
+import "errors"
+
+// Errors of the Top Ten list lookups
+var (
+	// ErrNoLists is returned when the collection contains no lists
+	ErrNoLists = errors.New("no top ten lists available")
+
+	// ErrListNotFound is returned for a list index outside the collection
+	ErrListNotFound = errors.New("top ten list not found")
+)
@@ -0,0 +1,85 @@
+package topten
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/auth"
+	"prospero/internal/features"
+	"prospero/internal/web/middleware"
+)
+
+// Feature plugs the Top Ten lists into the CLI, HTTP, SSH and MCP servers
+type Feature struct {
+	service *Service
+}
+
+var (
+	_ features.Feature  = (*Feature)(nil)
+	_ features.Exporter = (*Feature)(nil)
+)
+
+// NewFeature creates the topten feature. Its service is created by Open.
+func NewFeature() *Feature {
+	return &Feature{}
+}
+
+func (f *Feature) Name() string {
+	return "topten"
+}
+
+func (f *Feature) Help() features.Help {
+	return features.Help{
+		Summary: "Random David Letterman Top 10 lists",
+		Endpoints: []features.Endpoint{
+			{
+				Method:      http.MethodGet,
+				Path:        "/topten",
+				Description: "Get a random Dave's Top 10 list",
+				Parameters:  "?format=json|ascii",
+			},
+		},
+		Examples: []string{"topten --color"},
+	}
+}
+
+// Export returns the feature's service, or nil before Open
+func (f *Feature) Export() any {
+	if f.service == nil {
+		return nil
+	}
+	return f.service
+}
+
+func (f *Feature) Open(ctx context.Context) error {
+	service, err := NewService(ctx)
+	if err != nil {
+		return err
+	}
+	f.service = service
+	return nil
+}
+
+func (f *Feature) Close() error {
+	return nil
+}
+
+func (f *Feature) Check(ctx context.Context) error {
+	if f.service == nil || f.service.GetListCount() == 0 {
+		return ErrNoLists
+	}
+	return nil
+}
+
+func (f *Feature) RegisterRoutes(r chi.Router, opts features.RouteOptions) {
+	// Every request picks a new random list
+	random := middleware.Cache(middleware.CachePolicy{CacheControl: middleware.CacheNoStore})
+
+	r.With(middleware.RequireScope(auth.ScopeReadTopTen), random).Get("/topten", HTTPHandler(f.service))
+}
+
+func (f *Feature) MCPPrompts() []features.MCPPrompt {
+	return nil
+}
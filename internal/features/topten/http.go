@@ -0,0 +1,68 @@
+package topten
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"prospero/internal/web/handlers"
+)
+
+// randomLister is the subset of *Service the handlers need, for dependency injection
+type randomLister interface {
+	GetRandomList() (*TopTenList, error)
+}
+
+// HTTPHandler handles the /api/v1/topten endpoint
+func HTTPHandler(service randomLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the format parameter
+		format := r.URL.Query().Get("format")
+
+		// Auto-detect curl and default to ASCII
+		if format == "" {
+			userAgent := r.Header.Get("User-Agent")
+			if strings.Contains(strings.ToLower(userAgent), "curl") {
+				format = "ascii"
+			} else {
+				format = "json"
+			}
+		}
+
+		// Get a random list
+		list, err := service.GetRandomList()
+		if err != nil {
+			if errors.Is(err, ErrNoLists) {
+				handlers.WriteProblem(w, r, http.StatusServiceUnavailable, handlers.CodeNoLists, "No Top Ten lists are available")
+			} else {
+				slog.ErrorContext(r.Context(), "failed to get random list", "error", err)
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to get random list")
+			}
+			return
+		}
+
+		switch format {
+		case "ascii":
+			// Return ASCII formatted text
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			asciiOutput := FormatListAsASCII(list)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(asciiOutput))
+
+		case "json":
+			// Return JSON
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if err := json.NewEncoder(w).Encode(list); err != nil {
+				handlers.WriteProblem(w, r, http.StatusInternalServerError, handlers.CodeInternal, "Failed to encode JSON")
+				return
+			}
+
+		default:
+			handlers.WriteProblem(w, r, http.StatusBadRequest, handlers.CodeInvalidFormat, "Invalid format parameter. Use 'json' or 'ascii'")
+			return
+		}
+	}
+}
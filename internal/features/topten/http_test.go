@@ -1,4 +1,4 @@
-package handlers_test
+package topten_test
 
 import (
 	"encoding/json"
@@ -49,11 +49,11 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return JSON by default when not using curl", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
 		req.Header.Set("User-Agent", "Mozilla/5.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -70,11 +70,11 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return ASCII format when user-agent contains curl", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
 		req.Header.Set("User-Agent", "curl/7.68.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -88,11 +88,11 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return JSON when format=json is explicitly set", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten?format=json", nil)
 		req.Header.Set("User-Agent", "curl/7.68.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -106,10 +106,10 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return ASCII format when format=ascii", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten?format=ascii", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten?format=ascii", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -121,10 +121,10 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return error when format parameter is invalid", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten?format=invalid", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten?format=invalid", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -133,16 +133,29 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		service := &mockTopTenService{err: errors.New("database connection failed")}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Contains(t, w.Body.String(), "Failed to get random list")
 	})
 
+	t.Run("should return service unavailable when no lists are loaded", func(t *testing.T) {
+		service := &mockTopTenService{err: topten.ErrNoLists}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
+		w := httptest.NewRecorder()
+
+		handler := topten.HTTPHandler(service)
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), handlers.CodeNoLists)
+	})
+
 	t.Run("should detect curl in various user-agent strings", func(t *testing.T) {
 		tests := []struct {
 			name      string
@@ -159,11 +172,11 @@ func TestTopTen(t *testing.T) {
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
 				service := &mockTopTenService{list: sampleList}
-				req := httptest.NewRequest(http.MethodGet, "/api/topten", nil)
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
 				req.Header.Set("User-Agent", test.userAgent)
 				w := httptest.NewRecorder()
 
-				handler := handlers.TopTen(service)
+				handler := topten.HTTPHandler(service)
 				handler.ServeHTTP(w, req)
 
 				assert.Equal(t, http.StatusOK, w.Code)
@@ -183,10 +196,10 @@ func TestTopTen(t *testing.T) {
 
 	t.Run("should include all list fields in JSON response", func(t *testing.T) {
 		service := &mockTopTenService{list: sampleList}
-		req := httptest.NewRequest(http.MethodGet, "/api/topten?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten?format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.TopTen(service)
+		handler := topten.HTTPHandler(service)
 		handler.ServeHTTP(w, req)
 
 		var response topten.TopTenList
@@ -201,23 +214,3 @@ func TestTopTen(t *testing.T) {
 		assert.Equal(t, sampleList.URL, response.URL)
 	})
 }
-
-func TestHealth(t *testing.T) {
-	t.Run("should return healthy status with JSON response", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/health", nil)
-		w := httptest.NewRecorder()
-
-		handler := handlers.Health()
-		handler.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
-
-		var response map[string]string
-		err := json.NewDecoder(w.Body).Decode(&response)
-		require.NoError(t, err)
-
-		assert.Equal(t, "healthy", response["status"])
-		assert.Equal(t, "prospero", response["service"])
-	})
-}
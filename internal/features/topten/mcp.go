@@ -0,0 +1,47 @@
+package topten
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"prospero/internal/features"
+	"prospero/internal/mcp"
+)
+
+func (f *Feature) MCPTools() []features.MCPTool {
+	return []features.MCPTool{
+		{
+			Tool: mcp.Tool{
+				Name:        "topten_random",
+				Description: "Get a random David Letterman Top 10 list",
+				InputSchema: mcp.InputSchema{
+					Properties: map[string]mcp.SchemaProperty{
+						"format": {Type: "string", Description: "Output format: text (default) or json"},
+					},
+				},
+			},
+			Handler: f.randomTool,
+		},
+	}
+}
+
+func (f *Feature) randomTool(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	list, err := f.service.GetRandomList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get random list: %w", err)
+	}
+
+	switch format := mcp.StringArg(args, "format"); format {
+	case "", "text":
+		return mcp.TextResult(FormatListAsASCII(list)), nil
+	case "json":
+		data, err := json.Marshal(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode list: %w", err)
+		}
+		return mcp.TextResult(string(data)), nil
+	default:
+		return nil, fmt.Errorf("invalid format %q, use text or json", format)
+	}
+}
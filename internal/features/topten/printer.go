@@ -22,6 +22,35 @@ func isAlreadyNumbered(items []string) bool {
 	return numberedItemRegex.MatchString(strings.TrimSpace(items[0]))
 }
 
+// Item is a list entry split into its number and text
+type Item struct {
+	Number string
+	Text   string
+}
+
+// NumberedItems returns the items of the list with their numbers. Items that
+// are not numbered in the data count down from 10.
+func (l *TopTenList) NumberedItems() []Item {
+	alreadyNumbered := isAlreadyNumbered(l.Items)
+
+	items := make([]Item, len(l.Items))
+	for i, item := range l.Items {
+		if alreadyNumbered {
+			// Items already have numbers, extract them
+			parts := strings.SplitN(strings.TrimSpace(item), ".", 2)
+			if len(parts) >= 2 {
+				items[i] = Item{Number: strings.TrimSpace(parts[0]), Text: strings.TrimSpace(parts[1])}
+			} else {
+				// Fallback if splitting fails
+				items[i] = Item{Number: fmt.Sprintf("%d", i+1), Text: strings.TrimSpace(item)}
+			}
+		} else {
+			items[i] = Item{Number: fmt.Sprintf("%d", 10-i), Text: strings.TrimSpace(item)}
+		}
+	}
+	return items
+}
+
 // PrintList prints a formatted Top 10 list to the provided writer
 func PrintList(w io.Writer, list *TopTenList) {
 	// Style definitions
@@ -64,32 +93,11 @@ func PrintList(w io.Writer, list *TopTenList) {
 	content.WriteString("\n\n")
 
 	// Add the list items
-	alreadyNumbered := isAlreadyNumbered(list.Items)
-	for i, item := range list.Items {
-		var number string
-		var itemContent string
-
-		if alreadyNumbered {
-			// Items already have numbers, extract them
-			parts := strings.SplitN(strings.TrimSpace(item), ".", 2)
-			if len(parts) >= 2 {
-				number = strings.TrimSpace(parts[0])
-				itemContent = strings.TrimSpace(parts[1])
-			} else {
-				// Fallback if splitting fails
-				number = fmt.Sprintf("%d", i+1)
-				itemContent = strings.TrimSpace(item)
-			}
-		} else {
-			// Add numbers counting down from 10 to 1
-			number = fmt.Sprintf("%d", 10-i)
-			itemContent = strings.TrimSpace(item)
-		}
-
+	for _, item := range list.NumberedItems() {
 		// Right-align number within 2 character width
-		formattedNumber := fmt.Sprintf("%2s.", number)
+		formattedNumber := fmt.Sprintf("%2s.", item.Number)
 		styledNumber := numberStyle.Render(formattedNumber)
-		content.WriteString(fmt.Sprintf("  %s %s\n", styledNumber, itemContent))
+		content.WriteString(fmt.Sprintf("  %s %s\n", styledNumber, item.Text))
 	}
 
 	// Apply the container border and print
@@ -145,35 +153,27 @@ func PrintListASCII(w io.Writer, list *TopTenList) {
 	content.WriteString("\n\n")
 
 	// Add the list items
-	alreadyNumbered := isAlreadyNumbered(list.Items)
-	for i, item := range list.Items {
-		var number string
-		var itemContent string
-
-		if alreadyNumbered {
-			// Items already have numbers, extract them
-			parts := strings.SplitN(strings.TrimSpace(item), ".", 2)
-			if len(parts) >= 2 {
-				number = strings.TrimSpace(parts[0])
-				itemContent = strings.TrimSpace(parts[1])
-			} else {
-				// Fallback if splitting fails
-				number = fmt.Sprintf("%d", i+1)
-				itemContent = strings.TrimSpace(item)
-			}
-		} else {
-			// Add numbers counting down from 10 to 1
-			number = fmt.Sprintf("%d", 10-i)
-			itemContent = strings.TrimSpace(item)
-		}
-
+	for _, item := range list.NumberedItems() {
 		// Right-align number within 2 character width
-		formattedNumber := fmt.Sprintf("%2s.", number)
+		formattedNumber := fmt.Sprintf("%2s.", item.Number)
 		styledNumber := numberStyle.Render(formattedNumber)
-		content.WriteString(fmt.Sprintf("  %s %s\n", styledNumber, itemContent))
+		content.WriteString(fmt.Sprintf("  %s %s\n", styledNumber, item.Text))
 	}
 
 	// Apply the container border and print
 	finalOutput := containerStyle.Render(content.String())
 	fmt.Fprintf(w, "\n%s\n\n", finalOutput)
 }
+
+// writeListMarkdown writes list as a Markdown heading and its items. The
+// numbers count down, so they are written as text rather than as an ordered
+// list Markdown would renumber.
+func writeListMarkdown(w io.Writer, list *TopTenList) {
+	fmt.Fprintf(w, "# %s\n\n", list.Title)
+	if list.Show != "" || list.Date != "" {
+		fmt.Fprintf(w, "*%s*\n\n", strings.TrimSpace(list.Show+" "+list.Date))
+	}
+	for _, item := range list.NumberedItems() {
+		fmt.Fprintf(w, "- **%s.** %s\n", item.Number, item.Text)
+	}
+}
@@ -0,0 +1,134 @@
+// Package health tracks server lifecycle state and dependency checks for the
+// liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status values reported by probes
+const (
+	StatusOK          = "ok"
+	StatusStarting    = "starting"
+	StatusDraining    = "draining"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckFunc reports whether a component is healthy. A nil error means healthy.
+type CheckFunc func(ctx context.Context) error
+
+// ComponentStatus is the result of a single component check
+type ComponentStatus struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the aggregated readiness result
+type Report struct {
+	Status     string                     `json:"status"`
+	Pending    []string                   `json:"pending,omitempty"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Ready reports whether the service should receive traffic
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Registry holds component checks and the startup/shutdown state
+type Registry struct {
+	mu       sync.RWMutex
+	checks   map[string]CheckFunc
+	pending  map[string]bool
+	draining bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		checks:  make(map[string]CheckFunc),
+		pending: make(map[string]bool),
+	}
+}
+
+// Register adds or replaces a named component check
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Pending records a startup task. Readiness reports "starting" until every
+// pending task has been marked with Complete.
+func (r *Registry) Pending(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[name] = true
+}
+
+// Complete marks a startup task as finished
+func (r *Registry) Complete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, name)
+}
+
+// Drain marks the service as shutting down. Readiness fails from then on so
+// load balancers stop routing new traffic before listeners close.
+func (r *Registry) Drain() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.draining = true
+}
+
+// Check runs every component check and returns the aggregated report
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make(map[string]CheckFunc, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	pending := make([]string, 0, len(r.pending))
+	for name := range r.pending {
+		pending = append(pending, name)
+	}
+	draining := r.draining
+	r.mu.RUnlock()
+
+	sort.Strings(pending)
+
+	report := Report{
+		Status:     StatusOK,
+		Pending:    pending,
+		Components: make(map[string]ComponentStatus, len(checks)),
+	}
+
+	for name, check := range checks {
+		start := time.Now()
+		err := check(ctx)
+
+		status := ComponentStatus{
+			Status:   StatusOK,
+			Duration: time.Since(start).String(),
+		}
+		if err != nil {
+			status.Status = StatusUnavailable
+			status.Error = err.Error()
+			report.Status = StatusUnavailable
+		}
+		report.Components[name] = status
+	}
+
+	switch {
+	case draining:
+		report.Status = StatusDraining
+	case len(pending) > 0:
+		report.Status = StatusStarting
+	}
+
+	return report
+}
@@ -0,0 +1,72 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/health"
+)
+
+func TestRegistry(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("database closed") }
+
+	t.Run("should be ready when every check passes", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("shakespert", healthy)
+		probes.Register("topten", healthy)
+
+		report := probes.Check(context.Background())
+
+		assert.True(t, report.Ready())
+		assert.Equal(t, health.StatusOK, report.Status)
+		assert.Equal(t, health.StatusOK, report.Components["shakespert"].Status)
+		assert.Equal(t, health.StatusOK, report.Components["topten"].Status)
+	})
+
+	t.Run("should report failing components", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("shakespert", failing)
+		probes.Register("topten", healthy)
+
+		report := probes.Check(context.Background())
+
+		assert.False(t, report.Ready())
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.Equal(t, health.StatusUnavailable, report.Components["shakespert"].Status)
+		assert.Equal(t, "database closed", report.Components["shakespert"].Error)
+		assert.Equal(t, health.StatusOK, report.Components["topten"].Status)
+	})
+
+	t.Run("should report starting until pending tasks complete", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("topten", healthy)
+		probes.Pending("http")
+		probes.Pending("ssh")
+
+		report := probes.Check(context.Background())
+		assert.Equal(t, health.StatusStarting, report.Status)
+		assert.Equal(t, []string{"http", "ssh"}, report.Pending)
+
+		probes.Complete("http")
+		probes.Complete("ssh")
+
+		report = probes.Check(context.Background())
+		assert.True(t, report.Ready())
+		assert.Empty(t, report.Pending)
+	})
+
+	t.Run("should report draining after Drain", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("topten", healthy)
+		probes.Drain()
+
+		report := probes.Check(context.Background())
+
+		assert.False(t, report.Ready())
+		assert.Equal(t, health.StatusDraining, report.Status)
+	})
+}
@@ -0,0 +1,162 @@
+// Package hostkey loads and generates the host keys presented by the SSH
+// server. The host key asset holds one OpenSSH private key per algorithm,
+// concatenated, so clients can pick the algorithm they prefer.
+package hostkey
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"prospero/assets"
+	"prospero/internal/shared"
+)
+
+// Key algorithms that can be generated
+const (
+	Ed25519 = "ed25519"
+	ECDSA   = "ecdsa"
+	RSA     = "rsa"
+)
+
+// Algorithms lists every algorithm that can be generated, the preferred
+// one first
+var Algorithms = []string{Ed25519, ECDSA, RSA}
+
+// RSABits is the size of generated RSA keys
+const RSABits = 3072
+
+// Comment is written into generated keys and their public key lines
+const Comment = "prospero-ssh-server"
+
+// Generate creates a private key of the given algorithm, encoded as an
+// OpenSSH private key PEM block
+func Generate(algorithm string) ([]byte, error) {
+	var key crypto.PrivateKey
+	var err error
+	switch algorithm {
+	case Ed25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case ECDSA:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case RSA:
+		key, err = rsa.GenerateKey(rand.Reader, RSABits)
+	default:
+		return nil, fmt.Errorf("unknown host key algorithm: %s. Use %s", algorithm, strings.Join(Algorithms, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", algorithm, err)
+	}
+
+	block, err := cryptossh.MarshalPrivateKey(key, Comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s key: %w", algorithm, err)
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// GenerateAll creates a key for each of algorithms and concatenates them in
+// the form Parse reads
+func GenerateAll(algorithms []string) ([]byte, error) {
+	if len(algorithms) == 0 {
+		return nil, fmt.Errorf("at least one host key algorithm is required")
+	}
+
+	var keys bytes.Buffer
+	for i, algorithm := range algorithms {
+		if slices.Contains(algorithms[:i], algorithm) {
+			return nil, fmt.Errorf("host key algorithm %s is listed twice", algorithm)
+		}
+		key, err := Generate(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		keys.Write(key)
+	}
+	return keys.Bytes(), nil
+}
+
+// Parse reads the concatenated OpenSSH private keys in data. The server
+// presents a single key per algorithm, so two keys of the same type are
+// refused rather than one of them silently ignored.
+func Parse(data []byte) ([]cryptossh.Signer, error) {
+	var signers []cryptossh.Signer
+	rest := bytes.TrimSpace(data)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("host key %d is not a PEM encoded private key", len(signers)+1)
+		}
+		if block.Type != "OPENSSH PRIVATE KEY" {
+			return nil, fmt.Errorf("host key %d is a %s, not an OpenSSH private key", len(signers)+1, block.Type)
+		}
+
+		signer, err := cryptossh.ParsePrivateKey(pem.EncodeToMemory(block))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse host key %d: %w", len(signers)+1, err)
+		}
+		for _, other := range signers {
+			if other.PublicKey().Type() == signer.PublicKey().Type() {
+				return nil, fmt.Errorf("more than one %s host key", signer.PublicKey().Type())
+			}
+		}
+		signers = append(signers, signer)
+		rest = bytes.TrimSpace(rest)
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no host keys found")
+	}
+	return signers, nil
+}
+
+// Load reads the host key asset, decrypting it when it is age-encrypted
+func Load() ([]cryptossh.Signer, error) {
+	data, err := shared.ReadAsset(assets.HostKeyAsset)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Algorithm returns the name Generate knows the key's algorithm by, or its
+// SSH key type for algorithms it cannot generate
+func Algorithm(key cryptossh.PublicKey) string {
+	switch key.Type() {
+	case cryptossh.KeyAlgoED25519:
+		return Ed25519
+	case cryptossh.KeyAlgoECDSA256, cryptossh.KeyAlgoECDSA384, cryptossh.KeyAlgoECDSA521:
+		return ECDSA
+	case cryptossh.KeyAlgoRSA:
+		return RSA
+	}
+	return key.Type()
+}
+
+// AuthorizedKey formats key as a public key line, as found in .pub files
+func AuthorizedKey(key cryptossh.PublicKey) string {
+	return strings.TrimSpace(string(cryptossh.MarshalAuthorizedKey(key))) + " " + Comment
+}
+
+// KnownHosts formats key as a known_hosts line for the server reached at
+// each of hosts on port
+func KnownHosts(hosts []string, port string, key cryptossh.PublicKey) string {
+	addresses := make([]string, len(hosts))
+	for i, host := range hosts {
+		addresses[i] = knownhosts.Normalize(net.JoinHostPort(host, port))
+	}
+	return knownhosts.Line(addresses, key)
+}
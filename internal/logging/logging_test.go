@@ -0,0 +1,80 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/logging"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should write JSON records with context attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Format: "json", Level: "info"})
+		require.NoError(t, err)
+
+		ctx := logging.WithAttrs(context.Background(), slog.String("request_id", "abc-123"))
+		logger.InfoContext(ctx, "hello", "key", "value")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+		assert.Equal(t, "hello", record["msg"])
+		assert.Equal(t, "value", record["key"])
+		assert.Equal(t, "abc-123", record["request_id"])
+	})
+
+	t.Run("should accumulate attributes across WithAttrs calls", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Format: "text", Level: "info"})
+		require.NoError(t, err)
+
+		ctx := logging.WithAttrs(context.Background(), slog.String("ssh_session_id", "s1"))
+		ctx = logging.WithAttrs(ctx, slog.String("user", "prospero"))
+		logger.With("component", "ssh").InfoContext(ctx, "session started")
+
+		line := buf.String()
+		assert.Contains(t, line, "ssh_session_id=s1")
+		assert.Contains(t, line, "user=prospero")
+		assert.Contains(t, line, "component=ssh")
+	})
+
+	t.Run("should filter records below the configured level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Format: "text", Level: "warn"})
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "shown")
+	})
+
+	t.Run("should reject invalid formats and levels", func(t *testing.T) {
+		_, err := logging.New(&bytes.Buffer{}, logging.Options{Format: "xml", Level: "info"})
+		assert.Error(t, err)
+
+		_, err = logging.New(&bytes.Buffer{}, logging.Options{Format: "text", Level: "loud"})
+		assert.Error(t, err)
+	})
+}
+
+func TestCRLFWriter(t *testing.T) {
+	t.Run("should translate line feeds for raw terminals", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := logging.CRLFWriter(&buf)
+
+		n, err := w.Write([]byte("one\ntwo\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, len("one\ntwo\n"), n)
+		assert.Equal(t, "one\r\ntwo\r\n", buf.String())
+	})
+}
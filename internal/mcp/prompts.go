@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"embed"
 	"fmt"
 	"io/fs"
 	"path/filepath"
@@ -60,7 +59,7 @@ func (r *PromptRegistry) Execute(ctx context.Context, name string, args map[stri
 	return handler(ctx, args)
 }
 
-func LoadPromptsFromTOML(promptFiles embed.FS) ([]PromptDefinition, error) {
+func LoadPromptsFromTOML(promptFiles fs.FS) ([]PromptDefinition, error) {
 	var definitions []PromptDefinition
 
 	err := fs.WalkDir(promptFiles, "prompts", func(path string, d fs.DirEntry, err error) error {
@@ -77,7 +76,7 @@ func LoadPromptsFromTOML(promptFiles embed.FS) ([]PromptDefinition, error) {
 			return nil
 		}
 
-		data, err := promptFiles.ReadFile(path)
+		data, err := fs.ReadFile(promptFiles, path)
 		if err != nil {
 			return fmt.Errorf("failed to read %s: %w", path, err)
 		}
@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+
+	"prospero/internal/metrics"
 )
 
 type Server struct {
 	name            string
 	version         string
 	promptRegistry  *PromptRegistry
+	toolRegistry    *ToolRegistry
 	initialized     bool
 	protocolVersion string
 }
@@ -22,6 +26,7 @@ func NewServer(name, version string) *Server {
 		name:            name,
 		version:         version,
 		promptRegistry:  NewPromptRegistry(),
+		toolRegistry:    NewToolRegistry(),
 		protocolVersion: "2025-03-26",
 	}
 }
@@ -30,6 +35,20 @@ func (s *Server) RegisterPrompt(prompt Prompt, handler PromptHandler) {
 	s.promptRegistry.Register(prompt, handler)
 }
 
+func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
+	s.toolRegistry.Register(tool, handler)
+}
+
+// PromptCount returns the number of registered prompts
+func (s *Server) PromptCount() int {
+	return len(s.promptRegistry.prompts)
+}
+
+// ToolCount returns the number of registered tools
+func (s *Server) ToolCount() int {
+	return len(s.toolRegistry.tools)
+}
+
 func (s *Server) Run(ctx context.Context) error {
 	scanner := bufio.NewScanner(os.Stdin)
 	encoder := json.NewEncoder(os.Stdout)
@@ -60,14 +79,36 @@ func (s *Server) Run(ctx context.Context) error {
 			response := s.handleRequest(ctx, request)
 			if response != nil {
 				if err := encoder.Encode(response); err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing response: %v\n", err)
+					slog.ErrorContext(ctx, "failed to write mcp response", "error", err)
 				}
 			}
 		}
 	}
 }
 
+// knownMethods bounds the method label used for metrics
+var knownMethods = map[string]bool{
+	"initialize":   true,
+	"initialized":  true,
+	"prompts/list": true,
+	"prompts/get":  true,
+	"tools/list":   true,
+	"tools/call":   true,
+}
+
 func (s *Server) handleRequest(ctx context.Context, request JSONRPCRequest) *JSONRPCResponse {
+	response := s.dispatch(ctx, request)
+
+	method := request.Method
+	if !knownMethods[method] {
+		method = "unknown"
+	}
+	metrics.ObserveMCPCall(method, response != nil && response.Error != nil)
+
+	return response
+}
+
+func (s *Server) dispatch(ctx context.Context, request JSONRPCRequest) *JSONRPCResponse {
 	switch request.Method {
 	case "initialize":
 		return s.handleInitialize(request)
@@ -84,6 +125,16 @@ func (s *Server) handleRequest(ctx context.Context, request JSONRPCRequest) *JSO
 			return s.errorResponse(request.ID, -32002, "Server not initialized", nil)
 		}
 		return s.handlePromptsGet(ctx, request)
+	case "tools/list":
+		if !s.initialized {
+			return s.errorResponse(request.ID, -32002, "Server not initialized", nil)
+		}
+		return s.handleToolsList(request)
+	case "tools/call":
+		if !s.initialized {
+			return s.errorResponse(request.ID, -32002, "Server not initialized", nil)
+		}
+		return s.handleToolsCall(ctx, request)
 	default:
 		return s.errorResponse(request.ID, -32601, "Method not found", nil)
 	}
@@ -110,6 +161,9 @@ func (s *Server) handleInitialize(request JSONRPCRequest) *JSONRPCResponse {
 			Version: s.version,
 		},
 	}
+	if len(s.toolRegistry.tools) > 0 {
+		result.Capabilities.Tools = &ToolsCapability{}
+	}
 
 	return &JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -152,6 +206,39 @@ func (s *Server) handlePromptsGet(ctx context.Context, request JSONRPCRequest) *
 	}
 }
 
+func (s *Server) handleToolsList(request JSONRPCRequest) *JSONRPCResponse {
+	result := ListToolsResult{
+		Tools: s.toolRegistry.List(),
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  result,
+	}
+}
+
+func (s *Server) handleToolsCall(ctx context.Context, request JSONRPCRequest) *JSONRPCResponse {
+	var params CallToolParams
+	if request.Params != nil {
+		paramBytes, _ := json.Marshal(request.Params)
+		if err := json.Unmarshal(paramBytes, &params); err != nil {
+			return s.errorResponse(request.ID, -32602, "Invalid params", nil)
+		}
+	}
+
+	result, err := s.toolRegistry.Execute(ctx, params.Name, params.Arguments)
+	if err != nil {
+		return s.errorResponse(request.ID, -32602, err.Error(), nil)
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  result,
+	}
+}
+
 func (s *Server) errorResponse(id interface{}, code int, message string, data interface{}) *JSONRPCResponse {
 	return &JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -337,6 +338,109 @@ func TestServer_PromptsGet(t *testing.T) {
 	})
 }
 
+func TestServer_Tools(t *testing.T) {
+	newServer := func(t *testing.T) *mcp.Server {
+		t.Helper()
+
+		server := mcp.NewServer("test-server", "1.0.0")
+		server.RegisterTool(mcp.Tool{
+			Name:        "greet",
+			Description: "Greets someone",
+			InputSchema: mcp.InputSchema{
+				Properties: map[string]mcp.SchemaProperty{
+					"name": {Type: "string"},
+				},
+				Required: []string{"name"},
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			name := mcp.StringArg(args, "name")
+			if name == "" {
+				return nil, errors.New("name is required")
+			}
+			return mcp.TextResult("Hello, " + name), nil
+		})
+
+		initW := executeRequest(t, server.HTTPHandler(), createTestRequest(t, mcp.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      "init",
+			Method:  "initialize",
+		}))
+		require.Equal(t, 200, initW.Code)
+
+		var initRes mcp.JSONRPCResponse
+		require.NoError(t, json.NewDecoder(initW.Body).Decode(&initRes))
+		capabilities := initRes.Result.(map[string]interface{})["capabilities"].(map[string]interface{})
+		assert.Contains(t, capabilities, "tools")
+
+		notifW := executeRequest(t, server.HTTPHandler(), createTestRequest(t, mcp.JSONRPCRequest{
+			JSONRPC: "2.0",
+			Method:  "initialized",
+		}))
+		require.Equal(t, 202, notifW.Code)
+
+		return server
+	}
+
+	call := func(t *testing.T, server *mcp.Server, method string, params interface{}) mcp.JSONRPCResponse {
+		t.Helper()
+
+		w := executeRequest(t, server.HTTPHandler(), createTestRequest(t, mcp.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      "1",
+			Method:  method,
+			Params:  params,
+		}))
+
+		var res mcp.JSONRPCResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		return res
+	}
+
+	t.Run("should list registered tools with an object input schema", func(t *testing.T) {
+		res := call(t, newServer(t), "tools/list", nil)
+		require.Nil(t, res.Error)
+
+		tools := res.Result.(map[string]interface{})["tools"].([]interface{})
+		require.Len(t, tools, 1)
+
+		tool := tools[0].(map[string]interface{})
+		assert.Equal(t, "greet", tool["name"])
+		assert.Equal(t, "object", tool["inputSchema"].(map[string]interface{})["type"])
+	})
+
+	t.Run("should call a tool and return its content", func(t *testing.T) {
+		res := call(t, newServer(t), "tools/call", map[string]interface{}{
+			"name":      "greet",
+			"arguments": map[string]interface{}{"name": "World"},
+		})
+		require.Nil(t, res.Error)
+
+		result := res.Result.(map[string]interface{})
+		assert.NotContains(t, result, "isError")
+		content := result["content"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "Hello, World", content["text"])
+	})
+
+	t.Run("should report tool failures in the result", func(t *testing.T) {
+		res := call(t, newServer(t), "tools/call", map[string]interface{}{
+			"name": "greet",
+		})
+		require.Nil(t, res.Error)
+
+		result := res.Result.(map[string]interface{})
+		assert.Equal(t, true, result["isError"])
+	})
+
+	t.Run("should return error for unknown tool", func(t *testing.T) {
+		res := call(t, newServer(t), "tools/call", map[string]interface{}{
+			"name": "unknown-tool",
+		})
+
+		require.NotNil(t, res.Error)
+		assert.Equal(t, -32602, res.Error.Code)
+	})
+}
+
 func TestServer_MethodNotFound(t *testing.T) {
 	t.Run("should return method not found error for unknown method", func(t *testing.T) {
 		server := mcp.NewServer("test-server", "1.0.0")
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error)
+
+type ToolRegistry struct {
+	tools    map[string]Tool
+	handlers map[string]ToolHandler
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools:    make(map[string]Tool),
+		handlers: make(map[string]ToolHandler),
+	}
+}
+
+func (r *ToolRegistry) Register(tool Tool, handler ToolHandler) {
+	if tool.InputSchema.Type == "" {
+		tool.InputSchema.Type = "object"
+	}
+	r.tools[tool.Name] = tool
+	r.handlers[tool.Name] = handler
+}
+
+func (r *ToolRegistry) List() []Tool {
+	tools := make([]Tool, 0, len(r.tools))
+	for _, tool := range r.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// Execute runs the named tool. Errors from the tool itself are reported in
+// the result with IsError set, so the model can see and react to them.
+func (r *ToolRegistry) Execute(ctx context.Context, name string, args map[string]interface{}) (*CallToolResult, error) {
+	handler, exists := r.handlers[name]
+	if !exists {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+
+	result, err := handler(ctx, args)
+	if err != nil {
+		return ErrorResult(err.Error()), nil
+	}
+	return result, nil
+}
+
+// TextResult returns a tool result with a single text block
+func TextResult(text string) *CallToolResult {
+	return &CallToolResult{
+		Content: []MessageContent{{Type: "text", Text: text}},
+	}
+}
+
+// ErrorResult returns a tool result reporting a failure to the client
+func ErrorResult(message string) *CallToolResult {
+	result := TextResult(message)
+	result.IsError = true
+	return result
+}
+
+// StringArg returns the string argument name, or "" when absent
+func StringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// IntArg returns the integer argument name, or def when absent. JSON numbers
+// decode as float64.
+func IntArg(args map[string]interface{}, name string, def int) int {
+	switch value := args[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return def
+}
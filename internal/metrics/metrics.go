@@ -0,0 +1,170 @@
+// Package metrics defines the Prometheus metrics exported by Prospero and
+// helpers for recording them from the HTTP, SSH and MCP servers.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "prospero"
+
+// Registry holds every Prospero metric plus the Go runtime and process
+// collectors. A dedicated registry keeps third-party packages from leaking
+// metrics into our endpoint.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route pattern, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	sshActiveSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ssh",
+		Name:      "active_sessions",
+		Help:      "SSH sessions currently open.",
+	})
+
+	sshCommands = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ssh",
+		Name:      "commands_total",
+		Help:      "SSH commands executed by subcommand.",
+	}, []string{"command"})
+
+	sshSessionLimits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ssh",
+		Name:      "session_limits_total",
+		Help:      "SSH sessions refused or closed by a session limit, by limit.",
+	}, []string{"limit"})
+
+	sshAuth = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ssh",
+		Name:      "auth_attempts_total",
+		Help:      "SSH authentication attempts by method and result.",
+	}, []string{"method", "result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requests, connections and commands rejected by rate limits, by interface and budget.",
+	}, []string{"interface", "budget"})
+
+	mcpCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mcp",
+		Name:      "calls_total",
+		Help:      "MCP JSON-RPC method calls.",
+	}, []string{"method"})
+
+	mcpErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mcp",
+		Name:      "errors_total",
+		Help:      "MCP JSON-RPC method calls that returned an error.",
+	}, []string{"method"})
+
+	shakespertQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "shakespert",
+		Name:      "query_duration_seconds",
+		Help:      "Shakespert database query latency by query name.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"query"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		sshActiveSessions,
+		sshCommands,
+		sshSessionLimits,
+		sshAuth,
+		rateLimited,
+		mcpCalls,
+		mcpErrors,
+		shakespertQueryDuration,
+	)
+}
+
+// Handler returns an http.Handler serving the registry in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveHTTPRequest records a completed HTTP request
+func ObserveHTTPRequest(route, method, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(route, method, status).Inc()
+	httpDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// SSHSessionStarted increments the active SSH session gauge. The returned
+// function decrements it and must be called when the session ends.
+func SSHSessionStarted() func() {
+	sshActiveSessions.Inc()
+	return sshActiveSessions.Dec
+}
+
+// IncSSHCommand counts an SSH command invocation
+func IncSSHCommand(command string) {
+	sshCommands.WithLabelValues(command).Inc()
+}
+
+// IncSSHSessionLimit counts a session refused or closed by the named limit,
+// such as "idle_timeout" or "max_sessions_per_ip"
+func IncSSHSessionLimit(limit string) {
+	sshSessionLimits.WithLabelValues(limit).Inc()
+}
+
+// IncSSHAuth counts an SSH authentication attempt with method
+// ("publickey", "password" or "anonymous") and whether it succeeded
+func IncSSHAuth(method string, accepted bool) {
+	result := "rejected"
+	if accepted {
+		result = "accepted"
+	}
+	sshAuth.WithLabelValues(method, result).Inc()
+}
+
+// IncRateLimited counts a rejection by the named budget on an interface
+// ("http" or "ssh")
+func IncRateLimited(iface, budget string) {
+	rateLimited.WithLabelValues(iface, budget).Inc()
+}
+
+// ObserveMCPCall counts an MCP method call and whether it failed
+func ObserveMCPCall(method string, failed bool) {
+	mcpCalls.WithLabelValues(method).Inc()
+	if failed {
+		mcpErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveShakespertQuery records the latency of a named shakespert query
+// started at start. It is intended to be deferred:
+//
+//	defer metrics.ObserveShakespertQuery("ListWorks", time.Now())
+func ObserveShakespertQuery(query string, start time.Time) {
+	shakespertQueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
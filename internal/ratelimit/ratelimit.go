@@ -0,0 +1,134 @@
+// Package ratelimit implements token-bucket rate limiting shared by the HTTP
+// and SSH servers. Bucket state lives in a Store so an implementation shared
+// between instances can replace the in-memory one.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"time"
+)
+
+// Limit is a token-bucket budget: Requests tokens are added every Period, up
+// to Burst tokens
+type Limit struct {
+	Requests int
+	Period   time.Duration
+
+	// Burst is the bucket capacity. Zero means Requests.
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// capacity returns the bucket size
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate returns the refill rate in tokens per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Policy formats the limit for the RateLimit-Policy header
+func (l Limit) Policy() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", l.Requests, int(math.Ceil(l.Period.Seconds())), int(l.capacity()))
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed bool
+
+	// Limit is the bucket capacity and Remaining the tokens left after this
+	// request
+	Limit     int
+	Remaining int
+
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+
+	// RetryAfter is how long until a token is available. It is zero when
+	// Allowed.
+	RetryAfter time.Duration
+}
+
+// Store holds bucket state. Implementations must be safe for concurrent use.
+type Store interface {
+	// Take removes a token from the bucket named key, refilled according to
+	// limit as of now
+	Take(key string, limit Limit, now time.Time) Result
+}
+
+// Limiter applies a limit to keys in a store
+type Limiter struct {
+	store Store
+	limit Limit
+	now   func() time.Time
+}
+
+// NewLimiter creates a limiter applying limit to buckets in store
+func NewLimiter(store Store, limit Limit) *Limiter {
+	return &Limiter{store: store, limit: limit, now: time.Now}
+}
+
+// Limit returns the limiter's budget
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Allow takes a token for key. A disabled limit always allows.
+func (l *Limiter) Allow(key string) Result {
+	if !l.limit.Enabled() {
+		return Result{Allowed: true}
+	}
+	return l.store.Take(key, l.limit, l.now())
+}
+
+// bucket is the state of one token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b as of now and removes a token if one is available
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity, rate := limit.capacity(), limit.rate()
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.updated = now
+
+	result := Result{Limit: int(capacity)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+	return result
+}
+
+// seconds converts a float number of seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ClientAddr returns the host part of a remote address, so every connection
+// from one client shares a bucket regardless of its source port
+func ClientAddr(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
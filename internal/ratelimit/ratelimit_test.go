@@ -0,0 +1,86 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/ratelimit"
+)
+
+func TestMemoryStore(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 2, Period: time.Second}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should allow a burst then reject until tokens refill", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+
+		first := store.Take("client", limit, start)
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 2, first.Limit)
+		assert.Equal(t, 1, first.Remaining)
+
+		second := store.Take("client", limit, start)
+		assert.True(t, second.Allowed)
+		assert.Equal(t, 0, second.Remaining)
+		assert.Equal(t, time.Second, second.Reset)
+
+		third := store.Take("client", limit, start)
+		assert.False(t, third.Allowed)
+		assert.Equal(t, 500*time.Millisecond, third.RetryAfter)
+
+		later := store.Take("client", limit, start.Add(500*time.Millisecond))
+		assert.True(t, later.Allowed)
+	})
+
+	t.Run("should keep separate buckets per key", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		store.Take("a", limit, start)
+		store.Take("a", limit, start)
+
+		assert.False(t, store.Take("a", limit, start).Allowed)
+		assert.True(t, store.Take("b", limit, start).Allowed)
+	})
+
+	t.Run("should honour a burst larger than the rate", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		burst := ratelimit.Limit{Requests: 1, Period: time.Minute, Burst: 3}
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, store.Take("client", burst, start).Allowed)
+		}
+		result := store.Take("client", burst, start)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, time.Minute, result.RetryAfter)
+	})
+
+	t.Run("should drop buckets once they have refilled", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		store.Take("a", limit, start)
+		store.Take("b", limit, start.Add(2*time.Minute))
+
+		assert.Equal(t, 1, store.Len())
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("should allow everything when the limit is disabled", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{})
+
+		for i := 0; i < 100; i++ {
+			assert.True(t, limiter.Allow("client").Allowed)
+		}
+	})
+}
+
+func TestClientAddr(t *testing.T) {
+	t.Run("should strip the port", func(t *testing.T) {
+		assert.Equal(t, "192.0.2.1", ratelimit.ClientAddr("192.0.2.1:5555"))
+		assert.Equal(t, "2001:db8::1", ratelimit.ClientAddr("[2001:db8::1]:22"))
+	})
+
+	t.Run("should return addresses without a port unchanged", func(t *testing.T) {
+		assert.Equal(t, "192.0.2.1", ratelimit.ClientAddr("192.0.2.1"))
+	})
+}
@@ -0,0 +1,161 @@
+// Package sftp is a read-only SFTP server over an fs.FS. The protocol is
+// spoken by github.com/pkg/sftp; this package only answers its requests
+// from the filesystem and refuses every request that would change it.
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"path"
+
+	pkgsftp "github.com/pkg/sftp"
+)
+
+// Serve answers the SFTP requests read from rwc with the contents of fsys
+// until the client disconnects or ctx ends, which closes rwc. Paths are
+// relative to the root of fsys, which clients see as "/".
+func Serve(ctx context.Context, rwc io.ReadWriteCloser, fsys fs.FS) error {
+	h := handlers{ctx: ctx, fsys: fsys}
+	server := pkgsftp.NewRequestServer(rwc, pkgsftp.Handlers{
+		FileGet:  h,
+		FilePut:  h,
+		FileCmd:  h,
+		FileList: h,
+	})
+	stop := context.AfterFunc(ctx, func() { _ = server.Close() })
+	defer stop()
+
+	err := server.Serve()
+	if errors.Is(err, io.EOF) || ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// handlers answer the requests of one session
+type handlers struct {
+	ctx  context.Context
+	fsys fs.FS
+}
+
+// Fileread opens a file for download. Files that cannot be read at an
+// offset are read whole, as SFTP clients may read out of order.
+func (h handlers) Fileread(r *pkgsftp.Request) (io.ReaderAt, error) {
+	name := fsPath(r.Filepath)
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		return nil, h.fail(r, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, h.fail(r, err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, pkgsftp.ErrSSHFxFailure
+	}
+
+	if reader, ok := f.(io.ReaderAt); ok {
+		return file{ReaderAt: reader, Closer: f, size: info.Size()}, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, h.fail(r, err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// file is an open file, closed by the server with the handle
+type file struct {
+	io.ReaderAt
+	io.Closer
+	size int64
+}
+
+// ReadAt reads from the file, ending reads at its end even for files that
+// refuse offsets past it
+func (f file) ReadAt(p []byte, offset int64) (int, error) {
+	if offset >= f.size {
+		return 0, io.EOF
+	}
+	return f.ReaderAt.ReadAt(p, offset)
+}
+
+// Filewrite refuses uploads
+func (h handlers) Filewrite(*pkgsftp.Request) (io.WriterAt, error) {
+	return nil, pkgsftp.ErrSSHFxPermissionDenied
+}
+
+// Filecmd refuses renames, removals, new directories and attribute changes
+func (h handlers) Filecmd(*pkgsftp.Request) error {
+	return pkgsftp.ErrSSHFxPermissionDenied
+}
+
+// Filelist lists directories and describes files. Entries are listed with
+// their size, which generating filesystems compute by generating the file.
+func (h handlers) Filelist(r *pkgsftp.Request) (pkgsftp.ListerAt, error) {
+	name := fsPath(r.Filepath)
+	switch r.Method {
+	case "List":
+		entries, err := fs.ReadDir(h.fsys, name)
+		if err != nil {
+			return nil, h.fail(r, err)
+		}
+		infos := make(lister, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, h.fail(r, err)
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	case "Stat":
+		info, err := fs.Stat(h.fsys, name)
+		if err != nil {
+			return nil, h.fail(r, err)
+		}
+		return lister{info}, nil
+	}
+	return nil, pkgsftp.ErrSSHFxOpUnsupported
+}
+
+// fail reports a failed request. Errors other than missing files are
+// logged, as the client is only told that the request failed.
+func (h handlers) fail(r *pkgsftp.Request, err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return pkgsftp.ErrSSHFxNoSuchFile
+	}
+	slog.ErrorContext(h.ctx, "sftp request failed", "method", r.Method, "path", r.Filepath, "error", err)
+	return pkgsftp.ErrSSHFxFailure
+}
+
+// lister returns the entries of a directory listing in pages
+type lister []fs.FileInfo
+
+func (l lister) ListAt(page []fs.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(page, l[offset:])
+	if n < len(page) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fsPath converts a client path, absolute or relative to "/", to a path of
+// the filesystem
+func fsPath(name string) string {
+	cleaned := path.Clean("/" + name)
+	if cleaned == "/" {
+		return "."
+	}
+	return cleaned[1:]
+}
@@ -0,0 +1,166 @@
+package sftp_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	pkgsftp "github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/sftp"
+)
+
+var testFS = fstest.MapFS{
+	"works/hamlet.txt":    {Data: []byte("To be, or not to be"), Mode: 0o444, ModTime: time.Unix(1600, 0)},
+	"works/tempest.txt":   {Data: []byte("Our revels now are ended"), Mode: 0o444},
+	"sonnets/18.txt":      {Data: []byte("Shall I compare thee to a summer's day?"), Mode: 0o444},
+	"topten/1999/1.txt":   {Data: []byte("Top Ten"), Mode: 0o444},
+	"works/macbeth/1.txt": {Data: []byte("When shall we three meet again"), Mode: 0o444},
+}
+
+// serve starts serving testFS on one end of a pipe and returns the other
+// end, and the result of Serve once the connection is closed
+func serve(t *testing.T, ctx context.Context) (net.Conn, <-chan error) {
+	t.Helper()
+	server, conn := net.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- sftp.Serve(ctx, server, testFS) }()
+	t.Cleanup(func() { conn.Close() })
+	return conn, done
+}
+
+func newClient(t *testing.T) *pkgsftp.Client {
+	t.Helper()
+	conn, done := serve(t, context.Background())
+	client, err := pkgsftp.NewClientPipe(conn, conn)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Close()
+		assert.NoError(t, <-done)
+	})
+	return client
+}
+
+func TestServe(t *testing.T) {
+	t.Run("should resolve paths against the root", func(t *testing.T) {
+		client := newClient(t)
+		resolved, err := client.RealPath(".")
+		require.NoError(t, err)
+		assert.Equal(t, "/", resolved)
+
+		info, err := client.Stat("/works/../works/hamlet.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "hamlet.txt", info.Name())
+	})
+
+	t.Run("should read files at any offset", func(t *testing.T) {
+		client := newClient(t)
+		f, err := client.Open("/works/hamlet.txt")
+		require.NoError(t, err)
+		defer f.Close()
+
+		data := make([]byte, 6)
+		_, err = f.ReadAt(data, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "or not", string(data))
+
+		_, err = f.ReadAt(data, 100)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should report sizes, modes and times", func(t *testing.T) {
+		client := newClient(t)
+		info, err := client.Stat("works/hamlet.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len("To be, or not to be")), info.Size())
+		assert.Equal(t, fs.FileMode(0o444), info.Mode())
+		assert.Equal(t, int64(1600), info.ModTime().Unix())
+
+		_, err = client.Stat("/works/lear.txt")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("should list directories with the size of their files", func(t *testing.T) {
+		client := newClient(t)
+		infos, err := client.ReadDir("/works")
+		require.NoError(t, err)
+
+		sizes := map[string]int64{}
+		for _, info := range infos {
+			if !info.IsDir() {
+				sizes[info.Name()] = info.Size()
+			}
+		}
+		assert.Equal(t, map[string]int64{
+			"hamlet.txt":  int64(len("To be, or not to be")),
+			"tempest.txt": int64(len("Our revels now are ended")),
+		}, sizes)
+		assert.Len(t, infos, 3)
+
+		_, err = client.ReadDir("/plays")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("should refuse changes", func(t *testing.T) {
+		client := newClient(t)
+		_, err := client.Create("/works/lear.txt")
+		assert.ErrorIs(t, err, os.ErrPermission)
+		assert.ErrorIs(t, client.Remove("/works/hamlet.txt"), os.ErrPermission)
+		assert.ErrorIs(t, client.Mkdir("/plays"), os.ErrPermission)
+		assert.ErrorIs(t, client.Rename("/works/hamlet.txt", "/works/lear.txt"), os.ErrPermission)
+		assert.ErrorIs(t, client.Chmod("/works/hamlet.txt", 0o644), os.ErrPermission)
+	})
+
+	t.Run("should end sessions sending oversized packets", func(t *testing.T) {
+		conn, done := serve(t, context.Background())
+		go io.Copy(io.Discard, conn)
+
+		_, err := conn.Write(binary.BigEndian.AppendUint32(nil, 1<<30))
+		require.NoError(t, err)
+
+		select {
+		case err := <-done:
+			assert.Error(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the server kept reading an oversized packet")
+		}
+	})
+
+	t.Run("should end sessions sending truncated packets", func(t *testing.T) {
+		conn, done := serve(t, context.Background())
+		go io.Copy(io.Discard, conn)
+
+		_, err := conn.Write(binary.BigEndian.AppendUint32(nil, 100))
+		require.NoError(t, err)
+		_, err = conn.Write([]byte{1, 0})
+		require.NoError(t, err)
+		conn.Close()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the server did not end")
+		}
+	})
+
+	t.Run("should stop serving once the context ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, done := serve(t, ctx)
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the server kept serving")
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"fmt"
+
+	"prospero/assets"
+)
+
+// ReadAsset resolves the named asset and returns its plaintext contents,
+// decrypting it first when it is age-encrypted. An empty encrypted asset,
+// such as the keys file before any key is created, is returned as is.
+func ReadAsset(name string) ([]byte, error) {
+	asset, err := assets.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := asset.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if !asset.Encrypted || len(data) == 0 {
+		return data, nil
+	}
+
+	decrypted, err := DecryptAge(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s from %s: %w", name, asset.Source(), err)
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,49 @@
+// Package shared holds cross-cutting helpers used by several features.
+package shared
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ageArmorHeader starts an ASCII-armored age file
+const ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+// DecryptAge decrypts armored or binary age data with the scrypt password in
+// AGE_ENCRYPTION_PASSWORD
+func DecryptAge(encryptedData []byte) ([]byte, error) {
+	// Get the password from environment variable
+	password := os.Getenv("AGE_ENCRYPTION_PASSWORD")
+	if password == "" {
+		return nil, fmt.Errorf("AGE_ENCRYPTION_PASSWORD environment variable is not set")
+	}
+
+	// Create age identity for decryption
+	identity, err := age.NewScryptIdentity(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create age identity: %w", err)
+	}
+
+	// Check if the data is armored (ASCII format)
+	var ageReader io.Reader = bytes.NewReader(encryptedData)
+	if bytes.HasPrefix(encryptedData, []byte(ageArmorHeader)) {
+		ageReader = armor.NewReader(ageReader)
+	}
+
+	reader, err := age.Decrypt(ageReader, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt age data: %w", err)
+	}
+
+	decryptedData, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decrypted data: %w", err)
+	}
+
+	return decryptedData, nil
+}
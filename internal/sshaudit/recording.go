@@ -0,0 +1,161 @@
+package sshaudit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Header is the first line of an asciicast v2 recording
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes the output of a session as asciicast v2 events. Only the
+// output is recorded, so passwords typed without echo never are.
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	start time.Time
+	err   error
+}
+
+func newRecorder(path string, header Header) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	r := &Recorder{file: file, w: bufio.NewWriter(file), start: time.Now()}
+	header.Version = 2
+	header.Timestamp = r.start.Unix()
+	r.writeLine(header)
+	return r, nil
+}
+
+// Name is the file name of the recording
+func (r *Recorder) Name() string {
+	return filepath.Base(r.file.Name())
+}
+
+// Write records output. It never fails, so a full disk does not end the
+// session; Close reports the first error.
+func (r *Recorder) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		r.event("o", string(p))
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size
+func (r *Recorder) Resize(width, height int) {
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *Recorder) event(kind, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeLine([]any{time.Since(r.start).Seconds(), kind, data})
+}
+
+func (r *Recorder) writeLine(v any) {
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(v)
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
+
+// Close finishes the recording
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.err
+	if flushErr := r.w.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// ReplayOptions control the playback of a recording
+type ReplayOptions struct {
+	// Speed multiplies the playback speed; zero plays in real time
+	Speed float64
+
+	// IdleLimit caps the pause between two outputs; zero keeps every pause
+	IdleLimit time.Duration
+}
+
+// Replay writes the output of a recording to w with its original timing
+func Replay(ctx context.Context, r io.Reader, w io.Writer, opts ReplayOptions) error {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	if !scanner.Scan() {
+		return errors.New("failed to read recording: missing header")
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 {
+		return errors.New("failed to read recording: not an asciicast v2 file")
+	}
+
+	var last float64
+	for line := 2; scanner.Scan(); line++ {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return fmt.Errorf("failed to read recording line %d", line)
+		}
+		at, _ := event[0].(float64)
+		kind, _ := event[1].(string)
+		data, _ := event[2].(string)
+		if kind != "o" {
+			continue
+		}
+
+		pause := time.Duration((at - last) / speed * float64(time.Second))
+		if opts.IdleLimit > 0 {
+			pause = min(pause, opts.IdleLimit)
+		}
+		last = at
+		if pause > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pause):
+			}
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package sshaudit records who ran what over SSH. Every session ends with an
+// Event appended to a JSON Lines log, and interactive sessions can be
+// recorded in the asciicast v2 format played by asciinema.
+package sshaudit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogFile is the name of the session log in the audit directory
+const LogFile = "sessions.jsonl"
+
+// recordingExt is the extension of session recordings
+const recordingExt = ".cast"
+
+var (
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrAmbiguousSession = errors.New("session ID matches several sessions")
+)
+
+// Event describes a finished SSH session
+type Event struct {
+	SessionID      string    `json:"session_id"`
+	Start          time.Time `json:"start"`
+	DurationMS     int64     `json:"duration_ms"`
+	RemoteAddr     string    `json:"remote_addr"`
+	User           string    `json:"user"`
+	AuthMethod     string    `json:"auth_method"`
+	Role           string    `json:"role"`
+	KeyFingerprint string    `json:"key_fingerprint,omitempty"`
+	Command        string    `json:"command"`
+	Subsystem      string    `json:"subsystem,omitempty"`
+	Interactive    bool      `json:"interactive"`
+	ExitStatus     int       `json:"exit_status"`
+	BytesOut       int64     `json:"bytes_out"`
+
+	// Limit names the session limit that refused or closed the session, if
+	// any
+	Limit string `json:"limit,omitempty"`
+
+	// Recording is the file name of the session's recording, if any
+	Recording string `json:"recording,omitempty"`
+}
+
+// Duration is how long the session lasted
+func (e Event) Duration() time.Duration {
+	return time.Duration(e.DurationMS) * time.Millisecond
+}
+
+// ShortID is the prefix of the session ID shown in listings
+func (e Event) ShortID() string {
+	if len(e.SessionID) > 12 {
+		return e.SessionID[:12]
+	}
+	return e.SessionID
+}
+
+// Log appends events to the session log of a directory and creates the
+// recordings of interactive sessions next to it
+type Log struct {
+	mu     sync.Mutex
+	dir    string
+	file   *os.File
+	record bool
+}
+
+// Open opens the session log in dir, creating both if needed. Interactive
+// sessions are recorded when record is set.
+func Open(dir string, record bool) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, LogFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	return &Log{dir: dir, file: file, record: record}, nil
+}
+
+// Dir is the audit directory
+func (l *Log) Dir() string {
+	return l.dir
+}
+
+// Write appends an event to the session log
+func (l *Log) Write(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode session event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write session event: %w", err)
+	}
+	return nil
+}
+
+// Record starts the recording of an interactive session, or returns nil
+// when recordings are off
+func (l *Log) Record(sessionID string, header Header) (*Recorder, error) {
+	if !l.record {
+		return nil, nil
+	}
+	return newRecorder(filepath.Join(l.dir, sessionID+recordingExt), header)
+}
+
+// Close closes the session log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// ReadEvents reads the session log of dir, oldest session first
+func ReadEvents(dir string) ([]Event, error) {
+	file, err := os.Open(filepath.Join(dir, LogFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to read session log line %d: %w", line, err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session log: %w", err)
+	}
+	return events, nil
+}
+
+// Find returns the event whose session ID starts with id
+func Find(events []Event, id string) (Event, error) {
+	var found []Event
+	for _, e := range events {
+		if id != "" && strings.HasPrefix(e.SessionID, id) {
+			found = append(found, e)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Event{}, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	case 1:
+		return found[0], nil
+	default:
+		return Event{}, fmt.Errorf("%w: %s", ErrAmbiguousSession, id)
+	}
+}
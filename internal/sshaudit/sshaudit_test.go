@@ -0,0 +1,109 @@
+package sshaudit_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/sshaudit"
+)
+
+func TestLog(t *testing.T) {
+	t.Run("should append events and read them back in order", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "audit")
+		log, err := sshaudit.Open(dir, false)
+		require.NoError(t, err)
+
+		first := sshaudit.Event{SessionID: "a1b2", Command: "topten", ExitStatus: 0, BytesOut: 512, DurationMS: 1500}
+		second := sshaudit.Event{SessionID: "c3d4", Command: "stats", ExitStatus: 1, KeyFingerprint: "SHA256:abc"}
+		require.NoError(t, log.Write(first))
+		require.NoError(t, log.Write(second))
+		require.NoError(t, log.Close())
+
+		events, err := sshaudit.ReadEvents(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []sshaudit.Event{first, second}, events)
+		assert.Equal(t, 1500*time.Millisecond, events[0].Duration())
+	})
+
+	t.Run("should not record unless asked to", func(t *testing.T) {
+		log, err := sshaudit.Open(t.TempDir(), false)
+		require.NoError(t, err)
+		defer log.Close()
+
+		recorder, err := log.Record("a1b2", sshaudit.Header{Width: 80, Height: 24})
+		require.NoError(t, err)
+		assert.Nil(t, recorder)
+	})
+
+	t.Run("should report the line of malformed events", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, sshaudit.LogFile), []byte("{}\nnot json\n"), 0o600))
+
+		_, err := sshaudit.ReadEvents(dir)
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+func TestFind(t *testing.T) {
+	events := []sshaudit.Event{{SessionID: "a1b2c3"}, {SessionID: "a1f0e9"}, {SessionID: "d4e5f6"}}
+
+	t.Run("should find sessions by a unique prefix", func(t *testing.T) {
+		event, err := sshaudit.Find(events, "a1b")
+		require.NoError(t, err)
+		assert.Equal(t, "a1b2c3", event.SessionID)
+	})
+
+	t.Run("should reject unknown and ambiguous prefixes", func(t *testing.T) {
+		_, err := sshaudit.Find(events, "ff")
+		assert.ErrorIs(t, err, sshaudit.ErrSessionNotFound)
+		_, err = sshaudit.Find(events, "a1")
+		assert.ErrorIs(t, err, sshaudit.ErrAmbiguousSession)
+		_, err = sshaudit.Find(events, "")
+		assert.ErrorIs(t, err, sshaudit.ErrSessionNotFound)
+	})
+}
+
+func TestRecording(t *testing.T) {
+	t.Run("should replay the recorded output", func(t *testing.T) {
+		dir := t.TempDir()
+		log, err := sshaudit.Open(dir, true)
+		require.NoError(t, err)
+		defer log.Close()
+
+		recorder, err := log.Record("a1b2", sshaudit.Header{Width: 80, Height: 24, Env: map[string]string{"TERM": "xterm"}})
+		require.NoError(t, err)
+		require.NotNil(t, recorder)
+		assert.Equal(t, "a1b2.cast", recorder.Name())
+
+		_, _ = recorder.Write([]byte("prospero> "))
+		recorder.Resize(100, 30)
+		_, _ = recorder.Write([]byte("topten\r\n"))
+		require.NoError(t, recorder.Close())
+
+		data, err := os.ReadFile(filepath.Join(dir, "a1b2.cast"))
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 4)
+		assert.Contains(t, lines[0], `"version":2,"width":80,"height":24`)
+		assert.Contains(t, lines[2], `"r","100x30"`)
+
+		var out bytes.Buffer
+		err = sshaudit.Replay(context.Background(), bytes.NewReader(data), &out, sshaudit.ReplayOptions{IdleLimit: time.Millisecond})
+		require.NoError(t, err)
+		assert.Equal(t, "prospero> topten\r\n", out.String())
+	})
+
+	t.Run("should refuse files that are not recordings", func(t *testing.T) {
+		err := sshaudit.Replay(context.Background(), strings.NewReader(`{"version":1}`), io.Discard, sshaudit.ReplayOptions{})
+		assert.ErrorContains(t, err, "not an asciicast v2 file")
+	})
+}
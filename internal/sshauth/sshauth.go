@@ -0,0 +1,333 @@
+// Package sshauth authenticates SSH connections. Clients are identified by
+// a listed public key, a keyboard-interactive password or, when allowed, as
+// anonymous users, and each identity carries the role that gates the
+// commands it may run.
+package sshauth
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/assets"
+	"prospero/internal/shared"
+)
+
+// Roles granted to SSH clients
+const (
+	RoleUser = "user"
+
+	// RoleAdmin may also run the admin commands
+	RoleAdmin = "admin"
+)
+
+// Roles lists every known role
+var Roles = []string{RoleUser, RoleAdmin}
+
+// ValidRole reports whether role is known
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// Allows reports whether role may run a command requiring required. An
+// empty requirement is open to every role.
+func Allows(role, required string) bool {
+	return required == "" || role == required || role == RoleAdmin
+}
+
+// Methods clients authenticate with
+const (
+	MethodAnonymous = "anonymous"
+	MethodPublicKey = "publickey"
+	MethodPassword  = "password"
+)
+
+// Identity is an authenticated SSH client
+type Identity struct {
+	// User is the name the client is known by: the owner of its key, the
+	// password user or, for anonymous clients, the requested user name
+	User   string
+	Role   string
+	Method string
+
+	// Fingerprint is the SHA-256 fingerprint of the client's public key,
+	// empty for clients that did not authenticate with a key
+	Fingerprint string
+}
+
+// AuthorizedKey is a public key allowed to connect
+type AuthorizedKey struct {
+	Key cryptossh.PublicKey
+
+	// Name is the key owner: the user name of a keys file or the comment
+	// of an authorized_keys line
+	Name string
+	Role string
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the key
+func (k AuthorizedKey) Fingerprint() string {
+	return cryptossh.FingerprintSHA256(k.Key)
+}
+
+// ParseAuthorizedKeys decodes keys in authorized_keys format, one per line.
+// A role="admin" option grants a role other than defaultRole; other options
+// are ignored. Blank lines and # comments are skipped.
+func ParseAuthorizedKeys(data []byte, defaultRole string) ([]AuthorizedKey, error) {
+	var keys []AuthorizedKey
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		key, comment, options, _, err := cryptossh.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse key: %w", n, err)
+		}
+
+		authorized := AuthorizedKey{Key: key, Name: comment, Role: defaultRole}
+		for _, option := range options {
+			name, value, ok := strings.Cut(option, "=")
+			if !ok || !strings.EqualFold(name, "role") {
+				continue
+			}
+			role := strings.Trim(value, `"`)
+			if !ValidRole(role) {
+				return nil, fmt.Errorf("line %d: unknown role %q", n, role)
+			}
+			authorized.Role = role
+		}
+		keys = append(keys, authorized)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read keys: %w", err)
+	}
+	return keys, nil
+}
+
+// keysFileSuffix ends the files of a keys directory
+const keysFileSuffix = ".keys"
+
+// LoadKeysDir reads a directory of <user>.keys files, each listing the
+// public keys of one user in the format served by
+// https://github.com/<user>.keys. Every key is granted role.
+func LoadKeysDir(dir, role string) ([]AuthorizedKey, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read keys directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+keysFileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys in %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	var keys []AuthorizedKey
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		userKeys, err := ParseAuthorizedKeys(data, role)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		user := strings.TrimSuffix(filepath.Base(path), keysFileSuffix)
+		for i := range userKeys {
+			userKeys[i].Name = user
+			userKeys[i].Role = role
+		}
+		keys = append(keys, userKeys...)
+	}
+	return keys, nil
+}
+
+// User is a password user
+type User struct {
+	Role string
+
+	// PasswordHash is the bcrypt hash of the password
+	PasswordHash string
+}
+
+// Options configures an Authenticator
+type Options struct {
+	// Anonymous lets clients without a listed key or password in, with
+	// AnonymousRole
+	Anonymous     bool
+	AnonymousRole string
+
+	// AuthorizedKeys loads the authorized_keys asset, granting KeyRole to
+	// keys without a role option
+	AuthorizedKeys bool
+
+	// KeysDir is a directory of <user>.keys files granted KeyRole
+	KeysDir string
+	KeyRole string
+
+	// Users are the keyboard-interactive password users by name
+	Users map[string]User
+}
+
+// ErrNoMethods is returned when options let no client in
+var ErrNoMethods = errors.New("no SSH authentication method is enabled")
+
+// Authenticator decides who may connect and with which role. Its keys can
+// be reloaded while the server runs.
+type Authenticator struct {
+	opts Options
+
+	mu   sync.RWMutex
+	keys map[string]AuthorizedKey
+}
+
+// New creates an authenticator and loads its keys
+func New(opts Options) (*Authenticator, error) {
+	a := &Authenticator{opts: opts}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	if !opts.Anonymous && len(opts.Users) == 0 && a.Len() == 0 {
+		return nil, ErrNoMethods
+	}
+	return a, nil
+}
+
+// Reload reads the authorized_keys asset and the keys directory again.
+// The current keys are kept when either fails to load.
+func (a *Authenticator) Reload() error {
+	var keys []AuthorizedKey
+	if a.opts.AuthorizedKeys {
+		data, err := shared.ReadAsset(assets.AuthorizedKeysAsset)
+		if err != nil {
+			return fmt.Errorf("failed to load authorized keys: %w", err)
+		}
+		listed, err := ParseAuthorizedKeys(data, a.opts.KeyRole)
+		if err != nil {
+			return fmt.Errorf("failed to load authorized keys: %w", err)
+		}
+		keys = append(keys, listed...)
+	}
+	if a.opts.KeysDir != "" {
+		listed, err := LoadKeysDir(a.opts.KeysDir, a.opts.KeyRole)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, listed...)
+	}
+	a.SetKeys(keys)
+	return nil
+}
+
+// SetKeys replaces the listed keys. When a key is listed twice, the first
+// listing wins.
+func (a *Authenticator) SetKeys(keys []AuthorizedKey) {
+	byFingerprint := make(map[string]AuthorizedKey, len(keys))
+	for _, key := range keys {
+		fingerprint := key.Fingerprint()
+		if _, ok := byFingerprint[fingerprint]; !ok {
+			byFingerprint[fingerprint] = key
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = byFingerprint
+}
+
+// Len returns the number of listed keys
+func (a *Authenticator) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.keys)
+}
+
+// PublicKey authenticates user connecting with a listed key. Unlisted keys
+// are rejected; AnonymousKey decides whether they are let in anonymously.
+func (a *Authenticator) PublicKey(user string, key cryptossh.PublicKey) (Identity, bool) {
+	fingerprint := cryptossh.FingerprintSHA256(key)
+
+	a.mu.RLock()
+	listed, ok := a.keys[fingerprint]
+	a.mu.RUnlock()
+	if !ok {
+		return Identity{}, false
+	}
+
+	name := listed.Name
+	if name == "" {
+		name = user
+	}
+	return Identity{User: name, Role: listed.Role, Method: MethodPublicKey, Fingerprint: fingerprint}, true
+}
+
+// Password authenticates a password user
+func (a *Authenticator) Password(user, password string) (Identity, bool) {
+	u, ok := a.opts.Users[user]
+	if !ok {
+		return Identity{}, false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return Identity{}, false
+	}
+	return Identity{User: user, Role: u.Role, Method: MethodPassword}, true
+}
+
+// HasPassword reports whether user must authenticate with a password
+func (a *Authenticator) HasPassword(user string) bool {
+	_, ok := a.opts.Users[user]
+	return ok
+}
+
+// Anonymous lets user in without credentials when anonymous access is on
+func (a *Authenticator) Anonymous(user string) (Identity, bool) {
+	if !a.opts.Anonymous {
+		return Identity{}, false
+	}
+	return Identity{User: user, Role: a.opts.AnonymousRole, Method: MethodAnonymous}, true
+}
+
+// AnonymousKey lets user in with an unlisted key when anonymous access is
+// on, recording the key's fingerprint. Password users are refused so that
+// they go on to be asked for their password.
+func (a *Authenticator) AnonymousKey(user string, key cryptossh.PublicKey) (Identity, bool) {
+	if a.HasPassword(user) {
+		return Identity{}, false
+	}
+	identity, ok := a.Anonymous(user)
+	if ok {
+		identity.Fingerprint = cryptossh.FingerprintSHA256(key)
+	}
+	return identity, ok
+}
+
+// HashPassword returns the bcrypt hash a password user's password is
+// configured as
+func HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// ValidPasswordHash reports whether hash is a bcrypt hash
+func ValidPasswordHash(hash string) bool {
+	_, err := bcrypt.Cost([]byte(hash))
+	return err == nil
+}
@@ -0,0 +1,172 @@
+package sshauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"prospero/internal/sshauth"
+)
+
+func newKey(t *testing.T) cryptossh.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := cryptossh.NewPublicKey(public)
+	require.NoError(t, err)
+	return key
+}
+
+func authorizedLine(key cryptossh.PublicKey) string {
+	return strings.TrimSpace(string(cryptossh.MarshalAuthorizedKey(key)))
+}
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	t.Run("should read roles from options and skip comments", func(t *testing.T) {
+		admin, user := newKey(t), newKey(t)
+		data := "# operators\n\n" +
+			`role="admin",no-pty ` + authorizedLine(admin) + " prospero@island\n" +
+			authorizedLine(user) + " caliban@island\n"
+
+		keys, err := sshauth.ParseAuthorizedKeys([]byte(data), sshauth.RoleUser)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		assert.Equal(t, "prospero@island", keys[0].Name)
+		assert.Equal(t, sshauth.RoleAdmin, keys[0].Role)
+		assert.Equal(t, cryptossh.FingerprintSHA256(admin), keys[0].Fingerprint())
+		assert.Equal(t, "caliban@island", keys[1].Name)
+		assert.Equal(t, sshauth.RoleUser, keys[1].Role)
+	})
+
+	t.Run("should report the line of malformed keys and unknown roles", func(t *testing.T) {
+		_, err := sshauth.ParseAuthorizedKeys([]byte("\nssh-ed25519 garbage\n"), sshauth.RoleUser)
+		assert.ErrorContains(t, err, "line 2")
+
+		_, err = sshauth.ParseAuthorizedKeys([]byte(`role="king" `+authorizedLine(newKey(t))), sshauth.RoleUser)
+		assert.ErrorContains(t, err, `unknown role "king"`)
+	})
+}
+
+func TestLoadKeysDir(t *testing.T) {
+	t.Run("should name keys after their file", func(t *testing.T) {
+		dir := t.TempDir()
+		key := newKey(t)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "miranda.keys"), []byte(authorizedLine(key)+"\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not keys"), 0o600))
+
+		keys, err := sshauth.LoadKeysDir(dir, sshauth.RoleAdmin)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "miranda", keys[0].Name)
+		assert.Equal(t, sshauth.RoleAdmin, keys[0].Role)
+	})
+
+	t.Run("should fail for a missing directory", func(t *testing.T) {
+		_, err := sshauth.LoadKeysDir(filepath.Join(t.TempDir(), "missing"), sshauth.RoleUser)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("full fathom five"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	listed := newKey(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ariel.keys"), []byte(authorizedLine(listed)), 0o600))
+
+	authn, err := sshauth.New(sshauth.Options{
+		Anonymous:     true,
+		AnonymousRole: sshauth.RoleUser,
+		KeysDir:       dir,
+		KeyRole:       sshauth.RoleAdmin,
+		Users:         map[string]sshauth.User{"ferdinand": {Role: sshauth.RoleUser, PasswordHash: string(hash)}},
+	})
+	require.NoError(t, err)
+
+	t.Run("should accept only listed keys", func(t *testing.T) {
+		identity, ok := authn.PublicKey("guest", listed)
+		require.True(t, ok)
+		assert.Equal(t, sshauth.Identity{
+			User:        "ariel",
+			Role:        sshauth.RoleAdmin,
+			Method:      sshauth.MethodPublicKey,
+			Fingerprint: cryptossh.FingerprintSHA256(listed),
+		}, identity)
+
+		_, ok = authn.PublicKey("guest", newKey(t))
+		assert.False(t, ok)
+	})
+
+	t.Run("should check passwords against their hash", func(t *testing.T) {
+		identity, ok := authn.Password("ferdinand", "full fathom five")
+		require.True(t, ok)
+		assert.Equal(t, sshauth.MethodPassword, identity.Method)
+
+		_, ok = authn.Password("ferdinand", "wrong")
+		assert.False(t, ok)
+		_, ok = authn.Password("stephano", "full fathom five")
+		assert.False(t, ok)
+		assert.True(t, authn.HasPassword("ferdinand"))
+	})
+
+	t.Run("should let anonymous clients in with the anonymous role", func(t *testing.T) {
+		identity, ok := authn.Anonymous("guest")
+		require.True(t, ok)
+		assert.Equal(t, sshauth.Identity{User: "guest", Role: sshauth.RoleUser, Method: sshauth.MethodAnonymous}, identity)
+	})
+
+	t.Run("should let unlisted keys in anonymously with their fingerprint", func(t *testing.T) {
+		key := newKey(t)
+		identity, ok := authn.AnonymousKey("guest", key)
+		require.True(t, ok)
+		assert.Equal(t, sshauth.Identity{
+			User:        "guest",
+			Role:        sshauth.RoleUser,
+			Method:      sshauth.MethodAnonymous,
+			Fingerprint: cryptossh.FingerprintSHA256(key),
+		}, identity)
+
+		_, ok = authn.AnonymousKey("ferdinand", key)
+		assert.False(t, ok, "password users are asked for their password")
+
+		closed, err := sshauth.New(sshauth.Options{KeysDir: dir, KeyRole: sshauth.RoleUser})
+		require.NoError(t, err)
+		_, ok = closed.AnonymousKey("guest", key)
+		assert.False(t, ok)
+	})
+
+	t.Run("should pick up new keys on reload", func(t *testing.T) {
+		added := newKey(t)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gonzalo.keys"), []byte(authorizedLine(added)), 0o600))
+
+		require.NoError(t, authn.Reload())
+		assert.Equal(t, 2, authn.Len())
+		_, ok := authn.PublicKey("guest", added)
+		assert.True(t, ok)
+	})
+
+	t.Run("should refuse options that let nobody in", func(t *testing.T) {
+		_, err := sshauth.New(sshauth.Options{})
+		assert.ErrorIs(t, err, sshauth.ErrNoMethods)
+	})
+}
+
+func TestAllows(t *testing.T) {
+	t.Run("should let admins run everything", func(t *testing.T) {
+		assert.True(t, sshauth.Allows(sshauth.RoleAdmin, sshauth.RoleAdmin))
+		assert.True(t, sshauth.Allows(sshauth.RoleAdmin, ""))
+		assert.True(t, sshauth.Allows(sshauth.RoleUser, ""))
+		assert.False(t, sshauth.Allows(sshauth.RoleUser, sshauth.RoleAdmin))
+		assert.False(t, sshauth.Allows("", sshauth.RoleUser))
+	})
+}
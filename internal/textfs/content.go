@@ -0,0 +1,285 @@
+package textfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+)
+
+// works lists a text, Markdown and JSON file and a directory of scenes for
+// every work
+func (f *FS) works() ([]node, error) {
+	works, err := f.src.Library.ListWorks(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []node
+	for _, work := range works {
+		id := work.WorkID
+		nodes = append(nodes,
+			dirNode(id, func() ([]node, error) { return f.scenes(id) }),
+			fileNode(id+".json", func() ([]byte, error) { return f.workJSON(id) }),
+			fileNode(id+".md", func() ([]byte, error) { return f.workText(id, true) }),
+			fileNode(id+".txt", func() ([]byte, error) { return f.workText(id, false) }),
+		)
+	}
+	return nodes, nil
+}
+
+// scenes lists a <act>-<scene>.txt file for every scene of a work
+func (f *FS) scenes(workID string) ([]node, error) {
+	scenes, err := f.src.Library.ListScenes(f.ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]node, len(scenes))
+	for i, scene := range scenes {
+		nodes[i] = fileNode(fmt.Sprintf("%d-%d.txt", scene.Act, scene.Scene), func() ([]byte, error) {
+			return f.sceneText(workID, scene)
+		})
+	}
+	return nodes, nil
+}
+
+// sonnets lists a <n>.txt file for every sonnet
+func (f *FS) sonnets() ([]node, error) {
+	scenes, err := f.src.Library.ListScenes(f.ctx, sonnetsWorkID)
+	if err != nil {
+		// A database without the sonnets has an empty directory
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	nodes := make([]node, len(scenes))
+	for i, scene := range scenes {
+		nodes[i] = fileNode(fmt.Sprintf("%d.txt", scene.Scene), func() ([]byte, error) {
+			return f.sonnetText(scene)
+		})
+	}
+	return nodes, nil
+}
+
+// years lists a directory per year of the Top Ten lists, each holding the
+// lists of that year as <n>.txt, n being the list's position in the
+// collection counting from 1
+func (f *FS) years() ([]node, error) {
+	byYear := map[int][]int{}
+	var years []int
+	for i := range f.src.TopTen.GetListCount() {
+		list, err := f.src.TopTen.GetList(i)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byYear[list.Year]; !ok {
+			years = append(years, list.Year)
+		}
+		byYear[list.Year] = append(byYear[list.Year], i)
+	}
+
+	nodes := make([]node, len(years))
+	for i, year := range years {
+		indexes := byYear[year]
+		nodes[i] = dirNode(strconv.Itoa(year), func() ([]node, error) {
+			lists := make([]node, len(indexes))
+			for j, index := range indexes {
+				lists[j] = fileNode(fmt.Sprintf("%d.txt", index+1), func() ([]byte, error) {
+					return f.listText(index)
+				})
+			}
+			return lists, nil
+		})
+	}
+	return nodes, nil
+}
+
+// workJSON encodes a work as the HTTP API does
+func (f *FS) workJSON(workID string) ([]byte, error) {
+	work, err := f.src.Library.GetWork(f.ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(work, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode work: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// workText renders a whole work, scene by scene, as plain text or Markdown
+func (f *FS) workText(workID string, markdown bool) ([]byte, error) {
+	work, err := f.src.Library.GetWork(f.ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := f.src.Library.ListScenes(f.ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if markdown {
+		fmt.Fprintf(&b, "# %s\n\n", work.Title)
+		if work.LongTitle != "" && work.LongTitle != work.Title {
+			fmt.Fprintf(&b, "*%s*\n\n", work.LongTitle)
+		}
+		fmt.Fprintf(&b, "%s, %d\n", work.GenreName, work.Date)
+	} else {
+		fmt.Fprintf(&b, "%s\n", strings.ToUpper(work.Title))
+		if work.LongTitle != "" && work.LongTitle != work.Title {
+			fmt.Fprintf(&b, "%s\n", work.LongTitle)
+		}
+		fmt.Fprintf(&b, "%s, %d\n", work.GenreName, work.Date)
+	}
+
+	for _, scene := range scenes {
+		passages, err := f.src.Library.GetScene(f.ctx, workID, scene.Act, scene.Scene)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("\n\n")
+		if markdown {
+			writeSceneMarkdown(&b, work.GenreType, scene, passages)
+		} else {
+			writeSceneText(&b, work.GenreType, scene, passages)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// sceneText renders one scene as plain text
+func (f *FS) sceneText(workID string, scene shakespert.Scene) ([]byte, error) {
+	work, err := f.src.Library.GetWork(f.ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	passages, err := f.src.Library.GetScene(f.ctx, workID, scene.Act, scene.Scene)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n", strings.ToUpper(work.Title))
+	writeSceneText(&b, work.GenreType, scene, passages)
+	return b.Bytes(), nil
+}
+
+// sonnetText renders one sonnet as plain text
+func (f *FS) sonnetText(scene shakespert.Scene) ([]byte, error) {
+	passages, err := f.src.Library.GetScene(f.ctx, sonnetsWorkID, scene.Act, scene.Scene)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\n", sceneLabel(genreSonnet, scene))
+	for _, p := range passages {
+		fmt.Fprintf(&b, "%s\n", p.Text)
+	}
+	return b.Bytes(), nil
+}
+
+// listText renders a Top Ten list as plain text
+func (f *FS) listText(index int) ([]byte, error) {
+	list, err := f.src.TopTen.GetList(index)
+	if err != nil {
+		return nil, err
+	}
+	return formatList(list), nil
+}
+
+func formatList(list *topten.TopTenList) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n", list.Title)
+	if list.Date != "" {
+		fmt.Fprintf(&b, "%s\n", list.Date)
+	}
+	b.WriteString("\n")
+	for _, item := range list.NumberedItems() {
+		fmt.Fprintf(&b, "%3s. %s\n", item.Number, item.Text)
+	}
+	return b.Bytes()
+}
+
+// Genre codes with their own scene labels
+const (
+	genreSonnet = "s"
+	genrePoem   = "p"
+)
+
+// sceneLabel names a scene the way the work divides itself
+func sceneLabel(genre string, scene shakespert.Scene) string {
+	switch genre {
+	case genreSonnet:
+		return fmt.Sprintf("Sonnet %d", scene.Scene)
+	case genrePoem:
+		return fmt.Sprintf("Part %d", scene.Scene)
+	default:
+		return fmt.Sprintf("Act %d, Scene %d", scene.Act, scene.Scene)
+	}
+}
+
+// narrated reports whether passages without a speaker are the text itself
+// rather than stage directions, as in the sonnets and poems
+func narrated(genre string) bool {
+	return genre == genreSonnet || genre == genrePoem
+}
+
+// writeSceneText writes a scene heading followed by its passages: speeches
+// under the speaker's name and stage directions in brackets
+func writeSceneText(b *bytes.Buffer, genre string, scene shakespert.Scene, passages []shakespert.Passage) {
+	fmt.Fprintf(b, "%s\n", strings.ToUpper(sceneLabel(genre, scene)))
+	if scene.Description != "" {
+		fmt.Fprintf(b, "%s\n", scene.Description)
+	}
+	for _, p := range passages {
+		b.WriteString("\n")
+		switch {
+		case p.StageDirection() && narrated(genre):
+			fmt.Fprintf(b, "%s\n", p.Text)
+			continue
+		case p.StageDirection():
+			fmt.Fprintf(b, "  [%s]\n", p.Text)
+			continue
+		}
+		fmt.Fprintf(b, "%s\n", strings.ToUpper(p.Character))
+		for _, line := range strings.Split(p.Text, "\n") {
+			fmt.Fprintf(b, "  %s\n", line)
+		}
+	}
+}
+
+// writeSceneMarkdown writes a scene as a Markdown section. Lines of verse
+// end with hard breaks.
+func writeSceneMarkdown(b *bytes.Buffer, genre string, scene shakespert.Scene, passages []shakespert.Passage) {
+	fmt.Fprintf(b, "## %s\n", sceneLabel(genre, scene))
+	if scene.Description != "" {
+		fmt.Fprintf(b, "\n*%s*\n", scene.Description)
+	}
+	for _, p := range passages {
+		b.WriteString("\n")
+		switch {
+		case p.StageDirection() && narrated(genre):
+			fmt.Fprintf(b, "%s\n", strings.ReplaceAll(p.Text, "\n", "  \n"))
+			continue
+		case p.StageDirection():
+			fmt.Fprintf(b, "_%s_\n", p.Text)
+			continue
+		}
+		fmt.Fprintf(b, "**%s.** %s\n", p.Character, strings.ReplaceAll(p.Text, "\n", "  \n"))
+	}
+}
+
+// isNotFound reports whether err is a missing work
+func isNotFound(err error) bool {
+	return errors.Is(err, shakespert.ErrWorkNotFound)
+}
@@ -0,0 +1,311 @@
+// Package textfs is a read-only virtual filesystem of the works, sonnets and
+// Top Ten lists, generated on the fly from the services. It backs the SCP
+// and SFTP exports of the SSH server:
+//
+//	works/<id>.txt, works/<id>.md, works/<id>.json
+//	works/<id>/<act>-<scene>.txt
+//	topten/<year>/<n>.txt
+//	sonnets/<n>.txt
+package textfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+)
+
+// Library is the shakespert service as used by the filesystem
+type Library interface {
+	ListWorks(ctx context.Context) ([]shakespert.WorkSummary, error)
+	GetWork(ctx context.Context, workID string) (*shakespert.WorkDetail, error)
+	ListScenes(ctx context.Context, workID string) ([]shakespert.Scene, error)
+	GetScene(ctx context.Context, workID string, act, scene int64) ([]shakespert.Passage, error)
+}
+
+// TopTen is the topten service as used by the filesystem
+type TopTen interface {
+	GetList(index int) (*topten.TopTenList, error)
+	GetListCount() int
+}
+
+// Sources are the data the filesystem exposes. A nil source leaves its
+// directories out.
+type Sources struct {
+	Library Library
+	TopTen  TopTen
+}
+
+// sonnetsWorkID is the work whose scenes are the sonnets
+const sonnetsWorkID = "sonnets"
+
+// FS is the filesystem. Files are generated when opened, so their contents
+// always match the services.
+type FS struct {
+	ctx     context.Context
+	src     Sources
+	modTime time.Time
+}
+
+var (
+	_ fs.FS        = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+	_ fs.StatFS    = (*FS)(nil)
+)
+
+// New creates the filesystem. Queries run with ctx, which should end with
+// the session, and every entry reports modTime.
+func New(ctx context.Context, src Sources, modTime time.Time) *FS {
+	return &FS{ctx: ctx, src: src, modTime: modTime}
+}
+
+// node is a file or directory of the filesystem. Directories list their
+// children and files generate their contents, both on demand.
+type node struct {
+	name     string
+	children func() ([]node, error)
+	content  func() ([]byte, error)
+}
+
+func (n node) isDir() bool {
+	return n.children != nil
+}
+
+func dirNode(name string, children func() ([]node, error)) node {
+	return node{name: name, children: children}
+}
+
+func fileNode(name string, content func() ([]byte, error)) node {
+	return node{name: name, content: content}
+}
+
+// root lists the top-level directories of the available sources
+func (f *FS) root() node {
+	return dirNode(".", func() ([]node, error) {
+		var nodes []node
+		if f.src.Library != nil {
+			nodes = append(nodes, dirNode("works", f.works), dirNode("sonnets", f.sonnets))
+		}
+		if f.src.TopTen != nil {
+			nodes = append(nodes, dirNode("topten", f.years))
+		}
+		return nodes, nil
+	})
+}
+
+// lookup walks from the root to the node at name
+func (f *FS) lookup(op, name string) (node, error) {
+	if !fs.ValidPath(name) {
+		return node{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	current := f.root()
+	if name == "." {
+		return current, nil
+	}
+	for _, part := range strings.Split(name, "/") {
+		if !current.isDir() {
+			return node{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		children, err := current.children()
+		if err != nil {
+			return node{}, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		found := false
+		for _, child := range children {
+			if child.name == part {
+				current, found = child, true
+				break
+			}
+		}
+		if !found {
+			return node{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return current, nil
+}
+
+// Open opens the named file or directory
+func (f *FS) Open(name string) (fs.File, error) {
+	n, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.isDir() {
+		return &dir{fs: f, node: n}, nil
+	}
+	data, err := n.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &file{info: f.info(n.name, int64(len(data)), false), data: data}, nil
+}
+
+// ReadDir lists the named directory, sorted by name
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	n, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !n.isDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	return f.entries(name, n)
+}
+
+// Stat describes the named file or directory. The size of a file is only
+// known once it has been generated.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	file, err := f.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return file.Stat()
+}
+
+var errNotDir = errors.New("not a directory")
+
+// entries lists the children of the directory n at name
+func (f *FS) entries(name string, n node) ([]fs.DirEntry, error) {
+	children, err := n.children()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	slices.SortFunc(children, func(a, b node) int { return strings.Compare(a.name, b.name) })
+	entries := make([]fs.DirEntry, len(children))
+	for i, child := range children {
+		entries[i] = &dirEntry{fs: f, path: path.Join(name, child.name), node: child}
+	}
+	return entries, nil
+}
+
+func (f *FS) info(name string, size int64, isDir bool) *fileInfo {
+	return &fileInfo{name: path.Base(name), size: size, isDir: isDir, modTime: f.modTime}
+}
+
+// file is an open generated file
+type file struct {
+	info   *fileInfo
+	data   []byte
+	offset int64
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+func (f *file) Read(p []byte) (int, error) {
+	if f.offset >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+// ReadAt lets clients read files out of order, as SFTP does
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// dir is an open directory
+type dir struct {
+	fs      *FS
+	node    node
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.fs.info(d.node.name, 0, true), nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.node.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fs.entries(d.node.name, d.node)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// dirEntry is a child of a directory. Its size is generated on Info only.
+type dirEntry struct {
+	fs   *FS
+	path string
+	node node
+}
+
+func (e *dirEntry) Name() string { return e.node.name }
+func (e *dirEntry) IsDir() bool  { return e.node.isDir() }
+
+func (e *dirEntry) Type() fs.FileMode {
+	if e.node.isDir() {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+func (e *dirEntry) Info() (fs.FileInfo, error) {
+	if e.node.isDir() {
+		return e.fs.info(e.node.name, 0, true), nil
+	}
+	data, err := e.node.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: e.path, Err: err}
+	}
+	return e.fs.info(e.node.name, int64(len(data)), false), nil
+}
+
+// fileInfo describes a file or directory. Everything is read-only.
+type fileInfo struct {
+	name    string
+	size    int64
+	isDir   bool
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.isDir }
+func (i *fileInfo) Sys() any           { return nil }
+
+func (i *fileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
@@ -0,0 +1,171 @@
+package textfs_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+	"prospero/internal/textfs"
+)
+
+// library is an in-memory shakespert service
+type library struct {
+	works  []shakespert.WorkSummary
+	scenes map[string][]shakespert.Scene
+	text   map[string][]shakespert.Passage // by "work act.scene"
+}
+
+func newLibrary() *library {
+	return &library{
+		works: []shakespert.WorkSummary{
+			{WorkID: "hamlet", Title: "Hamlet", Date: 1600, GenreType: "t", GenreName: "Tragedy"},
+			{WorkID: "sonnets", Title: "Sonnets", Date: 1609, GenreType: "s", GenreName: "Sonnet"},
+		},
+		scenes: map[string][]shakespert.Scene{
+			"hamlet":  {{Act: 1, Scene: 1, Description: "Elsinore. A platform before the castle."}, {Act: 3, Scene: 1}},
+			"sonnets": {{Act: 1, Scene: 18}},
+		},
+		text: map[string][]shakespert.Passage{
+			"hamlet 1.1": {
+				{Number: 1, CharacterID: "xxx", Text: "Enter BERNARDO and FRANCISCO"},
+				{Number: 2, CharacterID: "bernardo", Character: "Bernardo", Text: "Who's there?"},
+			},
+			"hamlet 3.1": {
+				{Number: 3, CharacterID: "hamlet", Character: "Hamlet", Text: "To be, or not to be:\nthat is the question"},
+			},
+			"sonnets 1.18": {
+				{Number: 1, CharacterID: "xxx", Text: "Shall I compare thee to a summer's day?\nThou art more lovely and more temperate"},
+			},
+		},
+	}
+}
+
+func (l *library) ListWorks(ctx context.Context) ([]shakespert.WorkSummary, error) {
+	return l.works, nil
+}
+
+func (l *library) GetWork(ctx context.Context, workID string) (*shakespert.WorkDetail, error) {
+	for _, w := range l.works {
+		if w.WorkID == workID {
+			return &shakespert.WorkDetail{
+				WorkID: w.WorkID, Title: w.Title, Date: w.Date, GenreType: w.GenreType, GenreName: w.GenreName,
+			}, nil
+		}
+	}
+	return nil, shakespert.ErrWorkNotFound
+}
+
+func (l *library) ListScenes(ctx context.Context, workID string) ([]shakespert.Scene, error) {
+	scenes, ok := l.scenes[workID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", shakespert.ErrWorkNotFound, workID)
+	}
+	return scenes, nil
+}
+
+func (l *library) GetScene(ctx context.Context, workID string, act, scene int64) ([]shakespert.Passage, error) {
+	passages, ok := l.text[fmt.Sprintf("%s %d.%d", workID, act, scene)]
+	if !ok {
+		return nil, shakespert.ErrSceneNotFound
+	}
+	return passages, nil
+}
+
+// lists is an in-memory topten service
+type lists []topten.TopTenList
+
+func (l lists) GetList(index int) (*topten.TopTenList, error) {
+	if index < 0 || index >= len(l) {
+		return nil, fmt.Errorf("list %d not found", index)
+	}
+	return &l[index], nil
+}
+
+func (l lists) GetListCount() int {
+	return len(l)
+}
+
+func newFS() *textfs.FS {
+	return textfs.New(context.Background(), textfs.Sources{
+		Library: newLibrary(),
+		TopTen: lists{
+			{Title: "Top Ten Signs Your Island Is Enchanted", Date: "May 1, 1995", Year: 1995, Items: []string{"Spirits", "Monsters"}},
+			{Title: "Top Ten Excuses for Shipwrecks", Year: 1996, Items: []string{"Storm"}},
+			{Title: "Top Ten Uses for a Magic Staff", Year: 1995, Items: []string{"Walking"}},
+		},
+	}, time.Unix(1611, 0))
+}
+
+func TestFS(t *testing.T) {
+	t.Run("should behave as a filesystem", func(t *testing.T) {
+		require.NoError(t, fstest.TestFS(newFS(),
+			"works/hamlet.txt", "works/hamlet.md", "works/hamlet.json", "works/hamlet/1-1.txt",
+			"sonnets/18.txt", "topten/1995/1.txt", "topten/1995/3.txt", "topten/1996/2.txt"))
+	})
+
+	t.Run("should render scenes as plain text", func(t *testing.T) {
+		data, err := fs.ReadFile(newFS(), "works/hamlet/1-1.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "HAMLET\nACT 1, SCENE 1\nElsinore. A platform before the castle.\n\n"+
+			"  [Enter BERNARDO and FRANCISCO]\n\nBERNARDO\n  Who's there?\n", string(data))
+	})
+
+	t.Run("should render whole works as Markdown", func(t *testing.T) {
+		data, err := fs.ReadFile(newFS(), "works/hamlet.md")
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "# Hamlet\n\nTragedy, 1600\n")
+		assert.Contains(t, string(data), "## Act 3, Scene 1\n\n**Hamlet.** To be, or not to be:  \nthat is the question\n")
+		assert.Contains(t, string(data), "_Enter BERNARDO and FRANCISCO_")
+	})
+
+	t.Run("should encode works as the API does", func(t *testing.T) {
+		data, err := fs.ReadFile(newFS(), "works/hamlet.json")
+		require.NoError(t, err)
+		var work shakespert.WorkDetail
+		require.NoError(t, json.Unmarshal(data, &work))
+		assert.Equal(t, "Hamlet", work.Title)
+	})
+
+	t.Run("should render sonnets as verse", func(t *testing.T) {
+		data, err := fs.ReadFile(newFS(), "sonnets/18.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "Sonnet 18\n\nShall I compare thee to a summer's day?\nThou art more lovely and more temperate\n", string(data))
+	})
+
+	t.Run("should group Top Ten lists by year", func(t *testing.T) {
+		entries, err := fs.ReadDir(newFS(), "topten/1995")
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "1.txt", entries[0].Name())
+		assert.Equal(t, "3.txt", entries[1].Name())
+
+		data, err := fs.ReadFile(newFS(), "topten/1995/1.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "Top Ten Signs Your Island Is Enchanted\nMay 1, 1995\n\n 10. Spirits\n  9. Monsters\n", string(data))
+	})
+
+	t.Run("should report missing files", func(t *testing.T) {
+		_, err := newFS().Open("works/lear.txt")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = newFS().Open("works/hamlet.txt/1-1.txt")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = newFS().Open("/works")
+		assert.ErrorIs(t, err, fs.ErrInvalid)
+	})
+
+	t.Run("should leave out missing sources", func(t *testing.T) {
+		entries, err := fs.ReadDir(textfs.New(context.Background(), textfs.Sources{TopTen: lists{}}, time.Now()), ".")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "topten", entries[0].Name())
+	})
+}
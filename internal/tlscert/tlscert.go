@@ -0,0 +1,133 @@
+// Package tlscert loads and generates the certificates served by the HTTP
+// server
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// SelfSignedValidity is how long generated certificates are valid for
+const SelfSignedValidity = 90 * 24 * time.Hour
+
+// SelfSigned generates an ECDSA P-256 certificate for hosts, which may be
+// DNS names or IP addresses. The certificate is its own CA, so clients must
+// be told to trust it explicitly (curl -k, or its fingerprint).
+func SelfSigned(hosts []string, now time.Time) (tls.Certificate, error) {
+	if len(hosts) == 0 {
+		return tls.Certificate{}, fmt.Errorf("at least one host name is required")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Prospero development"},
+			CommonName:   hosts[0],
+		},
+		// Allow for clock skew between client and server
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(SelfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// Parse loads a certificate chain and private key from PEM data and checks
+// that the leaf has not expired as of now
+func Parse(certPEM, keyPEM []byte, now time.Time) (tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+	}
+	if now.After(cert.Leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("certificate for %s expired on %s",
+			Names(cert), cert.Leaf.NotAfter.Format(time.DateOnly))
+	}
+	return cert, nil
+}
+
+// Names returns the host names and addresses a certificate is valid for
+func Names(cert tls.Certificate) string {
+	if cert.Leaf == nil {
+		return ""
+	}
+	names := append([]string(nil), cert.Leaf.DNSNames...)
+	for _, ip := range cert.Leaf.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 {
+		names = append(names, cert.Leaf.Subject.CommonName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the leaf certificate as
+// colon-separated hex, the form browsers display
+func Fingerprint(cert tls.Certificate) string {
+	if len(cert.Certificate) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(cert.Certificate[0])
+	encoded := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += 2 {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+		b.WriteString(encoded[i : i+2])
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package tlscert_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/tlscert"
+)
+
+func TestSelfSigned(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should cover DNS names and IP addresses", func(t *testing.T) {
+		cert, err := tlscert.SelfSigned([]string{"localhost", "127.0.0.1", "::1"}, now)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"localhost"}, cert.Leaf.DNSNames)
+		require.Len(t, cert.Leaf.IPAddresses, 2)
+		assert.True(t, cert.Leaf.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")))
+		assert.Equal(t, "localhost, 127.0.0.1, ::1", tlscert.Names(cert))
+		assert.NoError(t, cert.Leaf.VerifyHostname("localhost"))
+	})
+
+	t.Run("should be valid from slightly before now", func(t *testing.T) {
+		cert, err := tlscert.SelfSigned([]string{"localhost"}, now)
+		require.NoError(t, err)
+
+		assert.True(t, cert.Leaf.NotBefore.Before(now))
+		assert.Equal(t, now.Add(tlscert.SelfSignedValidity), cert.Leaf.NotAfter)
+	})
+
+	t.Run("should require a host name", func(t *testing.T) {
+		_, err := tlscert.SelfSigned(nil, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	now := time.Now()
+	cert, err := tlscert.SelfSigned([]string{"example.com"}, now)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	t.Run("should load a matching key pair", func(t *testing.T) {
+		parsed, err := tlscert.Parse(certPEM, keyPEM, now)
+		require.NoError(t, err)
+
+		assert.Equal(t, "example.com", tlscert.Names(parsed))
+		assert.Equal(t, tlscert.Fingerprint(cert), tlscert.Fingerprint(parsed))
+	})
+
+	t.Run("should reject a mismatched key", func(t *testing.T) {
+		other, err := tlscert.SelfSigned([]string{"example.com"}, now)
+		require.NoError(t, err)
+		otherDER, err := x509.MarshalPKCS8PrivateKey(other.PrivateKey)
+		require.NoError(t, err)
+
+		_, err = tlscert.Parse(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherDER}), now)
+		assert.ErrorContains(t, err, "failed to load key pair")
+	})
+
+	t.Run("should reject an expired certificate", func(t *testing.T) {
+		_, err := tlscert.Parse(certPEM, keyPEM, now.Add(tlscert.SelfSignedValidity+time.Hour))
+		assert.ErrorContains(t, err, "expired")
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Run("should format the SHA-256 as colon-separated hex", func(t *testing.T) {
+		cert, err := tlscert.SelfSigned([]string{"localhost"}, time.Now())
+		require.NoError(t, err)
+
+		assert.Regexp(t, `^([0-9A-F]{2}:){31}[0-9A-F]{2}$`, tlscert.Fingerprint(cert))
+	})
+}
@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"prospero/internal/features/shakespert"
+)
+
+// work identifies the work a scene belongs to
+type work struct {
+	ID    string
+	Title string
+	Genre string
+}
+
+// Genre types with their own scene labels
+const (
+	genrePoem   = "p"
+	genreSonnet = "s"
+)
+
+// sceneLabel names a scene the way its genre numbers them
+func sceneLabel(w work, scene shakespert.Scene) string {
+	switch w.Genre {
+	case genreSonnet:
+		return fmt.Sprintf("Sonnet %d", scene.Scene)
+	case genrePoem:
+		return fmt.Sprintf("Part %d", scene.Scene)
+	default:
+		return fmt.Sprintf("Act %d, Scene %d", scene.Act, scene.Scene)
+	}
+}
+
+// genresView lists the genres, preceded by an entry for every work
+type genresView struct {
+	env    *env
+	genres []shakespert.Genre
+	list   list
+}
+
+func openGenres(e *env) (view, error) {
+	genres, err := e.src.Library.ListGenres(e.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &genresView{env: e, genres: genres}
+	v.list.items = append(v.list.items, "All works")
+	for _, genre := range genres {
+		v.list.items = append(v.list.items, genre.Genrename.String)
+	}
+	return v, nil
+}
+
+func (v *genresView) title() string {
+	return "Works"
+}
+
+func (v *genresView) keys() string {
+	return "↑/↓ move • enter select"
+}
+
+func (v *genresView) typing() bool {
+	return false
+}
+
+func (v *genresView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.list.move(msg, v.env.height) {
+		return v, nil
+	}
+	if msg.Type != tea.KeyEnter {
+		return v, nil
+	}
+
+	if v.list.cursor == 0 {
+		return v, push(func() (view, error) { return openWorks(v.env, "", "All works") })
+	}
+	genre := v.genres[v.list.cursor-1]
+	return v, push(func() (view, error) {
+		return openWorks(v.env, genre.Genretype, genre.Genrename.String)
+	})
+}
+
+func (v *genresView) render() string {
+	return v.list.render(v.env.styles, v.env.width, v.env.height)
+}
+
+// worksView lists the works of a genre, or every work
+type worksView struct {
+	env   *env
+	name  string
+	works []shakespert.WorkSummary
+	list  list
+}
+
+// openWorks lists the works of genreType, or every work when it is empty
+func openWorks(e *env, genreType, name string) (view, error) {
+	var works []shakespert.WorkSummary
+	var err error
+	if genreType == "" {
+		works, err = e.src.Library.ListWorks(e.ctx)
+	} else {
+		works, err = e.src.Library.GetWorksByGenre(e.ctx, genreType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v := &worksView{env: e, name: name, works: works}
+	for _, w := range works {
+		v.list.items = append(v.list.items, fmt.Sprintf("%-36s %4d  %s",
+			w.Title, w.Date, e.styles.dim.Render(w.GenreName)))
+	}
+	return v, nil
+}
+
+func (v *worksView) title() string {
+	return v.name
+}
+
+func (v *worksView) keys() string {
+	return "↑/↓ move • enter scenes"
+}
+
+func (v *worksView) typing() bool {
+	return false
+}
+
+func (v *worksView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.list.move(msg, v.env.height) {
+		return v, nil
+	}
+	if msg.Type != tea.KeyEnter || len(v.works) == 0 {
+		return v, nil
+	}
+
+	summary := v.works[v.list.cursor]
+	w := work{ID: summary.WorkID, Title: summary.Title, Genre: summary.GenreType}
+	return v, push(func() (view, error) { return openScenes(v.env, w) })
+}
+
+func (v *worksView) render() string {
+	return v.list.render(v.env.styles, v.env.width, v.env.height)
+}
+
+// scenesView lists the scenes of a work
+type scenesView struct {
+	env    *env
+	work   work
+	scenes []shakespert.Scene
+	list   list
+}
+
+func openScenes(e *env, w work) (view, error) {
+	scenes, err := e.src.Library.ListScenes(e.ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &scenesView{env: e, work: w, scenes: scenes}
+	for _, scene := range scenes {
+		v.list.items = append(v.list.items, fmt.Sprintf("%-18s %s",
+			sceneLabel(w, scene), e.styles.dim.Render(scene.Description)))
+	}
+	return v, nil
+}
+
+func (v *scenesView) title() string {
+	return v.work.Title
+}
+
+func (v *scenesView) keys() string {
+	return "↑/↓ move • enter read"
+}
+
+func (v *scenesView) typing() bool {
+	return false
+}
+
+func (v *scenesView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.list.move(msg, v.env.height) {
+		return v, nil
+	}
+	if msg.Type != tea.KeyEnter {
+		return v, nil
+	}
+
+	index := v.list.cursor
+	return v, push(func() (view, error) { return openScene(v.env, v.work, v.scenes, index, 0) })
+}
+
+func (v *scenesView) render() string {
+	return v.list.render(v.env.styles, v.env.width, v.env.height)
+}
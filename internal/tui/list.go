@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// list is a scrolling selection of one-line items
+type list struct {
+	items  []string
+	cursor int
+}
+
+// move applies a navigation key and reports whether msg was one. page is
+// the number of items pgup and pgdown move by.
+func (l *list) move(msg tea.KeyMsg, page int) bool {
+	switch msg.String() {
+	case "up", "k":
+		l.cursor--
+	case "down", "j":
+		l.cursor++
+	case "pgup":
+		l.cursor -= page
+	case "pgdown":
+		l.cursor += page
+	case "home", "g":
+		l.cursor = 0
+	case "end", "G":
+		l.cursor = len(l.items) - 1
+	default:
+		return false
+	}
+	l.cursor = max(min(l.cursor, len(l.items)-1), 0)
+	return true
+}
+
+// render shows the items around the cursor in height lines of width
+func (l *list) render(s styles, width, height int) string {
+	if len(l.items) == 0 {
+		return s.dim.Render("Nothing here.")
+	}
+
+	start := 0
+	if l.cursor >= height {
+		start = l.cursor - height + 1
+	}
+	end := min(start+height, len(l.items))
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		item := ansi.Truncate(l.items[i], max(width-2, 1), "…")
+		if i == l.cursor {
+			lines = append(lines, s.selected.Render("› "+item))
+		} else {
+			lines = append(lines, "  "+item)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
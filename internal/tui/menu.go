@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// menuEntry is an item of the main menu
+type menuEntry struct {
+	label       string
+	description string
+	open        func() tea.Cmd
+}
+
+// menuView is the first screen, offering the parts of the application the
+// sources allow
+type menuView struct {
+	env     *env
+	entries []menuEntry
+	list    list
+}
+
+func newMenuView(e *env) *menuView {
+	var entries []menuEntry
+	if e.src.Library != nil {
+		entries = append(entries,
+			menuEntry{
+				label:       "Browse works",
+				description: "Shakespeare's works by genre",
+				open:        func() tea.Cmd { return push(func() (view, error) { return openGenres(e) }) },
+			},
+			menuEntry{
+				label:       "Search",
+				description: "Find passages containing a phrase",
+				open:        func() tea.Cmd { return push(func() (view, error) { return newSearchView(e), nil }) },
+			},
+		)
+	}
+	if e.src.TopTen != nil {
+		entries = append(entries, menuEntry{
+			label:       "Top Ten",
+			description: "David Letterman's Top 10 lists",
+			open:        func() tea.Cmd { return push(func() (view, error) { return openTopTen(e) }) },
+		})
+	}
+	entries = append(entries, menuEntry{
+		label:       "Quit",
+		description: "Close the connection",
+		open:        func() tea.Cmd { return tea.Quit },
+	})
+
+	v := &menuView{env: e, entries: entries}
+	for _, entry := range entries {
+		v.list.items = append(v.list.items,
+			fmt.Sprintf("%-14s %s", entry.label, e.styles.dim.Render(entry.description)))
+	}
+	return v
+}
+
+func (v *menuView) title() string {
+	return "🎩 Prospero"
+}
+
+func (v *menuView) keys() string {
+	return "↑/↓ move • enter select"
+}
+
+func (v *menuView) typing() bool {
+	return false
+}
+
+func (v *menuView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.list.move(msg, v.env.height) {
+		return v, nil
+	}
+	if msg.Type == tea.KeyEnter {
+		return v, v.entries[v.list.cursor].open()
+	}
+	return v, nil
+}
+
+func (v *menuView) render() string {
+	s := v.env.styles
+	return s.heading.Render("Welcome to Prospero") + "\n\n" +
+		v.list.render(s, v.env.width, v.env.height-2)
+}
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"prospero/internal/features/shakespert"
+)
+
+// readerView pages through the text of a scene
+type readerView struct {
+	env      *env
+	work     work
+	scenes   []shakespert.Scene
+	index    int
+	passages []shakespert.Passage
+
+	// mark is the number of the passage a search opened the scene at, or 0
+	mark int64
+
+	// standalone is set when the scene was not opened from the work's scene
+	// list, so the breadcrumb does not name the work
+	standalone bool
+
+	// offset is the first line shown
+	offset int
+
+	// lines is the scene wrapped to wrapWidth, and anchors maps passage
+	// numbers to their first line
+	lines     []string
+	anchors   map[int64]int
+	wrapWidth int
+}
+
+// openScene reads the scene at index of the work's scenes, scrolled to the
+// passage numbered mark when it is not 0
+func openScene(e *env, w work, scenes []shakespert.Scene, index int, mark int64) (*readerView, error) {
+	scene := scenes[index]
+	passages, err := e.src.Library.GetScene(e.ctx, w.ID, scene.Act, scene.Scene)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &readerView{
+		env:      e,
+		work:     w,
+		scenes:   scenes,
+		index:    index,
+		passages: passages,
+		mark:     mark,
+	}
+	v.layout()
+	if mark != 0 {
+		v.offset = v.anchors[mark]
+		v.clamp()
+	}
+	return v, nil
+}
+
+// openPassage reads the scene containing a search result, scrolled to it
+func openPassage(e *env, result shakespert.SearchResult) (view, error) {
+	detail, err := e.src.Library.GetWork(e.ctx, result.WorkID)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := e.src.Library.ListScenes(e.ctx, result.WorkID)
+	if err != nil {
+		return nil, err
+	}
+
+	w := work{ID: detail.WorkID, Title: detail.Title, Genre: detail.GenreType}
+	for i, scene := range scenes {
+		if scene.Act == result.Act && scene.Scene == result.Scene {
+			v, err := openScene(e, w, scenes, i, result.Number)
+			if err != nil {
+				return nil, err
+			}
+			v.standalone = true
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s act %d scene %d", shakespert.ErrSceneNotFound, w.ID, result.Act, result.Scene)
+}
+
+func (v *readerView) title() string {
+	label := sceneLabel(v.work, v.scenes[v.index])
+	if v.standalone {
+		return v.work.Title + " › " + label
+	}
+	return label
+}
+
+func (v *readerView) keys() string {
+	return "↑/↓ scroll • space/b page • n/p next/previous scene"
+}
+
+func (v *readerView) typing() bool {
+	return false
+}
+
+// page is the number of text lines shown, leaving room for the status line
+func (v *readerView) page() int {
+	return max(v.env.height-2, 1)
+}
+
+func (v *readerView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	v.layout()
+
+	switch msg.String() {
+	case "up", "k":
+		v.offset--
+	case "down", "j":
+		v.offset++
+	case " ", "pgdown", "f":
+		v.offset += v.page()
+	case "b", "pgup":
+		v.offset -= v.page()
+	case "home", "g":
+		v.offset = 0
+	case "end", "G":
+		v.offset = len(v.lines)
+	case "n", "]":
+		if v.index+1 < len(v.scenes) {
+			return v, v.turn(v.index + 1)
+		}
+	case "p", "[":
+		if v.index > 0 {
+			return v, v.turn(v.index - 1)
+		}
+	}
+	v.clamp()
+	return v, nil
+}
+
+// turn returns a command replacing the view with the scene at index
+func (v *readerView) turn(index int) tea.Cmd {
+	return replace(func() (view, error) {
+		next, err := openScene(v.env, v.work, v.scenes, index, 0)
+		if err != nil {
+			return nil, err
+		}
+		next.standalone = v.standalone
+		return next, nil
+	})
+}
+
+// clamp keeps the offset within the scene
+func (v *readerView) clamp() {
+	v.offset = max(min(v.offset, len(v.lines)-v.page()), 0)
+}
+
+// layout wraps the scene to the width of the terminal when it changed
+func (v *readerView) layout() {
+	if v.wrapWidth == v.env.width && v.lines != nil {
+		return
+	}
+	s := v.env.styles
+	width := max(v.env.width-4, 20)
+
+	v.wrapWidth = v.env.width
+	v.lines = nil
+	v.anchors = make(map[int64]int, len(v.passages))
+	for _, p := range v.passages {
+		v.anchors[p.Number] = len(v.lines)
+
+		style := s.direction
+		if !p.StageDirection() {
+			v.lines = append(v.lines, s.character.Render(p.Character))
+			style = s.text
+		}
+		if p.Number == v.mark {
+			style = s.mark
+		}
+		for _, line := range strings.Split(ansi.Wrap(p.Text, width, ""), "\n") {
+			v.lines = append(v.lines, "  "+style.Render(line))
+		}
+		v.lines = append(v.lines, "")
+	}
+}
+
+func (v *readerView) render() string {
+	v.layout()
+	v.clamp()
+
+	end := min(v.offset+v.page(), len(v.lines))
+	text := strings.Join(v.lines[v.offset:end], "\n")
+
+	progress := 100
+	if len(v.lines) > v.page() {
+		progress = end * 100 / len(v.lines)
+	}
+	status := fmt.Sprintf("%s  %d%%", v.scenes[v.index].Description, progress)
+
+	return text + "\n\n" + v.env.styles.dim.Render(ansi.Truncate(status, v.env.width, "…"))
+}
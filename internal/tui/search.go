@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"prospero/internal/features/shakespert"
+)
+
+const (
+	// minSearchLength keeps searches from matching most of the text
+	minSearchLength = 3
+
+	// searchLimit is the maximum number of passages a search returns
+	searchLimit = 100
+)
+
+// searchView is a search box over the text of every work, with the matching
+// passages listed below it
+type searchView struct {
+	env   *env
+	query []rune
+
+	// searched is the query results were found for, or empty before the
+	// first search
+	searched string
+	results  []shakespert.SearchResult
+	list     list
+
+	// browsing is set while keys move through the results rather than edit
+	// the query
+	browsing bool
+}
+
+func newSearchView(e *env) *searchView {
+	return &searchView{env: e}
+}
+
+func (v *searchView) title() string {
+	return "Search"
+}
+
+func (v *searchView) keys() string {
+	if v.browsing {
+		return "↑/↓ move • enter read • / edit search"
+	}
+	return "enter search • tab results • esc back"
+}
+
+func (v *searchView) typing() bool {
+	return !v.browsing
+}
+
+func (v *searchView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.browsing {
+		return v.updateResults(msg)
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return v, pop
+	case tea.KeyEnter:
+		return v, v.search()
+	case tea.KeyBackspace:
+		if len(v.query) > 0 {
+			v.query = v.query[:len(v.query)-1]
+		}
+	case tea.KeyCtrlU:
+		v.query = nil
+	case tea.KeyTab, tea.KeyDown:
+		v.browsing = len(v.results) > 0
+	case tea.KeySpace:
+		v.query = append(v.query, ' ')
+	case tea.KeyRunes:
+		v.query = append(v.query, msg.Runes...)
+	}
+	return v, nil
+}
+
+func (v *searchView) updateResults(msg tea.KeyMsg) (view, tea.Cmd) {
+	if v.list.move(msg, v.env.height) {
+		return v, nil
+	}
+
+	switch msg.String() {
+	case "/", "tab":
+		v.browsing = false
+	case "enter":
+		result := v.results[v.list.cursor]
+		return v, push(func() (view, error) { return openPassage(v.env, result) })
+	}
+	return v, nil
+}
+
+// search returns a command replacing the view with one showing the results
+// for the query
+func (v *searchView) search() tea.Cmd {
+	query := strings.TrimSpace(string(v.query))
+	if len([]rune(query)) < minSearchLength {
+		return fail(fmt.Errorf("search for at least %d characters", minSearchLength))
+	}
+
+	e := v.env
+	return replace(func() (view, error) {
+		results, err := e.src.Library.Search(e.ctx, query, searchLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		next := &searchView{
+			env:      e,
+			query:    []rune(query),
+			searched: query,
+			results:  results,
+			browsing: len(results) > 0,
+		}
+		for _, r := range results {
+			text := strings.Join(strings.Fields(r.Text), " ")
+			next.list.items = append(next.list.items, fmt.Sprintf("%-24s %-8s %s  %s",
+				r.Title, fmt.Sprintf("%d.%d", r.Act, r.Scene), e.styles.character.Render(r.Character), text))
+		}
+		return next, nil
+	})
+}
+
+func (v *searchView) render() string {
+	s := v.env.styles
+
+	cursor := s.selected.Render("█")
+	if v.browsing {
+		cursor = ""
+	}
+	prompt := s.heading.Render("Search: ") + string(v.query) + cursor
+
+	var status string
+	switch {
+	case v.searched == "":
+		status = fmt.Sprintf("Type at least %d characters and press enter.", minSearchLength)
+	case len(v.results) == 0:
+		status = fmt.Sprintf("No passages contain %q.", v.searched)
+	case len(v.results) == 1:
+		status = fmt.Sprintf("1 passage contains %q:", v.searched)
+	case len(v.results) == searchLimit:
+		status = fmt.Sprintf("First %d passages containing %q:", searchLimit, v.searched)
+	default:
+		status = fmt.Sprintf("%d passages contain %q:", len(v.results), v.searched)
+	}
+
+	out := prompt + "\n\n" + s.dim.Render(status)
+	if len(v.results) > 0 {
+		out += "\n\n" + v.list.render(s, v.env.width, v.env.height-4)
+	}
+	return out
+}
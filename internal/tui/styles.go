@@ -0,0 +1,53 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// styles are the lipgloss styles of the application, bound to the session's
+// renderer so each client gets the colors its terminal supports
+type styles struct {
+	header    lipgloss.Style
+	selected  lipgloss.Style
+	dim       lipgloss.Style
+	heading   lipgloss.Style
+	text      lipgloss.Style
+	character lipgloss.Style
+	direction lipgloss.Style
+	mark      lipgloss.Style
+	number    lipgloss.Style
+	help      lipgloss.Style
+	err       lipgloss.Style
+}
+
+func newStyles(r *lipgloss.Renderer) styles {
+	return styles{
+		header: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA")).
+			Background(lipgloss.Color("#7D56F4")).
+			Padding(0, 1),
+		selected: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#4ECDC4")),
+		dim: r.NewStyle().
+			Foreground(lipgloss.Color("#626262")),
+		heading: r.NewStyle().
+			Bold(true),
+		text: r.NewStyle(),
+		character: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF6B6B")),
+		direction: r.NewStyle().
+			Italic(true).
+			Foreground(lipgloss.Color("#95E1D3")),
+		mark: r.NewStyle().
+			Reverse(true),
+		number: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF6B6B")),
+		help: r.NewStyle().
+			Foreground(lipgloss.Color("#626262")),
+		err: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF6B6B")),
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"prospero/internal/features/topten"
+)
+
+// toptenView is a carousel of the Top Ten lists, starting at a random one
+type toptenView struct {
+	env   *env
+	count int
+	index int
+	list  *topten.TopTenList
+}
+
+func openTopTen(e *env) (view, error) {
+	count := e.src.TopTen.GetListCount()
+	if count == 0 {
+		return nil, topten.ErrNoLists
+	}
+
+	v := &toptenView{env: e, count: count}
+	if err := v.show(rand.IntN(count)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// show moves the carousel to the list at index, wrapping around the ends
+func (v *toptenView) show(index int) error {
+	index = (index%v.count + v.count) % v.count
+	list, err := v.env.src.TopTen.GetList(index)
+	if err != nil {
+		return err
+	}
+	v.index, v.list = index, list
+	return nil
+}
+
+func (v *toptenView) title() string {
+	return "Top Ten"
+}
+
+func (v *toptenView) keys() string {
+	return "←/→ previous/next • r random"
+}
+
+func (v *toptenView) typing() bool {
+	return false
+}
+
+func (v *toptenView) update(msg tea.KeyMsg) (view, tea.Cmd) {
+	var err error
+	switch msg.String() {
+	case "left", "h":
+		err = v.show(v.index - 1)
+	case "right", "l", " ":
+		err = v.show(v.index + 1)
+	case "r":
+		err = v.show(rand.IntN(v.count))
+	}
+	if err != nil {
+		return v, fail(err)
+	}
+	return v, nil
+}
+
+func (v *toptenView) render() string {
+	s := v.env.styles
+	width := max(v.env.width-6, 20)
+
+	var b strings.Builder
+	b.WriteString(s.heading.Render(ansi.Wrap(v.list.Title, v.env.width, "")))
+	b.WriteString("\n")
+	b.WriteString(s.dim.Render(v.list.Date))
+	b.WriteString("\n\n")
+	for _, item := range v.list.NumberedItems() {
+		lines := strings.Split(ansi.Wrap(item.Text, width, ""), "\n")
+		b.WriteString(fmt.Sprintf("%s %s\n", s.number.Render(fmt.Sprintf("%3s.", item.Number)), lines[0]))
+		for _, line := range lines[1:] {
+			b.WriteString("     " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(s.dim.Render(fmt.Sprintf("List %d of %d", v.index+1, v.count)))
+	return b.String()
+}
@@ -0,0 +1,218 @@
+// Package tui is the full-screen terminal application served to SSH sessions
+// that request a PTY without a command. It browses Shakespeare's works by
+// genre, reads them scene by scene, searches their text and flips through
+// the Top Ten lists.
+package tui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+)
+
+// Library is the shakespert service as used by the application
+type Library interface {
+	ListWorks(ctx context.Context) ([]shakespert.WorkSummary, error)
+	GetWork(ctx context.Context, workID string) (*shakespert.WorkDetail, error)
+	ListGenres(ctx context.Context) ([]shakespert.Genre, error)
+	GetWorksByGenre(ctx context.Context, genreType string) ([]shakespert.WorkSummary, error)
+	ListScenes(ctx context.Context, workID string) ([]shakespert.Scene, error)
+	GetScene(ctx context.Context, workID string, act, scene int64) ([]shakespert.Passage, error)
+	Search(ctx context.Context, query string, limit int) ([]shakespert.SearchResult, error)
+}
+
+// TopTen is the topten service as used by the application
+type TopTen interface {
+	GetList(index int) (*topten.TopTenList, error)
+	GetListCount() int
+}
+
+// Sources are the data the application browses. A nil source hides its
+// menu entries.
+type Sources struct {
+	Library Library
+	TopTen  TopTen
+}
+
+// chromeHeight is the number of lines taken by the header and footer
+const chromeHeight = 4
+
+// env is shared by the views of one program
+type env struct {
+	ctx    context.Context
+	src    Sources
+	styles styles
+
+	// width and height are the size of the view area between the header
+	// and the footer
+	width, height int
+}
+
+// view is a screen of the application. Views are kept on a stack: selecting
+// an entry pushes a view and esc pops it.
+type view interface {
+	// title names the view in the header breadcrumb
+	title() string
+
+	// keys is the key help shown in the footer
+	keys() string
+
+	// typing reports whether keys are text input, so that q and esc are
+	// passed to the view rather than quitting or going back
+	typing() bool
+
+	update(msg tea.KeyMsg) (view, tea.Cmd)
+	render() string
+}
+
+// Messages views use to navigate
+type (
+	pushMsg    struct{ view view }
+	replaceMsg struct{ view view }
+	popMsg     struct{}
+	errMsg     struct{ err error }
+)
+
+// push returns a command loading a view and pushing it on the stack
+func push(load func() (view, error)) tea.Cmd {
+	return func() tea.Msg {
+		v, err := load()
+		if err != nil {
+			return errMsg{err}
+		}
+		return pushMsg{v}
+	}
+}
+
+// replace returns a command loading a view in place of the current one
+func replace(load func() (view, error)) tea.Cmd {
+	return func() tea.Msg {
+		v, err := load()
+		if err != nil {
+			return errMsg{err}
+		}
+		return replaceMsg{v}
+	}
+}
+
+// pop is a command returning to the previous view
+func pop() tea.Msg {
+	return popMsg{}
+}
+
+// fail returns a command reporting err in the footer
+func fail(err error) tea.Cmd {
+	return func() tea.Msg {
+		return errMsg{err}
+	}
+}
+
+// Model is the root Bubble Tea model of the application
+type Model struct {
+	env   *env
+	stack []view
+	err   error
+}
+
+var _ tea.Model = (*Model)(nil)
+
+// New creates the application. Queries run with ctx, which should end with
+// the session, and output is styled with renderer.
+func New(ctx context.Context, src Sources, renderer *lipgloss.Renderer) *Model {
+	e := &env{
+		ctx:    ctx,
+		src:    src,
+		styles: newStyles(renderer),
+		width:  80,
+		height: 24 - chromeHeight,
+	}
+	return &Model{env: e, stack: []view{newMenuView(e)}}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.env.width = msg.Width
+		m.env.height = max(msg.Height-chromeHeight, 1)
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	case pushMsg:
+		m.stack = append(m.stack, msg.view)
+	case replaceMsg:
+		m.stack[len(m.stack)-1] = msg.view
+	case popMsg:
+		return m, m.pop()
+	case errMsg:
+		m.err = msg.err
+	}
+	return m, nil
+}
+
+// handleKey applies the global keys and passes the rest to the current view
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	top := m.top()
+	m.err = nil
+
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return tea.Quit
+	case top.typing():
+	case msg.String() == "q":
+		return tea.Quit
+	case msg.Type == tea.KeyEsc:
+		return m.pop()
+	}
+
+	next, cmd := top.update(msg)
+	m.stack[len(m.stack)-1] = next
+	return cmd
+}
+
+// pop returns to the previous view, quitting from the menu
+func (m *Model) pop() tea.Cmd {
+	if len(m.stack) == 1 {
+		return tea.Quit
+	}
+	m.stack = m.stack[:len(m.stack)-1]
+	return nil
+}
+
+func (m *Model) top() view {
+	return m.stack[len(m.stack)-1]
+}
+
+func (m *Model) View() string {
+	s := m.env.styles
+	width := m.env.width
+
+	titles := make([]string, len(m.stack))
+	for i, v := range m.stack {
+		titles[i] = v.title()
+	}
+	header := s.header.Render(ansi.Truncate(strings.Join(titles, " › "), max(width-2, 1), "…"))
+
+	body := lipgloss.NewStyle().
+		Height(m.env.height).
+		MaxHeight(m.env.height).
+		Render(m.top().render())
+
+	footer := s.help.Render(m.top().keys() + " • esc back • q quit")
+	if m.top().typing() {
+		footer = s.help.Render(m.top().keys() + " • ctrl+c quit")
+	}
+	if m.err != nil {
+		footer = s.err.Render("✗ " + m.err.Error())
+	}
+
+	return header + "\n\n" + body + "\n\n" + ansi.Truncate(footer, width, "…")
+}
@@ -0,0 +1,390 @@
+package tui_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/features/shakespert"
+	"prospero/internal/features/topten"
+	"prospero/internal/tui"
+)
+
+// library is an in-memory shakespert service
+type library struct {
+	works  []shakespert.WorkSummary
+	scenes map[string][]shakespert.Scene
+	text   map[string][]shakespert.Passage // by "work act.scene"
+}
+
+func newLibrary() *library {
+	var hamletScene []shakespert.Passage
+	hamletScene = append(hamletScene, shakespert.Passage{Number: 1, CharacterID: "xxx", Text: "Enter HAMLET"})
+	for i := int64(2); i <= 40; i++ {
+		hamletScene = append(hamletScene, shakespert.Passage{
+			Number: i, CharacterID: "hamlet", Character: "Hamlet", Text: fmt.Sprintf("Line %d of the scene", i),
+		})
+	}
+
+	return &library{
+		works: []shakespert.WorkSummary{
+			{WorkID: "hamlet", Title: "Hamlet", Date: 1600, GenreType: "t", GenreName: "Tragedy"},
+			{WorkID: "tempest", Title: "The Tempest", Date: 1611, GenreType: "c", GenreName: "Comedy"},
+		},
+		scenes: map[string][]shakespert.Scene{
+			"hamlet": {
+				{Act: 1, Scene: 1, Description: "Elsinore. A platform before the castle."},
+				{Act: 1, Scene: 2, Description: "A room of state in the castle."},
+			},
+			"tempest": {{Act: 1, Scene: 1, Description: "On a ship at sea."}},
+		},
+		text: map[string][]shakespert.Passage{
+			"hamlet 1.1":  hamletScene,
+			"hamlet 1.2":  {{Number: 41, CharacterID: "hamlet", Character: "Hamlet", Text: "O, that this too too solid flesh would melt"}},
+			"tempest 1.1": {{Number: 1, CharacterID: "prospero", Character: "Prospero", Text: "Our revels now are ended."}},
+		},
+	}
+}
+
+func (l *library) ListWorks(ctx context.Context) ([]shakespert.WorkSummary, error) {
+	return l.works, nil
+}
+
+func (l *library) GetWork(ctx context.Context, workID string) (*shakespert.WorkDetail, error) {
+	for _, w := range l.works {
+		if w.WorkID == workID {
+			return &shakespert.WorkDetail{WorkID: w.WorkID, Title: w.Title, GenreType: w.GenreType}, nil
+		}
+	}
+	return nil, shakespert.ErrWorkNotFound
+}
+
+func (l *library) ListGenres(ctx context.Context) ([]shakespert.Genre, error) {
+	return []shakespert.Genre{
+		{Genretype: "c", Genrename: sql.NullString{String: "Comedy", Valid: true}},
+		{Genretype: "t", Genrename: sql.NullString{String: "Tragedy", Valid: true}},
+	}, nil
+}
+
+func (l *library) GetWorksByGenre(ctx context.Context, genreType string) ([]shakespert.WorkSummary, error) {
+	var works []shakespert.WorkSummary
+	for _, w := range l.works {
+		if w.GenreType == genreType {
+			works = append(works, w)
+		}
+	}
+	return works, nil
+}
+
+func (l *library) ListScenes(ctx context.Context, workID string) ([]shakespert.Scene, error) {
+	return l.scenes[workID], nil
+}
+
+func (l *library) GetScene(ctx context.Context, workID string, act, scene int64) ([]shakespert.Passage, error) {
+	return l.text[fmt.Sprintf("%s %d.%d", workID, act, scene)], nil
+}
+
+func (l *library) Search(ctx context.Context, query string, limit int) ([]shakespert.SearchResult, error) {
+	var results []shakespert.SearchResult
+	for key, passages := range l.text {
+		var workID string
+		var act, scene int64
+		fmt.Sscanf(key, "%s %d.%d", &workID, &act, &scene)
+		for _, p := range passages {
+			if strings.Contains(strings.ToLower(p.Text), strings.ToLower(query)) {
+				results = append(results, shakespert.SearchResult{
+					WorkID: workID, Title: workID, Act: act, Scene: scene,
+					Number: p.Number, Character: p.Character, Text: p.Text,
+				})
+			}
+		}
+	}
+	return results, nil
+}
+
+// lists is an in-memory topten service
+type lists []topten.TopTenList
+
+func (l lists) GetList(index int) (*topten.TopTenList, error) {
+	return &l[index], nil
+}
+
+func (l lists) GetListCount() int {
+	return len(l)
+}
+
+// harness drives a model the way a Bubble Tea program would, running
+// commands synchronously
+type harness struct {
+	t     *testing.T
+	model tea.Model
+	quit  bool
+}
+
+func newHarness(t *testing.T, src tui.Sources) *harness {
+	h := &harness{t: t, model: tui.New(context.Background(), src, lipgloss.NewRenderer(io.Discard))}
+	h.send(tea.WindowSizeMsg{Width: 100, Height: 20})
+	return h
+}
+
+func (h *harness) send(msg tea.Msg) {
+	model, cmd := h.model.Update(msg)
+	h.model = model
+	for cmd != nil {
+		next := cmd()
+		if _, ok := next.(tea.QuitMsg); ok {
+			h.quit = true
+			return
+		}
+		model, cmd = h.model.Update(next)
+		h.model = model
+	}
+}
+
+// keys sends each key in turn. Named keys are bracketed, e.g. "[enter]".
+func (h *harness) keys(keys ...string) {
+	named := map[string]tea.KeyType{
+		"[enter]": tea.KeyEnter, "[esc]": tea.KeyEsc, "[down]": tea.KeyDown, "[up]": tea.KeyUp,
+		"[tab]": tea.KeyTab, "[backspace]": tea.KeyBackspace, "[space]": tea.KeySpace, "[right]": tea.KeyRight,
+		"[left]": tea.KeyLeft, "[ctrl+c]": tea.KeyCtrlC,
+	}
+	for _, key := range keys {
+		if t, ok := named[key]; ok {
+			h.send(tea.KeyMsg{Type: t})
+		} else {
+			h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		}
+	}
+}
+
+func (h *harness) view() string {
+	return h.model.View()
+}
+
+func TestMenu(t *testing.T) {
+	t.Run("should offer every source", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary(), TopTen: lists{{Title: "A"}}})
+
+		view := h.view()
+		assert.Contains(t, view, "Browse works")
+		assert.Contains(t, view, "Search")
+		assert.Contains(t, view, "Top Ten")
+		assert.Contains(t, view, "Quit")
+	})
+
+	t.Run("should hide entries of missing sources", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{TopTen: lists{{Title: "A"}}})
+
+		view := h.view()
+		assert.NotContains(t, view, "Browse works")
+		assert.Contains(t, view, "Top Ten")
+	})
+
+	t.Run("should quit on q", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("q")
+		assert.True(t, h.quit)
+	})
+
+	t.Run("should quit on esc from the menu", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("[esc]")
+		assert.True(t, h.quit)
+	})
+}
+
+func TestBrowse(t *testing.T) {
+	t.Run("should filter works by genre", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("[enter]")
+		require.Contains(t, h.view(), "All works")
+		require.Contains(t, h.view(), "Tragedy")
+
+		h.keys("[down]", "[down]", "[enter]")
+		view := h.view()
+		assert.Contains(t, view, "Hamlet")
+		assert.NotContains(t, view, "The Tempest")
+		assert.Contains(t, view, "Works › Tragedy")
+	})
+
+	t.Run("should list every work", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("[enter]", "[enter]")
+		view := h.view()
+		assert.Contains(t, view, "Hamlet")
+		assert.Contains(t, view, "The Tempest")
+	})
+
+	t.Run("should list the scenes of a work", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("[enter]", "[enter]", "[enter]")
+		view := h.view()
+		assert.Contains(t, view, "Act 1, Scene 1")
+		assert.Contains(t, view, "A room of state in the castle.")
+	})
+
+	t.Run("should go back with esc", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+
+		h.keys("[enter]", "[enter]", "[esc]")
+		assert.Contains(t, h.view(), "Tragedy")
+		assert.False(t, h.quit)
+	})
+}
+
+func TestReader(t *testing.T) {
+	open := func(t *testing.T) *harness {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+		h.keys("[enter]", "[enter]", "[enter]", "[enter]")
+		return h
+	}
+
+	t.Run("should show the start of the scene", func(t *testing.T) {
+		h := open(t)
+
+		view := h.view()
+		assert.Contains(t, view, "Hamlet › Act 1, Scene 1")
+		assert.Contains(t, view, "Enter HAMLET")
+		assert.Contains(t, view, "Line 2 of the scene")
+		assert.NotContains(t, view, "Line 40 of the scene")
+	})
+
+	t.Run("should page through the scene", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("[space]")
+		assert.NotContains(t, h.view(), "Enter HAMLET")
+
+		h.keys("b")
+		assert.Contains(t, h.view(), "Enter HAMLET")
+
+		h.keys("G")
+		view := h.view()
+		assert.Contains(t, view, "Line 40 of the scene")
+		assert.Contains(t, view, "100%")
+	})
+
+	t.Run("should move between scenes", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("n")
+		view := h.view()
+		assert.Contains(t, view, "Act 1, Scene 2")
+		assert.Contains(t, view, "too too solid flesh")
+
+		h.keys("n")
+		assert.Contains(t, h.view(), "Act 1, Scene 2")
+
+		h.keys("p")
+		assert.Contains(t, h.view(), "Enter HAMLET")
+	})
+}
+
+func TestSearch(t *testing.T) {
+	open := func(t *testing.T) *harness {
+		h := newHarness(t, tui.Sources{Library: newLibrary()})
+		h.keys("[down]", "[enter]")
+		return h
+	}
+
+	t.Run("should take q as text", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("q", "u", "i")
+		assert.False(t, h.quit)
+		assert.Contains(t, h.view(), "Search: qui")
+	})
+
+	t.Run("should require a minimum length", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("o", "f", "[enter]")
+		assert.Contains(t, h.view(), "search for at least 3 characters")
+	})
+
+	t.Run("should list matching passages", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("r", "e", "v", "e", "l", "s", "[enter]")
+		view := h.view()
+		assert.Contains(t, view, `1 passage contains "revels"`)
+		assert.Contains(t, view, "Our revels now are ended.")
+	})
+
+	t.Run("should report no matches", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("x", "y", "z", "z", "y", "[enter]")
+		assert.Contains(t, h.view(), `No passages contain "xyzzy"`)
+	})
+
+	t.Run("should open the scene at the passage", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("L", "i", "n", "e", "[space]", "3", "9", "[enter]", "[enter]")
+		view := h.view()
+		assert.Contains(t, view, "Hamlet › Act 1, Scene 1")
+		assert.Contains(t, view, "Line 39 of the scene")
+		assert.NotContains(t, view, "Enter HAMLET")
+	})
+
+	t.Run("should edit the search again", func(t *testing.T) {
+		h := open(t)
+
+		h.keys("r", "e", "v", "e", "l", "s", "[enter]", "/", "[backspace]", "[backspace]")
+		assert.Contains(t, h.view(), "Search: reve")
+	})
+}
+
+func TestTopTen(t *testing.T) {
+	collection := lists{
+		{Title: "List Zero", Date: "1990", Items: []string{"one"}},
+		{Title: "List One", Date: "1991", Items: []string{"one"}},
+		{Title: "List Two", Date: "1992", Items: []string{"one"}},
+	}
+	titles := []string{"List Zero", "List One", "List Two"}
+
+	current := func(t *testing.T, h *harness) int {
+		for i, title := range titles {
+			if strings.Contains(h.view(), title) {
+				return i
+			}
+		}
+		t.Fatalf("no list shown:\n%s", h.view())
+		return -1
+	}
+
+	t.Run("should cycle through the lists", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{TopTen: collection})
+		h.keys("[enter]")
+
+		start := current(t, h)
+		h.keys("[right]")
+		assert.Equal(t, (start+1)%3, current(t, h))
+		h.keys("[left]", "[left]")
+		assert.Equal(t, (start+2)%3, current(t, h))
+		assert.Contains(t, h.view(), fmt.Sprintf("List %d of 3", (start+2)%3+1))
+	})
+
+	t.Run("should number the items", func(t *testing.T) {
+		h := newHarness(t, tui.Sources{TopTen: lists{{Title: "Only", Items: []string{"first", "second"}}}})
+		h.keys("[enter]")
+
+		view := h.view()
+		assert.Contains(t, view, "10. first")
+		assert.Contains(t, view, " 9. second")
+	})
+}
@@ -0,0 +1,42 @@
+// Package version reports build information for the running binary.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is the release version. It is overridden at build time with
+//
+//	go build -ldflags "-X prospero/internal/version.Version=v1.2.3"
+var Version = "dev"
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns build information, filling in the VCS revision recorded by the
+// Go toolchain when available
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	return info
+}
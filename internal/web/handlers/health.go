@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"prospero/assets"
+	"prospero/internal/health"
+	"prospero/internal/version"
+)
+
+// readinessChecker interface for dependency injection
+type readinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+// Health handles the /health endpoint. With ?verbose=true it also reports
+// the build version, uptime and embedded asset checksums.
+func Health(startedAt time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"status":  "healthy",
+			"service": "prospero",
+		}
+
+		if verbose := r.URL.Query().Get("verbose"); verbose == "true" || verbose == "1" {
+			response["build"] = version.Get()
+			response["started_at"] = startedAt.UTC().Format(time.RFC3339)
+			response["uptime"] = time.Since(startedAt).Round(time.Second).String()
+			response["assets"] = assets.Checksums()
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// Livez handles the /livez endpoint. It only reports that the process is
+// running and able to serve requests; dependencies are checked by Readyz.
+func Livez() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": health.StatusOK})
+	}
+}
+
+// Readyz handles the /readyz endpoint. It returns 200 with per-component
+// status when every dependency is healthy, and 503 while starting up,
+// draining for shutdown, or when any component check fails.
+func Readyz(checker readinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+
+		report := checker.Check(ctx)
+
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+
+		writeJSON(w, status, report)
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
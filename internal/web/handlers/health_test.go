@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/health"
+	"prospero/internal/web/handlers"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("should return healthy status with JSON response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		w := httptest.NewRecorder()
+
+		handler := handlers.Health(time.Now())
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var response map[string]interface{}
+		err := json.NewDecoder(w.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, "healthy", response["status"])
+		assert.Equal(t, "prospero", response["service"])
+		assert.NotContains(t, response, "build")
+	})
+
+	t.Run("should include build, uptime and asset checksums when verbose", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health?verbose=true", nil)
+		w := httptest.NewRecorder()
+
+		handler := handlers.Health(time.Now().Add(-time.Minute))
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		err := json.NewDecoder(w.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Contains(t, response, "build")
+		assert.Contains(t, response, "started_at")
+		assert.Equal(t, "1m0s", response["uptime"])
+		assert.Contains(t, response, "assets")
+	})
+}
+
+func TestLivez(t *testing.T) {
+	t.Run("should always report ok", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+		w := httptest.NewRecorder()
+
+		handlers.Livez().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+}
+
+func TestReadyz(t *testing.T) {
+	serve := func(probes *health.Registry) (*httptest.ResponseRecorder, health.Report) {
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		w := httptest.NewRecorder()
+		handlers.Readyz(probes).ServeHTTP(w, req)
+
+		var report health.Report
+		json.Unmarshal(w.Body.Bytes(), &report)
+		return w, report
+	}
+
+	t.Run("should return 200 when all components are ready", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("topten", func(ctx context.Context) error { return nil })
+
+		w, report := serve(probes)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, health.StatusOK, report.Status)
+		assert.Equal(t, health.StatusOK, report.Components["topten"].Status)
+	})
+
+	t.Run("should return 503 when a component fails", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Register("shakespert", func(ctx context.Context) error { return errors.New("database closed") })
+
+		w, report := serve(probes)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.Equal(t, "database closed", report.Components["shakespert"].Error)
+	})
+
+	t.Run("should return 503 during startup", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Pending("http")
+
+		w, report := serve(probes)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, health.StatusStarting, report.Status)
+	})
+
+	t.Run("should return 503 while draining", func(t *testing.T) {
+		probes := health.NewRegistry()
+		probes.Drain()
+
+		w, report := serve(probes)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, health.StatusDraining, report.Status)
+	})
+}
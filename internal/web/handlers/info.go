@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"prospero/internal/features"
 )
 
-// Info handles the /api/info endpoint
-func Info() http.HandlerFunc {
+// Info handles the /api/v1/info endpoint, listing endpoints in the order
+// given
+func Info(endpoints []features.Endpoint) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get the format parameter
 		format := r.URL.Query().Get("format")
@@ -26,7 +29,7 @@ func Info() http.HandlerFunc {
 		switch format {
 		case "text", "ascii":
 			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-			writeInfoAsText(w)
+			writeInfoAsText(w, endpoints)
 
 		case "json":
 			w.Header().Set("Content-Type", "application/json")
@@ -34,43 +37,7 @@ func Info() http.HandlerFunc {
 			info := map[string]interface{}{
 				"service":     "prospero",
 				"description": "An interactive API for exploring classic literature and entertainment",
-				"endpoints": []map[string]string{
-					{
-						"method":      "GET",
-						"path":        "/health",
-						"description": "Health check endpoint",
-					},
-					{
-						"method":      "GET",
-						"path":        "/api/info",
-						"description": "Server information and available endpoints",
-						"parameters":  "?format=json|text|ascii",
-					},
-					{
-						"method":      "GET",
-						"path":        "/api/topten",
-						"description": "Get a random Dave's Top 10 list",
-						"parameters":  "?format=json|ascii",
-					},
-					{
-						"method":      "GET",
-						"path":        "/api/shakespert/works",
-						"description": "List all Shakespeare works",
-						"parameters":  "?format=json|text|ascii&genre=<type>",
-					},
-					{
-						"method":      "GET",
-						"path":        "/api/shakespert/works/{id}",
-						"description": "Get specific work details",
-						"parameters":  "?format=json|text|ascii",
-					},
-					{
-						"method":      "GET",
-						"path":        "/api/shakespert/genres",
-						"description": "List all genres",
-						"parameters":  "?format=json|text|ascii",
-					},
-				},
+				"endpoints":   endpoints,
 				"notes": []string{
 					"Endpoints auto-detect curl and return ASCII format by default",
 					"Use ?format=json for JSON responses",
@@ -79,18 +46,18 @@ func Info() http.HandlerFunc {
 			}
 
 			if err := json.NewEncoder(w).Encode(info); err != nil {
-				http.Error(w, fmt.Sprintf("Failed to encode JSON: %v", err), http.StatusInternalServerError)
+				WriteProblem(w, r, http.StatusInternalServerError, CodeInternal, "Failed to encode JSON")
 				return
 			}
 
 		default:
-			http.Error(w, "Invalid format parameter. Use 'json', 'text', or 'ascii'", http.StatusBadRequest)
+			WriteProblem(w, r, http.StatusBadRequest, CodeInvalidFormat, "Invalid format parameter. Use 'json', 'text', or 'ascii'")
 			return
 		}
 	}
 }
 
-func writeInfoAsText(w http.ResponseWriter) {
+func writeInfoAsText(w http.ResponseWriter, endpoints []features.Endpoint) {
 	var b strings.Builder
 
 	b.WriteString("\n")
@@ -105,34 +72,14 @@ func writeInfoAsText(w http.ResponseWriter) {
 	b.WriteString("────────────────────────────────────────────────────────────────────\n")
 	b.WriteString("\n")
 
-	b.WriteString("  GET  /health\n")
-	b.WriteString("       Health check endpoint\n")
-	b.WriteString("\n")
-
-	b.WriteString("  GET  /api/info\n")
-	b.WriteString("       Server information and available endpoints\n")
-	b.WriteString("       Parameters: ?format=json|text|ascii\n")
-	b.WriteString("\n")
-
-	b.WriteString("  GET  /api/topten\n")
-	b.WriteString("       Get a random Dave's Top 10 list\n")
-	b.WriteString("       Parameters: ?format=json|ascii\n")
-	b.WriteString("\n")
-
-	b.WriteString("  GET  /api/shakespert/works\n")
-	b.WriteString("       List all Shakespeare works\n")
-	b.WriteString("       Parameters: ?format=json|text|ascii&genre=<type>\n")
-	b.WriteString("\n")
-
-	b.WriteString("  GET  /api/shakespert/works/{id}\n")
-	b.WriteString("       Get specific work details\n")
-	b.WriteString("       Parameters: ?format=json|text|ascii\n")
-	b.WriteString("\n")
-
-	b.WriteString("  GET  /api/shakespert/genres\n")
-	b.WriteString("       List all genres\n")
-	b.WriteString("       Parameters: ?format=json|text|ascii\n")
-	b.WriteString("\n")
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&b, "  %-4s %s\n", endpoint.Method, endpoint.Path)
+		fmt.Fprintf(&b, "       %s\n", endpoint.Description)
+		if endpoint.Parameters != "" {
+			fmt.Fprintf(&b, "       Parameters: %s\n", endpoint.Parameters)
+		}
+		b.WriteString("\n")
+	}
 
 	b.WriteString("💡 Tips:\n")
 	b.WriteString("────────────────────────────────────────────────────────────────────\n")
@@ -145,11 +92,12 @@ func writeInfoAsText(w http.ResponseWriter) {
 	b.WriteString("Examples:\n")
 	b.WriteString("────────────────────────────────────────────────────────────────────\n")
 	b.WriteString("\n")
-	b.WriteString("  curl http://localhost:8080/api/info\n")
-	b.WriteString("  curl http://localhost:8080/api/topten\n")
-	b.WriteString("  curl http://localhost:8080/api/shakespert/works\n")
-	b.WriteString("  curl http://localhost:8080/api/shakespert/works/hamlet\n")
-	b.WriteString("  curl http://localhost:8080/api/shakespert/genres\n")
+	for _, endpoint := range endpoints {
+		// Only endpoints without path parameters make runnable examples
+		if strings.HasPrefix(endpoint.Path, "/api/") && !strings.Contains(endpoint.Path, "{") {
+			fmt.Fprintf(&b, "  curl http://localhost:8080%s\n", endpoint.Path)
+		}
+	}
 	b.WriteString("\n")
 	b.WriteString("════════════════════════════════════════════════════════════════════\n")
 	b.WriteString("\n")
@@ -9,16 +9,26 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"prospero/internal/features"
 	"prospero/internal/web/handlers"
 )
 
+var testEndpoints = []features.Endpoint{
+	{Method: "GET", Path: "/health", Description: "Health check endpoint", Parameters: "?verbose=true"},
+	{Method: "GET", Path: "/api/v1/info", Description: "Server information and available endpoints"},
+	{Method: "GET", Path: "/api/v1/topten", Description: "Get a random Dave's Top 10 list"},
+	{Method: "GET", Path: "/api/v1/shakespert/works", Description: "List all Shakespeare works"},
+	{Method: "GET", Path: "/api/v1/shakespert/works/{id}", Description: "Get specific work details"},
+	{Method: "GET", Path: "/api/v1/shakespert/genres", Description: "List all genres"},
+}
+
 func TestInfo(t *testing.T) {
 	t.Run("should return JSON by default when not using curl", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info", nil)
 		req.Header.Set("User-Agent", "Mozilla/5.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -34,11 +44,11 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should return ASCII format when user-agent contains curl", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info", nil)
 		req.Header.Set("User-Agent", "curl/7.68.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -50,11 +60,11 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should return JSON when format=json is explicitly set", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=json", nil)
 		req.Header.Set("User-Agent", "curl/7.68.0")
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -67,10 +77,10 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should return text format when format=text", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=text", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=text", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -81,10 +91,10 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should return text format when format=ascii", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=ascii", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=ascii", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -95,10 +105,10 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should return error for invalid format parameter", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=invalid", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=invalid", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -120,11 +130,11 @@ func TestInfo(t *testing.T) {
 
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/info", nil)
 				req.Header.Set("User-Agent", test.userAgent)
 				w := httptest.NewRecorder()
 
-				handler := handlers.Info()
+				handler := handlers.Info(testEndpoints)
 				handler.ServeHTTP(w, req)
 
 				assert.Equal(t, http.StatusOK, w.Code)
@@ -143,10 +153,10 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should include all expected endpoints in JSON response", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=json", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=json", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		var response map[string]interface{}
@@ -162,11 +172,11 @@ func TestInfo(t *testing.T) {
 
 		expectedPaths := []string{
 			"/health",
-			"/api/info",
-			"/api/topten",
-			"/api/shakespert/works",
-			"/api/shakespert/works/{id}",
-			"/api/shakespert/genres",
+			"/api/v1/info",
+			"/api/v1/topten",
+			"/api/v1/shakespert/works",
+			"/api/v1/shakespert/works/{id}",
+			"/api/v1/shakespert/genres",
 		}
 
 		for _, expected := range expectedPaths {
@@ -175,24 +185,37 @@ func TestInfo(t *testing.T) {
 	})
 
 	t.Run("should include all expected endpoints in text response", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/api/info?format=text", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=text", nil)
 		w := httptest.NewRecorder()
 
-		handler := handlers.Info()
+		handler := handlers.Info(testEndpoints)
 		handler.ServeHTTP(w, req)
 
 		body := w.Body.String()
 		expectedEndpoints := []string{
 			"/health",
-			"/api/info",
-			"/api/topten",
-			"/api/shakespert/works",
-			"/api/shakespert/works/{id}",
-			"/api/shakespert/genres",
+			"/api/v1/info",
+			"/api/v1/topten",
+			"/api/v1/shakespert/works",
+			"/api/v1/shakespert/works/{id}",
+			"/api/v1/shakespert/genres",
 		}
 
 		for _, endpoint := range expectedEndpoints {
 			assert.Contains(t, body, endpoint, "missing endpoint in text: %s", endpoint)
 		}
 	})
+
+	t.Run("should only use endpoints without path parameters as curl examples", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/info?format=text", nil)
+		w := httptest.NewRecorder()
+
+		handler := handlers.Info(testEndpoints)
+		handler.ServeHTTP(w, req)
+
+		body := w.Body.String()
+		assert.Contains(t, body, "curl http://localhost:8080/api/v1/topten\n")
+		assert.NotContains(t, body, "curl http://localhost:8080/api/v1/shakespert/works/{id}")
+		assert.NotContains(t, body, "curl http://localhost:8080/health")
+	})
 }
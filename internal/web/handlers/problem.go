@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is the media type for RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase is the prefix for problem type URIs. Each error code
+// resolves to problemTypeBase + code.
+const problemTypeBase = "urn:prospero:problem:"
+
+// Stable error codes returned in the "code" member of problem responses.
+// These are part of the API contract and must not change once published.
+const (
+	CodeInvalidFormat          = "invalid_format"
+	CodeMissingWorkID          = "missing_work_id"
+	CodeWorkNotFound           = "work_not_found"
+	CodeNoLists                = "no_lists"
+	CodeNotFound               = "not_found"
+	CodeMethodNotAllowed       = "method_not_allowed"
+	CodeOriginNotAllowed       = "origin_not_allowed"
+	CodeRateLimited            = "rate_limited"
+	CodeAuthenticationRequired = "authentication_required"
+	CodeInvalidToken           = "invalid_token"
+	CodeInsufficientScope      = "insufficient_scope"
+	CodeInternal               = "internal_error"
+)
+
+// Problem is an RFC 9457 problem details object
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// WriteProblem writes an application/problem+json response with the given
+// status, stable error code and human-readable detail
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	problem := Problem{
+		Type:     problemTypeBase + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// NotFound returns a handler that answers unknown routes with a problem response
+func NotFound() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, http.StatusNotFound, CodeNotFound, "No route matches "+r.URL.Path)
+	}
+}
+
+// MethodNotAllowed returns a handler that answers unsupported methods with a problem response
+func MethodNotAllowed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+			"Method "+r.Method+" is not allowed on "+r.URL.Path)
+	}
+}
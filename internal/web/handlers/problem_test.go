@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/web/handlers"
+)
+
+func TestWriteProblem(t *testing.T) {
+	t.Run("should write RFC 9457 problem details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works/nope", nil)
+		w := httptest.NewRecorder()
+
+		handlers.WriteProblem(w, req, http.StatusNotFound, handlers.CodeWorkNotFound, "Work not found: nope")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem handlers.Problem
+		err := json.NewDecoder(w.Body).Decode(&problem)
+		require.NoError(t, err)
+
+		assert.Equal(t, "urn:prospero:problem:work_not_found", problem.Type)
+		assert.Equal(t, "Not Found", problem.Title)
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "Work not found: nope", problem.Detail)
+		assert.Equal(t, "/api/v1/shakespert/works/nope", problem.Instance)
+		assert.Equal(t, handlers.CodeWorkNotFound, problem.Code)
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	t.Run("should return problem response for unknown routes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/nothing", nil)
+		w := httptest.NewRecorder()
+
+		handler := handlers.NotFound()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), handlers.CodeNotFound)
+	})
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Run("should return problem response for unsupported methods", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/topten", nil)
+		w := httptest.NewRecorder()
+
+		handler := handlers.MethodNotAllowed()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Contains(t, w.Body.String(), handlers.CodeMethodNotAllowed)
+	})
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"prospero/internal/auth"
+	"prospero/internal/web/handlers"
+)
+
+// bearerRealm is the realm advertised in WWW-Authenticate challenges
+const bearerRealm = "prospero"
+
+// Authenticate identifies the caller of each request and stores the
+// principal in the request context for RequireScope. Requests without an
+// Authorization header are anonymous and hold publicScopes; a header that
+// is not a valid bearer token is rejected with 401 rather than downgraded to
+// anonymous access.
+func Authenticate(keys *auth.Keyring, publicScopes []string) func(http.Handler) http.Handler {
+	anonymous := auth.Anonymous(publicScopes)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), anonymous)))
+				return
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				challenge(w, "invalid_request", "")
+				handlers.WriteProblem(w, r, http.StatusUnauthorized, handlers.CodeInvalidToken,
+					"Authorization must be a Bearer token")
+				return
+			}
+
+			key, err := keys.Authenticate(strings.TrimSpace(token))
+			if err != nil {
+				detail := "The API key is not valid"
+				if errors.Is(err, auth.ErrRevokedKey) {
+					detail = "The API key has been revoked"
+				}
+				challenge(w, "invalid_token", "")
+				handlers.WriteProblem(w, r, http.StatusUnauthorized, handlers.CodeInvalidToken, detail)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), auth.ForKey(key))))
+		})
+	}
+}
+
+// RequireScope rejects requests whose principal lacks scope: anonymous
+// callers get 401 so they know a key would help, callers with a key get 403.
+// Requests that did not pass through Authenticate are rejected.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			switch {
+			case ok && principal.Has(scope):
+				next.ServeHTTP(w, r)
+			case !ok || principal.Anonymous():
+				challenge(w, "", scope)
+				handlers.WriteProblem(w, r, http.StatusUnauthorized, handlers.CodeAuthenticationRequired,
+					"An API key with scope "+scope+" is required")
+			default:
+				challenge(w, "insufficient_scope", scope)
+				handlers.WriteProblem(w, r, http.StatusForbidden, handlers.CodeInsufficientScope,
+					"The API key lacks scope "+scope)
+			}
+		})
+	}
+}
+
+// challenge sets an RFC 6750 WWW-Authenticate header
+func challenge(w http.ResponseWriter, code, scope string) {
+	value := `Bearer realm="` + bearerRealm + `"`
+	if code != "" {
+		value += `, error="` + code + `"`
+	}
+	if scope != "" {
+		value += `, scope="` + scope + `"`
+	}
+	w.Header().Set("WWW-Authenticate", value)
+}
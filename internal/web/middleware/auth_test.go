@@ -0,0 +1,115 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/auth"
+	"prospero/internal/web/middleware"
+)
+
+func TestAuthenticate(t *testing.T) {
+	reader, readerToken, err := auth.NewKey("reader", []string{auth.ScopeReadTopTen}, nil)
+	require.NoError(t, err)
+	admin, adminToken, err := auth.NewKey("ops", []string{auth.ScopeAdmin}, nil)
+	require.NoError(t, err)
+	keys, err := auth.NewKeyring([]auth.Key{reader, admin})
+	require.NoError(t, err)
+
+	newRouter := func(publicScopes []string) *chi.Mux {
+		r := chi.NewRouter()
+		r.Use(middleware.Authenticate(keys, publicScopes))
+		ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+		r.With(middleware.RequireScope(auth.ScopeReadTopTen)).Get("/topten", ok)
+		r.With(middleware.RequireScope(auth.ScopeMCP)).Post("/mcp", ok)
+		r.Get("/info", ok)
+		return r
+	}
+
+	request := func(r http.Handler, method, path, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should give anonymous requests the public scopes", func(t *testing.T) {
+		r := newRouter([]string{auth.ScopeReadTopTen})
+
+		assert.Equal(t, http.StatusOK, request(r, http.MethodGet, "/topten", "").Code)
+
+		w := request(r, http.MethodPost, "/mcp", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer realm="prospero", scope="mcp"`, w.Header().Get("WWW-Authenticate"))
+		assert.Contains(t, w.Body.String(), `"code":"authentication_required"`)
+	})
+
+	t.Run("should require a key when nothing is public", func(t *testing.T) {
+		r := newRouter(nil)
+
+		assert.Equal(t, http.StatusUnauthorized, request(r, http.MethodGet, "/topten", "").Code)
+		assert.Equal(t, http.StatusOK, request(r, http.MethodGet, "/topten", "Bearer "+readerToken).Code)
+		assert.Equal(t, http.StatusOK, request(r, http.MethodGet, "/info", "").Code)
+	})
+
+	t.Run("should answer 403 when the key lacks the scope", func(t *testing.T) {
+		w := request(newRouter(nil), http.MethodPost, "/mcp", "Bearer "+readerToken)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, `Bearer realm="prospero", error="insufficient_scope", scope="mcp"`, w.Header().Get("WWW-Authenticate"))
+		assert.Contains(t, w.Body.String(), `"code":"insufficient_scope"`)
+	})
+
+	t.Run("should let admin keys use every scope", func(t *testing.T) {
+		r := newRouter(nil)
+
+		assert.Equal(t, http.StatusOK, request(r, http.MethodPost, "/mcp", "Bearer "+adminToken).Code)
+		assert.Equal(t, http.StatusOK, request(r, http.MethodGet, "/topten", "bearer "+adminToken).Code)
+	})
+
+	t.Run("should reject invalid credentials even on public routes", func(t *testing.T) {
+		r := newRouter([]string{auth.ScopeReadTopTen})
+
+		w := request(r, http.MethodGet, "/info", "Bearer prospero_00000000_nope")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer realm="prospero", error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+		assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
+
+		w = request(r, http.MethodGet, "/info", "Basic dXNlcjpwYXNz")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer realm="prospero", error="invalid_request"`, w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("should keep keyed responses of cached routes out of shared caches", func(t *testing.T) {
+		r := chi.NewRouter()
+		r.Use(middleware.Authenticate(keys, nil))
+		r.With(middleware.RequireScope(auth.ScopeReadTopTen),
+			middleware.Cache(middleware.CachePolicy{CacheControl: middleware.CacheImmutable, Version: "v1"}),
+		).Get("/topten", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ariel")) })
+
+		w := request(r, http.MethodGet, "/topten", "Bearer "+readerToken)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Cache-Control"), "private,"), w.Header().Get("Cache-Control"))
+		assert.Contains(t, w.Header().Values("Vary"), "Authorization")
+
+		w = request(r, http.MethodGet, "/topten", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotContains(t, w.Header().Get("Cache-Control"), "public")
+	})
+
+	t.Run("should reject requests that were never authenticated", func(t *testing.T) {
+		r := chi.NewRouter()
+		r.With(middleware.RequireScope(auth.ScopeReadTopTen)).Get("/topten", func(w http.ResponseWriter, r *http.Request) {})
+
+		assert.Equal(t, http.StatusUnauthorized, request(r, http.MethodGet, "/topten", "").Code)
+	})
+}
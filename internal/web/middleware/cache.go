@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Common Cache-Control policies
+const (
+	// CacheImmutable is for data that only changes when a new binary is deployed
+	CacheImmutable = "public, max-age=86400, stale-while-revalidate=3600"
+	// CacheShort is for responses that are stable but may be reworded between releases
+	CacheShort = "public, max-age=300"
+	// CacheNoStore is for responses that must never be reused, such as random picks
+	CacheNoStore = "no-store"
+)
+
+// CachePolicy describes the caching headers for a route
+type CachePolicy struct {
+	// CacheControl is the Cache-Control header value for successful
+	// responses. A public policy is made private for requests with an
+	// Authorization header, so that shared caches never serve a response
+	// obtained with an API key to anyone else.
+	CacheControl string
+
+	// Version seeds the ETag. Leave empty to disable ETag and conditional
+	// request handling, e.g. for responses that differ on every request.
+	Version string
+
+	// LastModified is sent as Last-Modified when non-zero
+	LastModified time.Time
+
+	// Vary lists request headers that select the representation. Their
+	// values are folded into the ETag and advertised in the Vary header.
+	Vary []string
+}
+
+// Cache applies the given policy. When the policy has a Version, a strong
+// ETag is derived from the version, request path, query and Vary headers,
+// and matching If-None-Match or If-Modified-Since requests are answered
+// with 304 Not Modified once the handler has produced a successful
+// response, so that missing resources still get their error.
+//
+// Public responses carry Vary: Authorization, and are sent as private to
+// requests with credentials. Error responses (status >= 400) are always
+// sent with Cache-Control: no-store and without validators.
+func Cache(policy CachePolicy) func(http.Handler) http.Handler {
+	lastModified := ""
+	if !policy.LastModified.IsZero() {
+		lastModified = policy.LastModified.UTC().Format(http.TimeFormat)
+	}
+	public := hasDirective(policy.CacheControl, "public")
+	private := privateCacheControl(policy.CacheControl)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			for _, name := range policy.Vary {
+				h.Add("Vary", name)
+			}
+			if public {
+				h.Add("Vary", "Authorization")
+			}
+
+			etag := ""
+			if policy.Version != "" {
+				etag = computeETag(policy.Version, r, policy.Vary)
+				h.Set("ETag", etag)
+				if lastModified != "" {
+					h.Set("Last-Modified", lastModified)
+				}
+			}
+			switch {
+			case public && r.Header.Get("Authorization") != "":
+				h.Set("Cache-Control", private)
+			case policy.CacheControl != "":
+				h.Set("Cache-Control", policy.CacheControl)
+			}
+
+			conditional := etag != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+				notModified(r, etag, policy.LastModified)
+
+			next.ServeHTTP(&cacheResponseWriter{ResponseWriter: w, notModified: conditional}, r)
+		})
+	}
+}
+
+// hasDirective reports whether a Cache-Control value holds directive
+func hasDirective(cacheControl, directive string) bool {
+	for _, part := range strings.Split(cacheControl, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(name, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// privateCacheControl turns a public Cache-Control value into a private one,
+// keeping its other directives
+func privateCacheControl(cacheControl string) string {
+	parts := strings.Split(cacheControl, ",")
+	for i, part := range parts {
+		if strings.EqualFold(strings.TrimSpace(part), "public") {
+			parts[i] = strings.Replace(part, strings.TrimSpace(part), "private", 1)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// computeETag derives a strong ETag from the asset version and the parts of
+// the request that select the representation
+func computeETag(version string, r *http.Request, vary []string) string {
+	h := sha256.New()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Query().Encode()))
+	for _, name := range vary {
+		h.Write([]byte{0})
+		h.Write([]byte(strings.ToLower(r.Header.Get(name))))
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// notModified evaluates the conditional request headers. If-None-Match takes
+// precedence over If-Modified-Since as required by RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// etagMatches implements the weak comparison used for If-None-Match
+func etagMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheResponseWriter strips caching headers from error responses and,
+// when the request's validators match, turns a successful response into a
+// 304 without its body
+type cacheResponseWriter struct {
+	http.ResponseWriter
+	notModified bool
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *cacheResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	switch {
+	case status >= http.StatusBadRequest:
+		h.Set("Cache-Control", CacheNoStore)
+		h.Del("ETag")
+		h.Del("Last-Modified")
+	case w.notModified && status >= http.StatusOK && status < http.StatusMultipleChoices:
+		status = http.StatusNotModified
+		h.Del("Content-Length")
+		w.discard = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer so streaming responses keep working
+func (w *cacheResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *cacheResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,177 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/web/middleware"
+)
+
+func TestCache(t *testing.T) {
+	modified := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := middleware.CachePolicy{
+		CacheControl: middleware.CacheImmutable,
+		Version:      "v1",
+		LastModified: modified,
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+
+	serve := func(policy middleware.CachePolicy, handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		middleware.Cache(policy)(handler).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should set ETag, Last-Modified and Cache-Control", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works", nil)
+		w := serve(policy, ok, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, middleware.CacheImmutable, w.Header().Get("Cache-Control"))
+		assert.Equal(t, modified.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		assert.NotContains(t, etag, "W/", "ETag should be strong")
+	})
+
+	t.Run("should derive different ETags for different query parameters", func(t *testing.T) {
+		a := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works?genre=t", nil))
+		b := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works?genre=c", nil))
+		c := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works?genre=t", nil))
+
+		assert.NotEqual(t, a.Header().Get("ETag"), b.Header().Get("ETag"))
+		assert.Equal(t, a.Header().Get("ETag"), c.Header().Get("ETag"))
+	})
+
+	t.Run("should derive different ETags for different versions", func(t *testing.T) {
+		other := policy
+		other.Version = "v2"
+
+		a := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works", nil))
+		b := serve(other, ok, httptest.NewRequest(http.MethodGet, "/works", nil))
+
+		assert.NotEqual(t, a.Header().Get("ETag"), b.Header().Get("ETag"))
+	})
+
+	t.Run("should answer matching If-None-Match with 304", func(t *testing.T) {
+		first := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works", nil))
+		etag := first.Header().Get("ETag")
+
+		req := httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("If-None-Match", `"other", `+etag)
+		w := serve(policy, ok, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("should answer 304 only for resources that exist", func(t *testing.T) {
+		missing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no such work", http.StatusNotFound)
+		})
+
+		for _, validator := range []string{"*", serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works/nope", nil)).Header().Get("ETag")} {
+			req := httptest.NewRequest(http.MethodGet, "/works/nope", nil)
+			req.Header.Set("If-None-Match", validator)
+			w := serve(policy, missing, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, validator)
+			assert.Equal(t, "no such work\n", w.Body.String())
+			assert.Empty(t, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("should match weak If-None-Match validators", func(t *testing.T) {
+		first := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works", nil))
+
+		req := httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("If-None-Match", "W/"+first.Header().Get("ETag"))
+		w := serve(policy, ok, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("should serve full response for stale If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		w := serve(policy, ok, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello", w.Body.String())
+	})
+
+	t.Run("should answer If-Modified-Since when no If-None-Match is sent", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("If-Modified-Since", modified.Add(time.Hour).Format(http.TimeFormat))
+		w := serve(policy, ok, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat))
+		w = serve(policy, ok, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should fold Vary headers into the ETag", func(t *testing.T) {
+		varying := policy
+		varying.Vary = []string{"User-Agent"}
+
+		curlReq := httptest.NewRequest(http.MethodGet, "/info", nil)
+		curlReq.Header.Set("User-Agent", "curl/8.0")
+		browserReq := httptest.NewRequest(http.MethodGet, "/info", nil)
+		browserReq.Header.Set("User-Agent", "Mozilla/5.0")
+
+		a := serve(varying, ok, curlReq)
+		b := serve(varying, ok, browserReq)
+
+		assert.NotEqual(t, a.Header().Get("ETag"), b.Header().Get("ETag"))
+		assert.Equal(t, "User-Agent", a.Header().Get("Vary"))
+	})
+
+	t.Run("should keep responses to requests with an API key out of shared caches", func(t *testing.T) {
+		anonymous := serve(policy, ok, httptest.NewRequest(http.MethodGet, "/works", nil))
+		assert.Equal(t, middleware.CacheImmutable, anonymous.Header().Get("Cache-Control"))
+		assert.Contains(t, anonymous.Header().Values("Vary"), "Authorization")
+
+		req := httptest.NewRequest(http.MethodGet, "/works", nil)
+		req.Header.Set("Authorization", "Bearer prospero_key")
+		keyed := serve(policy, ok, req)
+		assert.Equal(t, "private, max-age=86400, stale-while-revalidate=3600", keyed.Header().Get("Cache-Control"))
+		assert.Contains(t, keyed.Header().Values("Vary"), "Authorization")
+		assert.Equal(t, anonymous.Header().Get("ETag"), keyed.Header().Get("ETag"))
+	})
+
+	t.Run("should not set validators for no-store policies", func(t *testing.T) {
+		w := serve(middleware.CachePolicy{CacheControl: middleware.CacheNoStore}, ok,
+			httptest.NewRequest(http.MethodGet, "/topten", nil))
+
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+
+	t.Run("should strip caching headers from error responses", func(t *testing.T) {
+		failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		})
+
+		w := serve(policy, failing, httptest.NewRequest(http.MethodGet, "/works", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+	})
+}
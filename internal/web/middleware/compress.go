@@ -0,0 +1,306 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressMinSize is the smallest response body worth compressing.
+// Smaller bodies are sent as-is since framing overhead eats the savings.
+const DefaultCompressMinSize = 1024
+
+// Supported content codings in server preference order
+const (
+	encodingZstd   = "zstd"
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+var encodingPreference = []string{encodingZstd, encodingBrotli, encodingGzip}
+
+// compressibleTypes lists media types that benefit from compression. Anything
+// else (images, archives, octet streams) is assumed to be compressed already.
+var compressibleTypes = []string{
+	"text/",
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// encoder is the common interface of the pooled compressors
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// zstdEncoder adapts *zstd.Encoder to the encoder interface
+type zstdEncoder struct {
+	*zstd.Encoder
+}
+
+func (e zstdEncoder) Reset(w io.Writer) {
+	e.Encoder.Reset(w)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	encodingGzip: {New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}},
+	encodingBrotli: {New: func() any {
+		return brotli.NewWriterLevel(io.Discard, 5)
+	}},
+	encodingZstd: {New: func() any {
+		w, _ := zstd.NewWriter(io.Discard,
+			zstd.WithEncoderLevel(zstd.SpeedDefault),
+			zstd.WithEncoderConcurrency(1),
+		)
+		return zstdEncoder{w}
+	}},
+}
+
+// Compress negotiates a content coding from Accept-Encoding and compresses
+// response bodies of at least minSize bytes. Supported codings are zstd,
+// brotli and gzip.
+//
+// Responses that already carry a Content-Encoding, have a non-compressible
+// Content-Type, or have no body are passed through uncompressed. When an
+// encoding was negotiated, a strong ETag is converted to a weak one whether
+// or not the body ends up compressed: encoded bytes are not identical to
+// the identity representation, and a 304, which has no body to decide on,
+// must send back the validator the full response carried. Flush is honored
+// so streaming responses are delivered incrementally.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        minSize,
+			}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the best supported coding from an Accept-Encoding
+// header, honoring q-values and breaking ties by server preference
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if params != "" {
+			key, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+		}
+
+		if name == "*" {
+			wildcard = q
+		} else {
+			weights[name] = q
+		}
+	}
+
+	best := ""
+	bestQ := 0.0
+	for _, encoding := range encodingPreference {
+		q, ok := weights[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best = encoding
+			bestQ = q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is large enough and of a suitable type to compress
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status   int
+	buf      []byte
+	decided  bool
+	enc      encoder
+	finished bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = status
+
+	// Bodiless responses are committed immediately
+	if status == http.StatusNoContent || status == http.StatusNotModified || status < http.StatusOK {
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) < w.minSize {
+			return len(b), nil
+		}
+		if err := w.commit(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush commits the headers and pushes any encoded bytes to the client.
+// A streaming response is compressed regardless of the bytes seen so far.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.commit(true)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// commit decides whether to compress, writes the headers and drains the buffer
+func (w *compressWriter) commit(allowCompress bool) error {
+	w.decide(allowCompress)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// decide fixes the response headers and, if appropriate, sets up the encoder
+func (w *compressWriter) decide(allowCompress bool) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if allowCompress && h.Get("Content-Encoding") == "" && isCompressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+
+		w.enc = encoderPools[w.encoding].Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	weakenETag(h)
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// weakenETag converts a strong ETag to a weak one
+func weakenETag(h http.Header) {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+}
+
+// close finishes the response once the handler returns
+func (w *compressWriter) close() {
+	if w.finished {
+		return
+	}
+	w.finished = true
+
+	if !w.decided {
+		// The body never reached the threshold
+		w.commit(false)
+	}
+
+	if w.enc != nil {
+		w.enc.Close()
+		w.enc.Reset(io.Discard)
+		encoderPools[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+}
+
+// isCompressible reports whether a Content-Type benefits from compression
+func isCompressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		return false
+	}
+
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
@@ -0,0 +1,209 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/web/middleware"
+)
+
+func TestCompress(t *testing.T) {
+	largeBody := strings.Repeat("To be, or not to be, that is the question. ", 100)
+
+	textHandler := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("ETag", `"abc123"`)
+			w.Write([]byte(body))
+		})
+	}
+
+	serve := func(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		middleware.Compress(middleware.DefaultCompressMinSize)(handler).ServeHTTP(w, req)
+		return w
+	}
+
+	decode := func(t *testing.T, encoding string, body io.Reader) string {
+		t.Helper()
+
+		var reader io.Reader
+		switch encoding {
+		case "gzip":
+			gz, err := gzip.NewReader(body)
+			require.NoError(t, err)
+			reader = gz
+		case "br":
+			reader = brotli.NewReader(body)
+		case "zstd":
+			zr, err := zstd.NewReader(body)
+			require.NoError(t, err)
+			defer zr.Close()
+			reader = zr
+		default:
+			reader = body
+		}
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("should compress with each supported encoding", func(t *testing.T) {
+		for _, encoding := range []string{"gzip", "br", "zstd"} {
+			t.Run(encoding, func(t *testing.T) {
+				w := serve(textHandler(largeBody), encoding)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+				assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+				assert.Less(t, w.Body.Len(), len(largeBody))
+				assert.Equal(t, largeBody, decode(t, encoding, w.Body))
+			})
+		}
+	})
+
+	t.Run("should prefer zstd when several encodings are equally acceptable", func(t *testing.T) {
+		w := serve(textHandler(largeBody), "gzip, deflate, br, zstd")
+		assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("should honor q-values", func(t *testing.T) {
+		w := serve(textHandler(largeBody), "zstd;q=0.1, gzip;q=0.9")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+		w = serve(textHandler(largeBody), "gzip;q=0, identity")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("should not compress without Accept-Encoding", func(t *testing.T) {
+		w := serve(textHandler(largeBody), "")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+		assert.Equal(t, `"abc123"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("should not compress bodies below the minimum size", func(t *testing.T) {
+		w := serve(textHandler("short"), "gzip")
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "short", w.Body.String())
+		assert.Equal(t, `W/"abc123"`, w.Header().Get("ETag"), "the ETag is weak whenever an encoding was negotiated")
+
+		w = serve(textHandler("short"), "")
+		assert.Equal(t, `"abc123"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("should weaken strong ETags on encoded responses", func(t *testing.T) {
+		w := serve(textHandler(largeBody), "gzip")
+		assert.Equal(t, `W/"abc123"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("should skip already-compressed content types", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte(largeBody))
+		})
+
+		w := serve(handler, "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("should skip responses that already have a Content-Encoding", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Encoding", "identity")
+			w.Write([]byte(largeBody))
+		})
+
+		w := serve(handler, "gzip")
+		assert.Equal(t, "identity", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("should pass through 304 responses", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		})
+
+		w := serve(handler, "gzip")
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Zero(t, w.Body.Len())
+	})
+
+	t.Run("should send back the ETag of the full response on 304 responses", func(t *testing.T) {
+		for name, body := range map[string]string{"compressed": largeBody, "below the minimum size": "short"} {
+			t.Run(name, func(t *testing.T) {
+				handler := middleware.Compress(middleware.DefaultCompressMinSize)(
+					middleware.Cache(middleware.CachePolicy{CacheControl: middleware.CacheShort, Version: "v1"})(
+						http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+							w.Write([]byte(body))
+						})))
+
+				request := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/shakespert/works", nil)
+					req.Header.Set("Accept-Encoding", acceptEncoding)
+					req.Header.Set("If-None-Match", ifNoneMatch)
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, req)
+					return w
+				}
+
+				for _, acceptEncoding := range []string{"gzip", ""} {
+					full := request(acceptEncoding, "")
+					require.Equal(t, http.StatusOK, full.Code)
+					etag := full.Header().Get("ETag")
+					require.NotEmpty(t, etag)
+
+					revalidated := request(acceptEncoding, etag)
+					assert.Equal(t, http.StatusNotModified, revalidated.Code)
+					assert.Equal(t, etag, revalidated.Header().Get("ETag"), "Accept-Encoding: %q", acceptEncoding)
+					assert.Zero(t, revalidated.Body.Len())
+				}
+			})
+		}
+	})
+
+	t.Run("should preserve the status code", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(largeBody))
+		})
+
+		w := serve(handler, "gzip")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("should compress streamed responses on flush", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: hello\n\n"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("data: world\n\n"))
+		})
+
+		w := serve(handler, "gzip")
+		assert.True(t, w.Flushed)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "data: hello\n\ndata: world\n\n", decode(t, "gzip", w.Body))
+	})
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/web/handlers"
+)
+
+// corsMethods are the methods a preflight may be answered with. Only those
+// actually routed for the request path are advertised.
+var corsMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// CORSPolicy describes which cross-origin requests are allowed
+type CORSPolicy struct {
+	// AllowedOrigins lists origins or origin patterns, see MatchOrigin
+	AllowedOrigins []string
+
+	// AllowCredentials lets browsers send cookies and Authorization headers.
+	// The matching origin is echoed instead of "*" when set.
+	AllowCredentials bool
+
+	// AllowedHeaders lists request headers a preflight may ask for
+	AllowedHeaders []string
+
+	// ExposedHeaders lists response headers scripts may read
+	ExposedHeaders []string
+
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// CORS applies policy to cross-origin requests. Preflight requests (OPTIONS
+// with Access-Control-Request-Method) are answered directly with the methods
+// registered on the matching chi route; a preflight for a path with no
+// routes falls through so it gets the usual 404. Other OPTIONS requests are
+// routed normally.
+func CORS(policy CORSPolicy) func(http.Handler) http.Handler {
+	// With credentials the origin must be echoed, so the response varies
+	anyOrigin := slices.Contains(policy.AllowedOrigins, "*") && !policy.AllowCredentials
+	allowedHeaders := strings.Join(policy.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(policy.ExposedHeaders, ", ")
+	maxAge := ""
+	if policy.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(policy.MaxAge.Seconds()))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if !anyOrigin {
+				h.Add("Vary", "Origin")
+			}
+
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			allowed := MatchOrigin(policy.AllowedOrigins, origin)
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+
+				methods := routeMethods(r)
+				if len(methods) == 0 {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				// A disallowed origin gets no CORS headers, so the browser
+				// blocks the actual request
+				if allowed {
+					setAllowOrigin(h, origin, anyOrigin, policy.AllowCredentials)
+					h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+					if allowedHeaders != "" {
+						h.Set("Access-Control-Allow-Headers", allowedHeaders)
+					}
+					if maxAge != "" {
+						h.Set("Access-Control-Max-Age", maxAge)
+					}
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if allowed {
+				setAllowOrigin(h, origin, anyOrigin, policy.AllowCredentials)
+				if exposedHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setAllowOrigin(h http.Header, origin string, anyOrigin, credentials bool) {
+	if anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// routeMethods returns the methods routed for the request path on the chi
+// router serving r
+func routeMethods(r *http.Request) []string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return nil
+	}
+
+	path := routingPath(r)
+	var methods []string
+	for _, method := range corsMethods {
+		if rctx.Routes.Match(chi.NewRouteContext(), method, path) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// routingPath returns the path chi routes the request by
+func routingPath(r *http.Request) string {
+	if r.URL.RawPath != "" {
+		return r.URL.RawPath
+	}
+	return r.URL.Path
+}
+
+// RequireOrigin rejects browser requests whose Origin header matches none of
+// patterns with 403 Forbidden. Requests without an Origin header, such as
+// those from non-browser clients, are allowed. This defends endpoints like
+// /mcp against DNS rebinding, where a malicious page resolves its own host
+// name to a local address.
+func RequireOrigin(patterns []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if origin := r.Header.Get("Origin"); origin != "" && !MatchOrigin(patterns, origin) {
+				handlers.WriteProblem(w, r, http.StatusForbidden, handlers.CodeOriginNotAllowed,
+					"Origin "+origin+" is not allowed")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MatchOrigin reports whether origin matches any of patterns. A pattern is
+// "*", an exact origin such as https://example.com, or an origin containing
+// "*" wildcards that each match a run of characters other than "/", such as
+// https://*.example.com or http://localhost:*. Matching ignores case.
+func MatchOrigin(patterns []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range patterns {
+		if pattern == "*" || matchWildcard(strings.ToLower(pattern), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard matches s against pattern, where each "*" matches any run
+// of characters other than "/"
+func matchWildcard(pattern, s string) bool {
+	star := strings.IndexByte(pattern, '*')
+	if star < 0 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, pattern[:star]) {
+		return false
+	}
+	s, pattern = s[len(pattern[:star]):], pattern[star+1:]
+
+	// Try every length for this star, shortest first
+	for i := 0; i <= len(s); i++ {
+		if matchWildcard(pattern, s[i:]) {
+			return true
+		}
+		if i < len(s) && s[i] == '/' {
+			break
+		}
+	}
+	return false
+}
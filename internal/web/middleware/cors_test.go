@@ -0,0 +1,182 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/web/handlers"
+	"prospero/internal/web/middleware"
+)
+
+func newCORSRouter(policy middleware.CORSPolicy) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.CORS(policy))
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/works", ok)
+		r.Get("/works/{id}", ok)
+	})
+	r.Get("/mcp", ok)
+	r.Post("/mcp", ok)
+	return r
+}
+
+func preflight(path, origin, method string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	return req
+}
+
+func TestCORS(t *testing.T) {
+	policy := middleware.CORSPolicy{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"ETag"},
+		MaxAge:         10 * time.Minute,
+	}
+
+	t.Run("should answer preflights with the methods routed for the path", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, preflight("/api/works/hamlet", "https://app.example.com", "GET"))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+		assert.Contains(t, w.Header().Values("Vary"), "Access-Control-Request-Method")
+
+		w = httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, preflight("/mcp", "https://app.example.com", "POST"))
+		assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("should omit CORS headers for disallowed origins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, preflight("/api/works", "https://evil.test", "GET"))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("should let preflights for unknown paths fall through", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, preflight("/nowhere", "https://app.example.com", "GET"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should route OPTIONS requests that are not preflights", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/works", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("should echo allowed origins and expose headers on simple requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/works", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		newCORSRouter(policy).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("should send a wildcard without Vary when any origin is allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/works", nil)
+		req.Header.Set("Origin", "https://anywhere.test")
+		w := httptest.NewRecorder()
+		newCORSRouter(middleware.CORSPolicy{AllowedOrigins: []string{"*"}}).ServeHTTP(w, req)
+
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+
+	t.Run("should echo the origin and allow credentials when configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/works", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		newCORSRouter(middleware.CORSPolicy{
+			AllowedOrigins:   []string{"https://app.example.com"},
+			AllowCredentials: true,
+		}).ServeHTTP(w, req)
+
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
+
+func TestRequireOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	guard := middleware.RequireOrigin([]string{"http://localhost:*"})(next)
+
+	t.Run("should allow requests without an Origin header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should allow matching origins", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Origin", "http://localhost:6274")
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should reject other origins with a problem response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Origin", "http://rebind.attacker.test")
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, handlers.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), handlers.CodeOriginNotAllowed)
+	})
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{pattern: "*", origin: "https://anything.test", want: true},
+		{pattern: "https://example.com", origin: "https://example.com", want: true},
+		{pattern: "https://example.com", origin: "HTTPS://EXAMPLE.COM", want: true},
+		{pattern: "https://example.com", origin: "http://example.com", want: false},
+		{pattern: "https://*.example.com", origin: "https://api.example.com", want: true},
+		{pattern: "https://*.example.com", origin: "https://a.b.example.com", want: true},
+		{pattern: "https://*.example.com", origin: "https://example.com", want: false},
+		{pattern: "https://*.example.com", origin: "https://example.com.evil.test", want: false},
+		{pattern: "http://localhost:*", origin: "http://localhost:8080", want: true},
+		{pattern: "http://localhost:*", origin: "http://localhost", want: false},
+		{pattern: "*://[::1]:*", origin: "http://[::1]:3000", want: true},
+		{pattern: "*://localhost", origin: "https://evil.test/://localhost", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run("should match "+test.origin+" against "+test.pattern+" correctly", func(t *testing.T) {
+			assert.Equal(t, test.want, middleware.MatchOrigin([]string{test.pattern}, test.origin))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Deprecated marks responses from legacy routes with the Deprecation header
+// and a Link to the successor route. The successor path is derived by
+// replacing the oldPrefix of the request path with newPrefix.
+func Deprecated(oldPrefix, newPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			successor := newPrefix + strings.TrimPrefix(r.URL.Path, oldPrefix)
+			w.Header().Set("Deprecation", "true")
+			w.Header().Add("Link", "<"+successor+">; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/web/middleware"
+)
+
+func TestDeprecated(t *testing.T) {
+	t.Run("should set Deprecation and successor Link headers", func(t *testing.T) {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/shakespert/works/hamlet", nil)
+		w := httptest.NewRecorder()
+
+		middleware.Deprecated("/api", "/api/v1")(next).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Equal(t, `</api/v1/shakespert/works/hamlet>; rel="successor-version"`, w.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"prospero/internal/logging"
+)
+
+// RequestLogger logs one structured line per request and attaches the
+// request ID from chi's RequestID middleware to the request context, so any
+// slog.*Context call made while handling the request includes it. It must be
+// installed after middleware.RequestID.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if reqID := chimiddleware.GetReqID(ctx); reqID != "" {
+			ctx = logging.WithAttrs(ctx, slog.String("request_id", reqID))
+			r = r.WithContext(ctx)
+		}
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+
+		defer func() {
+			scheme := "http"
+			if r.TLS != nil {
+				scheme = "https"
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			slog.InfoContext(ctx, "http request",
+				slog.String("method", r.Method),
+				slog.String("url", scheme+"://"+r.Host+r.RequestURI),
+				slog.String("proto", r.Proto),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+			)
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
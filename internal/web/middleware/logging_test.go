@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"prospero/internal/logging"
+	"prospero/internal/web/middleware"
+)
+
+func TestRequestLogger(t *testing.T) {
+	t.Run("should include the request ID in handler and access log lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.Options{Format: "json", Level: "info"})
+		require.NoError(t, err)
+
+		previous := slog.Default()
+		slog.SetDefault(logger)
+		defer slog.SetDefault(previous)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			slog.InfoContext(r.Context(), "inside handler")
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topten", nil)
+		req.Header.Set("X-Request-Id", "req-42")
+		w := httptest.NewRecorder()
+
+		chimiddleware.RequestID(middleware.RequestLogger(handler)).ServeHTTP(w, req)
+
+		decoder := json.NewDecoder(&buf)
+		var records []map[string]any
+		for decoder.More() {
+			var record map[string]any
+			require.NoError(t, decoder.Decode(&record))
+			records = append(records, record)
+		}
+
+		require.Len(t, records, 2)
+		assert.Equal(t, "inside handler", records[0]["msg"])
+		assert.Equal(t, "req-42", records[0]["request_id"])
+
+		assert.Equal(t, "http request", records[1]["msg"])
+		assert.Equal(t, "req-42", records[1]["request_id"])
+		assert.Equal(t, float64(http.StatusCreated), records[1]["status"])
+		assert.Equal(t, "GET", records[1]["method"])
+	})
+}
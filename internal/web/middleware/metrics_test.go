@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/metrics"
+	"prospero/internal/web/middleware"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("should record requests by route pattern and status", func(t *testing.T) {
+		r := chi.NewRouter()
+		r.Use(middleware.Metrics)
+		r.Get("/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		for _, id := range []string{"a", "b"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics-test/"+id, nil))
+			assert.Equal(t, http.StatusTeapot, w.Code)
+		}
+
+		w := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		body := w.Body.String()
+		assert.Contains(t, body, `prospero_http_requests_total{method="GET",route="/metrics-test/{id}",status="418"} 2`)
+		assert.Contains(t, body, `prospero_http_request_duration_seconds_count{method="GET",route="/metrics-test/{id}",status="418"} 2`)
+		assert.Contains(t, body, "go_goroutines")
+		assert.NotContains(t, body, "/metrics-test/a", "raw paths must not be used as labels")
+	})
+
+	t.Run("should label non-standard methods as OTHER", func(t *testing.T) {
+		r := chi.NewRouter()
+		r.Use(middleware.Metrics)
+		r.Get("/metrics-test-methods", func(w http.ResponseWriter, r *http.Request) {})
+
+		for _, method := range []string{"BREW", "PROPFIND", "get"} {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/metrics-test-methods", nil))
+		}
+
+		w := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		body := w.Body.String()
+		assert.Contains(t, body, `prospero_http_requests_total{method="OTHER",route="unmatched",status="405"} 3`)
+		assert.NotContains(t, body, `method="BREW"`)
+		assert.NotContains(t, body, `method="get"`)
+	})
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"prospero/internal/metrics"
+	"prospero/internal/ratelimit"
+	"prospero/internal/web/handlers"
+)
+
+// Budget names for buckets not tied to a route pattern
+const (
+	// defaultBudget is the bucket of routes without a budget of their own
+	defaultBudget = "default"
+
+	// clientBudget is the bucket of clients with a budget of their own
+	clientBudget = "client"
+)
+
+// RateLimitPolicy describes the budgets applied to requests
+type RateLimitPolicy struct {
+	Store ratelimit.Store
+
+	// Default applies to routes not listed in Routes
+	Default ratelimit.Limit
+
+	// Routes maps chi route patterns, such as /api/v1/topten, to their own
+	// budgets
+	Routes map[string]ratelimit.Limit
+
+	// Key identifies the client. It defaults to the client address, which
+	// the RealIP middleware derives from proxy headers.
+	Key func(r *http.Request) string
+
+	// Client optionally returns a budget of the client's own, such as the
+	// limit of an API key, which replaces the default and route budgets
+	Client func(r *http.Request) (ratelimit.Limit, bool)
+}
+
+// RateLimit limits requests per client with token buckets. Every response
+// carries RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers; requests over budget are answered with 429 and
+// Retry-After without invoking the handler.
+func RateLimit(policy RateLimitPolicy) func(http.Handler) http.Handler {
+	defaultLimiter := ratelimit.NewLimiter(policy.Store, policy.Default)
+	routeLimiters := make(map[string]*ratelimit.Limiter, len(policy.Routes))
+	for pattern, limit := range policy.Routes {
+		routeLimiters[pattern] = ratelimit.NewLimiter(policy.Store, limit)
+	}
+
+	key := policy.Key
+	if key == nil {
+		key = func(r *http.Request) string {
+			return ratelimit.ClientAddr(r.RemoteAddr)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			budget, limiter := defaultBudget, defaultLimiter
+			if pattern := routePattern(r); pattern != "" {
+				if routeLimiter, ok := routeLimiters[pattern]; ok {
+					budget, limiter = pattern, routeLimiter
+				}
+			}
+			if policy.Client != nil {
+				if limit, ok := policy.Client(r); ok {
+					budget, limiter = clientBudget, ratelimit.NewLimiter(policy.Store, limit)
+				}
+			}
+			if !limiter.Limit().Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result := limiter.Allow(budget + " " + key(r))
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+			h.Set("RateLimit-Policy", limiter.Limit().Policy())
+
+			if !result.Allowed {
+				metrics.IncRateLimited("http", budget)
+				h.Set("Retry-After", ceilSeconds(result.RetryAfter))
+				handlers.WriteProblem(w, r, http.StatusTooManyRequests, handlers.CodeRateLimited,
+					"Rate limit exceeded, retry in "+ceilSeconds(result.RetryAfter)+" seconds")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routePattern returns the chi route pattern the request will be routed to,
+// or "" when none matches
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	return rctx.Routes.Find(chi.NewRouteContext(), r.Method, routingPath(r))
+}
+
+// ceilSeconds formats d as whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,130 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/ratelimit"
+	"prospero/internal/web/middleware"
+)
+
+func newRateLimitRouter(policy middleware.RateLimitPolicy) *chi.Mux {
+	r := chi.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Route("/api", func(r chi.Router) {
+		r.Use(middleware.RateLimit(policy))
+		r.Get("/works", ok)
+		r.Get("/topten", ok)
+	})
+	return r
+}
+
+func get(r http.Handler, path, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	policy := func() middleware.RateLimitPolicy {
+		return middleware.RateLimitPolicy{
+			Store:   ratelimit.NewMemoryStore(),
+			Default: ratelimit.Limit{Requests: 3, Period: time.Minute},
+			Routes: map[string]ratelimit.Limit{
+				"/api/topten": {Requests: 1, Period: time.Minute},
+			},
+		}
+	}
+
+	t.Run("should set RateLimit headers on allowed responses", func(t *testing.T) {
+		w := get(newRateLimitRouter(policy()), "/api/works", "192.0.2.1:1234")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "20", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "3;w=60;burst=3", w.Header().Get("RateLimit-Policy"))
+	})
+
+	t.Run("should answer 429 with Retry-After once the budget is spent", func(t *testing.T) {
+		r := newRateLimitRouter(policy())
+		for range 3 {
+			assert.Equal(t, http.StatusOK, get(r, "/api/works", "192.0.2.1:1234").Code)
+		}
+
+		w := get(r, "/api/works", "192.0.2.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "20", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"code":"rate_limited"`)
+	})
+
+	t.Run("should key buckets by client address without the port", func(t *testing.T) {
+		r := newRateLimitRouter(policy())
+		for port := range 3 {
+			get(r, "/api/works", "192.0.2.1:"+strconv.Itoa(1000+port))
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/works", "192.0.2.1:9999").Code)
+		assert.Equal(t, http.StatusOK, get(r, "/api/works", "192.0.2.2:1234").Code)
+	})
+
+	t.Run("should apply per-route budgets separately from the default", func(t *testing.T) {
+		r := newRateLimitRouter(policy())
+
+		assert.Equal(t, http.StatusOK, get(r, "/api/topten", "192.0.2.1:1234").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(r, "/api/topten", "192.0.2.1:1234").Code)
+		assert.Equal(t, http.StatusOK, get(r, "/api/works", "192.0.2.1:1234").Code)
+	})
+
+	t.Run("should pass requests through when the limit is disabled", func(t *testing.T) {
+		p := policy()
+		p.Default = ratelimit.Limit{}
+		r := newRateLimitRouter(p)
+
+		for range 5 {
+			w := get(r, "/api/works", "192.0.2.1:1234")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("should apply a client budget in place of the route budgets", func(t *testing.T) {
+		p := policy()
+		p.Client = func(r *http.Request) (ratelimit.Limit, bool) {
+			return ratelimit.Limit{Requests: 10, Period: time.Minute}, r.Header.Get("X-Client") != ""
+		}
+		r := newRateLimitRouter(p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/topten", nil)
+		req.Header.Set("X-Client", "ci")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "10", w.Header().Get("RateLimit-Limit"))
+
+		assert.Equal(t, "1", get(r, "/api/topten", "192.0.2.1:1234").Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("should use the Key function to identify clients", func(t *testing.T) {
+		p := policy()
+		p.Key = func(r *http.Request) string { return r.Header.Get("X-Client") }
+		r := newRateLimitRouter(p)
+
+		for range 3 {
+			get(r, "/api/works", "192.0.2.1:1234")
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/works", nil)
+		req.Header.Set("X-Client", "other")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HSTSPolicy describes the Strict-Transport-Security header
+type HSTSPolicy struct {
+	MaxAge            time.Duration
+	IncludeSubdomains bool
+	Preload           bool
+}
+
+// HSTS sets Strict-Transport-Security on responses to HTTPS requests.
+// Browsers ignore the header over plain HTTP, so it is not sent there.
+func HSTS(policy HSTSPolicy) func(http.Handler) http.Handler {
+	value := "max-age=" + strconv.Itoa(int(policy.MaxAge.Seconds()))
+	if policy.IncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if policy.Preload {
+		value += "; preload"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil {
+				w.Header().Set("Strict-Transport-Security", value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RedirectHTTPS answers every request with a permanent redirect to the same
+// URL over HTTPS on httpsPort. 308 is used so clients repeat the method and
+// body, which matters for POST /mcp.
+func RedirectHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// No port, but IPv6 literals are still bracketed
+			host = strings.Trim(r.Host, "[]")
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
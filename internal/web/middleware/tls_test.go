@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"prospero/internal/web/middleware"
+)
+
+func TestHSTS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("should set the header on HTTPS requests", func(t *testing.T) {
+		handler := middleware.HSTS(middleware.HSTSPolicy{
+			MaxAge:            365 * 24 * time.Hour,
+			IncludeSubdomains: true,
+			Preload:           true,
+		})(ok)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.TLS = &tls.ConnectionState{}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, "max-age=31536000; includeSubDomains; preload", w.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("should not set the header over plain HTTP", func(t *testing.T) {
+		handler := middleware.HSTS(middleware.HSTSPolicy{MaxAge: time.Hour})(ok)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+	})
+}
+
+func TestRedirectHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		httpsPort string
+		want      string
+	}{
+		{"should swap the port", "example.com:8081", "8443", "https://example.com:8443/api/v1/works?genre=t"},
+		{"should omit the default port", "example.com:80", "443", "https://example.com/api/v1/works?genre=t"},
+		{"should add a port to bare hosts", "example.com", "8443", "https://example.com:8443/api/v1/works?genre=t"},
+		{"should keep IPv6 literals bracketed", "[::1]:8081", "443", "https://[::1]/api/v1/works?genre=t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/works?genre=t", nil)
+			req.Host = tt.host
+			w := httptest.NewRecorder()
+			middleware.RedirectHTTPS(tt.httpsPort).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Location"))
+		})
+	}
+}